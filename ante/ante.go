@@ -8,15 +8,19 @@ import (
 	ibckeeper "github.com/Finschia/ibc-go/v3/modules/core/keeper"
 	wasmkeeper "github.com/Finschia/wasmd/x/wasm/keeper"
 	wasmtypes "github.com/Finschia/wasmd/x/wasm/types"
+
+	blocklistante "github.com/Finschia/finschia/x/blocklist/ante"
+	blocklistkeeper "github.com/Finschia/finschia/x/blocklist/keeper"
 )
 
 // HandlerOptions extend the SDK's AnteHandler options by requiring the IBC
-// channel keeper.
+// channel keeper and the blocklist keeper.
 type HandlerOptions struct {
 	ante.HandlerOptions
 
-	IBCkeeper  *ibckeeper.Keeper
-	WasmConfig *wasmtypes.WasmConfig
+	IBCkeeper       *ibckeeper.Keeper
+	WasmConfig      *wasmtypes.WasmConfig
+	BlocklistKeeper *blocklistkeeper.Keeper
 }
 
 func NewAnteHandler(opts HandlerOptions) (sdk.AnteHandler, error) {
@@ -32,6 +36,9 @@ func NewAnteHandler(opts HandlerOptions) (sdk.AnteHandler, error) {
 	if opts.WasmConfig == nil {
 		return nil, sdkerrors.Wrap(sdkerrors.ErrLogic, "wasm config is required for ante builder")
 	}
+	if opts.BlocklistKeeper == nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrLogic, "blocklist keeper is required for AnteHandler")
+	}
 
 	sigGasConsumer := opts.SigGasConsumer
 	if sigGasConsumer == nil {
@@ -44,6 +51,7 @@ func NewAnteHandler(opts HandlerOptions) (sdk.AnteHandler, error) {
 		ante.NewRejectExtensionOptionsDecorator(),
 		ante.NewMempoolFeeDecorator(),
 		ante.NewValidateBasicDecorator(),
+		blocklistante.NewBlockedAddressDecorator(*opts.BlocklistKeeper),
 		ante.NewTxTimeoutHeightDecorator(),
 		ante.NewValidateMemoDecorator(opts.AccountKeeper),
 		ante.NewConsumeGasForTxSizeDecorator(opts.AccountKeeper),
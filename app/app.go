@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/gorilla/mux"
 	"github.com/rakyll/statik/fs"
@@ -29,7 +31,6 @@ import (
 	"github.com/Finschia/finschia-sdk/server/config"
 	servertypes "github.com/Finschia/finschia-sdk/server/types"
 	"github.com/Finschia/finschia-sdk/simapp"
-	"github.com/Finschia/finschia-sdk/store/streaming"
 	sdk "github.com/Finschia/finschia-sdk/types"
 	"github.com/Finschia/finschia-sdk/types/module"
 	"github.com/Finschia/finschia-sdk/version"
@@ -47,7 +48,6 @@ import (
 	authzmodule "github.com/Finschia/finschia-sdk/x/authz/module"
 	"github.com/Finschia/finschia-sdk/x/bank"
 	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
-	"github.com/Finschia/finschia-sdk/x/bankplus"
 	bankpluskeeper "github.com/Finschia/finschia-sdk/x/bankplus/keeper"
 	"github.com/Finschia/finschia-sdk/x/capability"
 	capabilitykeeper "github.com/Finschia/finschia-sdk/x/capability/keeper"
@@ -123,7 +123,15 @@ import (
 	wasmplustypes "github.com/Finschia/wasmd/x/wasmplus/types"
 
 	appante "github.com/Finschia/finschia/ante"
+	"github.com/Finschia/finschia/app/history"
+	"github.com/Finschia/finschia/app/indexer"
+	"github.com/Finschia/finschia/app/invariants"
 	appparams "github.com/Finschia/finschia/app/params"
+	"github.com/Finschia/finschia/app/streaming"
+	"github.com/Finschia/finschia/app/zeroheight"
+	"github.com/Finschia/finschia/x/blocklist"
+	blocklistkeeper "github.com/Finschia/finschia/x/blocklist/keeper"
+	blocklisttypes "github.com/Finschia/finschia/x/blocklist/types"
 
 	// unnamed import of statik for swagger UI support
 	_ "github.com/Finschia/finschia-sdk/client/docs/statik"
@@ -172,6 +180,7 @@ var (
 		transfer.AppModuleBasic{},
 		ica.AppModuleBasic{},
 		wasmplus.AppModuleBasic{},
+		blocklist.AppModuleBasic{},
 	)
 
 	// module account permissions
@@ -188,11 +197,6 @@ var (
 		ibctransfertypes.ModuleName:    {authtypes.Minter, authtypes.Burner},
 		wasmplustypes.ModuleName:       {authtypes.Burner},
 	}
-
-	// module accounts that are allowed to receive tokens
-	allowedReceivingModAcc = map[string]bool{
-		// govtypes.ModuleName: true, // TODO: uncomment it when authority is ready
-	}
 )
 
 var (
@@ -210,6 +214,11 @@ type LinkApp struct { // nolint: golint
 	interfaceRegistry types.InterfaceRegistry
 
 	invCheckPeriod uint
+	homePath       string
+	genesisFile    string
+
+	// schedule of the invariants asserted in EndBlocker, each at its period
+	invariantSchedule invariants.Schedule
 
 	// keys to access the substores
 	keys    map[string]*sdk.KVStoreKey
@@ -217,7 +226,7 @@ type LinkApp struct { // nolint: golint
 
 	// keepers
 	AccountKeeper    authkeeper.AccountKeeper
-	BankKeeper       bankpluskeeper.Keeper
+	BankKeeper       blocklistkeeper.BankKeeper
 	CapabilityKeeper *capabilitykeeper.Keeper
 	StakingKeeper    stakingkeeper.Keeper
 	SlashingKeeper   slashingkeeper.Keeper
@@ -239,6 +248,7 @@ type LinkApp struct { // nolint: golint
 	TokenKeeper      tokenkeeper.Keeper
 	CollectionKeeper collectionkeeper.Keeper
 	WasmKeeper       wasmpluskeeper.Keeper
+	BlocklistKeeper  blocklistkeeper.Keeper
 
 	ScopedIBCKeeper      capabilitykeeper.ScopedKeeper
 	ScopedTransferKeeper capabilitykeeper.ScopedKeeper
@@ -253,6 +263,20 @@ type LinkApp struct { // nolint: golint
 
 	// the configurator
 	configurator module.Configurator
+
+	// streaming services of the state changes, closed on Close
+	streamingServices []streaming.Service
+	streamingWG       *sync.WaitGroup
+	// whether the blocks are streamed before their commit, which a failure to
+	// stream them aborts
+	streamBeforeCommit bool
+
+	// indexer of the blocks if enabled, served over REST
+	indexer *indexer.Indexer
+
+	// history of the transfers of token and collection if enabled, served
+	// over gRPC and REST
+	historyServer history.QueryServer
 }
 
 func init() {
@@ -307,20 +331,32 @@ func NewLinkApp(
 	// not include this key.
 	memKeys := sdk.NewMemoryStoreKeys(capabilitytypes.MemStoreKey)
 
-	// configure state listening capabilities using AppOptions
-	// we are doing nothing with the returned streamingServices and waitGroup in this case
-	if _, _, err := streaming.LoadStreamingServices(bApp, appOpts, appCodec, keys); err != nil {
+	svcs, err := loadServices(bApp, appOpts, keys, encodingConfig, appCodec)
+	if err != nil {
+		ostos.Exit(err.Error())
+	}
+
+	invariantSchedule, err := invariants.ScheduleFromOptions(appOpts, invCheckPeriod)
+	if err != nil {
 		ostos.Exit(err.Error())
 	}
 
 	app := &LinkApp{
-		BaseApp:           bApp,
-		legacyAmino:       legacyAmino,
-		appCodec:          appCodec,
-		interfaceRegistry: interfaceRegistry,
-		invCheckPeriod:    invCheckPeriod,
-		keys:              keys,
-		memKeys:           memKeys,
+		BaseApp:            bApp,
+		legacyAmino:        legacyAmino,
+		appCodec:           appCodec,
+		interfaceRegistry:  interfaceRegistry,
+		streamingServices:  svcs.streaming,
+		streamingWG:        svcs.wg,
+		streamBeforeCommit: svcs.streamBeforeCommit,
+		indexer:            svcs.indexer,
+		historyServer:      svcs.history,
+		invCheckPeriod:     invCheckPeriod,
+		invariantSchedule:  invariantSchedule,
+		homePath:           homePath,
+		genesisFile:        genesisFilePath(homePath, appOpts),
+		keys:               keys,
+		memKeys:            memKeys,
 	}
 
 	app.ParamsKeeper = initParamsKeeper(appCodec, legacyAmino, keys[paramstypes.StoreKey], tkeys[paramstypes.TStoreKey])
@@ -342,8 +378,14 @@ func NewLinkApp(
 	app.AccountKeeper = authkeeper.NewAccountKeeper(
 		appCodec, keys[authtypes.StoreKey], app.GetSubspace(authtypes.ModuleName), authtypes.ProtoBaseAccount, maccPerms,
 	)
-	app.BankKeeper = bankpluskeeper.NewBaseKeeper(
-		appCodec, keys[banktypes.StoreKey], app.AccountKeeper, app.GetSubspace(banktypes.ModuleName), app.BlockedAddrs(), true,
+	// the module accounts allowed to receive funds are the params of blocklist,
+	// so that the blocklist keeper tells the blocked addresses to bankplus
+	app.BlocklistKeeper = blocklistkeeper.NewKeeper(app.GetSubspace(blocklisttypes.ModuleName), moduleAccountNames(), foundation.DefaultAuthority().String())
+	app.BankKeeper = blocklistkeeper.NewBankKeeper(
+		bankpluskeeper.NewBaseKeeper(
+			appCodec, keys[banktypes.StoreKey], app.AccountKeeper, app.GetSubspace(banktypes.ModuleName), map[string]bool{}, true,
+		),
+		app.BlocklistKeeper,
 	)
 	stakingKeeper := stakingkeeper.NewKeeper(
 		appCodec, keys[stakingtypes.StoreKey], app.AccountKeeper, app.BankKeeper, app.GetSubspace(stakingtypes.ModuleName),
@@ -359,8 +401,10 @@ func NewLinkApp(
 	app.SlashingKeeper = slashingkeeper.NewKeeper(
 		appCodec, keys[slashingtypes.StoreKey], &stakingKeeper, app.GetSubspace(slashingtypes.ModuleName),
 	)
+	// the invariants are asserted by the schedule in EndBlocker, each at its
+	// own period, instead of crisis asserting all of them at invCheckPeriod
 	app.CrisisKeeper = crisiskeeper.NewKeeper(
-		app.GetSubspace(crisistypes.ModuleName), invCheckPeriod, app.BankKeeper, authtypes.FeeCollectorName,
+		app.GetSubspace(crisistypes.ModuleName), 0, app.BankKeeper, authtypes.FeeCollectorName,
 	)
 	app.FeeGrantKeeper = feegrantkeeper.NewKeeper(appCodec, keys[feegrant.StoreKey], app.AccountKeeper)
 	app.UpgradeKeeper = upgradekeeper.NewKeeper(skipUpgradeHeights, keys[upgradetypes.StoreKey], appCodec, homePath, app.BaseApp)
@@ -374,7 +418,7 @@ func NewLinkApp(
 	// register the staking hooks
 	// NOTE: stakingKeeper above is passed by reference, so that it will contain these hooks
 	app.StakingKeeper = *stakingKeeper.SetHooks(
-		stakingtypes.NewMultiStakingHooks(app.DistrKeeper.Hooks(), app.SlashingKeeper.Hooks()),
+		stakingtypes.NewMultiStakingHooks(blocklistkeeper.NewDistrHooks(app.DistrKeeper, app.BlocklistKeeper), app.SlashingKeeper.Hooks()),
 	)
 
 	// Create Authz Keeper
@@ -462,15 +506,15 @@ func NewLinkApp(
 	// register the proposal types
 	govRouter := govtypes.NewRouter()
 	govRouter.AddRoute(govtypes.RouterKey, govtypes.ProposalHandler).
-		AddRoute(paramproposal.RouterKey, params.NewParamChangeProposalHandler(app.ParamsKeeper)).
+		AddRoute(paramproposal.RouterKey, blocklist.NewParamChangeProposalHandler(params.NewParamChangeProposalHandler(app.ParamsKeeper), app.BlocklistKeeper)).
 		AddRoute(distrtypes.RouterKey, distr.NewCommunityPoolSpendProposalHandler(app.DistrKeeper)).
 		AddRoute(upgradetypes.RouterKey, upgrade.NewSoftwareUpgradeProposalHandler(app.UpgradeKeeper)).
 		AddRoute(ibcclienttypes.RouterKey, ibcclient.NewClientProposalHandler(app.IBCKeeper.ClientKeeper)).
 		AddRoute(wasmplustypes.RouterKey, wasmpluskeeper.NewWasmProposalHandler(&app.WasmKeeper, wasmplustypes.EnableAllProposals))
 
 	govKeeper := govkeeper.NewKeeper(
-		appCodec, keys[govtypes.StoreKey], app.GetSubspace(govtypes.ModuleName), app.AccountKeeper, app.BankKeeper,
-		&stakingKeeper, govRouter,
+		appCodec, keys[govtypes.StoreKey], app.GetSubspace(govtypes.ModuleName), app.AccountKeeper,
+		blocklistkeeper.NewGovBankKeeper(app.BankKeeper, app.DistrKeeper), &stakingKeeper, govRouter,
 	)
 	app.GovKeeper = *govKeeper.SetHooks(
 		govtypes.NewMultiGovHooks(
@@ -499,26 +543,27 @@ func NewLinkApp(
 		),
 		auth.NewAppModule(appCodec, app.AccountKeeper, nil),
 		vesting.NewAppModule(app.AccountKeeper, app.BankKeeper),
-		bankplus.NewAppModule(appCodec, app.BankKeeper, app.AccountKeeper),
+		blocklist.NewBankPlusAppModule(appCodec, app.BankKeeper, app.AccountKeeper),
 		capability.NewAppModule(appCodec, *app.CapabilityKeeper),
 		crisis.NewAppModule(&app.CrisisKeeper, skipGenesisInvariants),
 		feegrantmodule.NewAppModule(appCodec, app.AccountKeeper, app.BankKeeper, app.FeeGrantKeeper, app.interfaceRegistry),
-		foundationmodule.NewAppModule(appCodec, app.FoundationKeeper),
+		zeroheight.NewFoundationAppModule(appCodec, app.FoundationKeeper, foundationConfig),
 		gov.NewAppModule(appCodec, app.GovKeeper, app.AccountKeeper, app.BankKeeper),
 		mint.NewAppModule(appCodec, app.MintKeeper, app.AccountKeeper),
 		slashing.NewAppModule(appCodec, app.SlashingKeeper, app.AccountKeeper, app.BankKeeper, app.StakingKeeper),
 		distr.NewAppModule(appCodec, app.DistrKeeper, app.AccountKeeper, app.BankKeeper, app.StakingKeeper),
 		stakingplusmodule.NewAppModule(appCodec, app.StakingKeeper, app.AccountKeeper, app.BankKeeper, app.FoundationKeeper),
 		upgrade.NewAppModule(app.UpgradeKeeper),
-		wasmplus.NewAppModule(appCodec, &app.WasmKeeper, app.StakingKeeper, app.AccountKeeper, app.BankKeeper),
+		zeroheight.NewWasmPlusAppModule(appCodec, &app.WasmKeeper, app.StakingKeeper, app.AccountKeeper, app.BankKeeper),
 		evidence.NewAppModule(app.EvidenceKeeper),
 		params.NewAppModule(app.ParamsKeeper),
-		tokenmodule.NewAppModule(appCodec, app.TokenKeeper),
-		collectionmodule.NewAppModule(appCodec, app.CollectionKeeper),
+		blocklist.NewTokenAppModule(appCodec, app.TokenKeeper, app.BlocklistKeeper),
+		blocklist.NewCollectionAppModule(appCodec, app.CollectionKeeper, app.BlocklistKeeper),
 		authzmodule.NewAppModule(appCodec, app.AuthzKeeper, app.AccountKeeper, app.BankKeeper, app.interfaceRegistry),
 		ibc.NewAppModule(app.IBCKeeper),
 		transferModule,
 		icaModule,
+		blocklist.NewAppModule(app.BlocklistKeeper),
 	)
 
 	// During begin block slashing happens after distr.BeginBlocker so that
@@ -527,6 +572,7 @@ func NewLinkApp(
 	// NOTE: staking module is required if HistoricalEntries param > 0
 	app.mm.SetOrderBeginBlockers(
 		upgradetypes.ModuleName,
+		blocklisttypes.ModuleName,
 		capabilitytypes.ModuleName,
 		minttypes.ModuleName,
 		foundation.ModuleName,
@@ -574,6 +620,7 @@ func NewLinkApp(
 		ibchost.ModuleName,
 		icatypes.ModuleName,
 		wasmplustypes.ModuleName,
+		blocklisttypes.ModuleName,
 	)
 
 	// NOTE: The genutils module must occur after staking so that pools are
@@ -587,6 +634,7 @@ func NewLinkApp(
 	// NOTE: capability module's beginblocker must come before any modules using capabilities (e.g. IBC)
 	app.mm.SetOrderInitGenesis(
 		capabilitytypes.ModuleName,
+		blocklisttypes.ModuleName,
 		authtypes.ModuleName,
 		banktypes.ModuleName,
 		distrtypes.ModuleName,
@@ -595,7 +643,6 @@ func NewLinkApp(
 		govtypes.ModuleName,
 		minttypes.ModuleName,
 		foundation.ModuleName,
-		crisistypes.ModuleName,
 		genutiltypes.ModuleName,
 		evidencetypes.ModuleName,
 		authz.ModuleName,
@@ -610,12 +657,21 @@ func NewLinkApp(
 		icatypes.ModuleName,
 		// wasm after ibc transfer
 		wasmplustypes.ModuleName,
+		// crisis last, as its InitGenesis asserts the invariants, and those of
+		// token and collection read the state their InitGenesis sets, e.g. the
+		// class nonce, panicking before it
+		crisistypes.ModuleName,
 	)
 
 	app.mm.RegisterInvariants(&app.CrisisKeeper)
+	invariants.RegisterInvariants(&app.CrisisKeeper, app.TokenKeeper, app.CollectionKeeper, app.BankKeeper, app.WasmKeeper, keys[banktypes.StoreKey], appCodec)
 	app.mm.RegisterRoutes(app.Router(), app.QueryRouter(), encodingConfig.Amino)
 	app.configurator = module.NewConfigurator(app.appCodec, app.MsgServiceRouter(), app.GRPCQueryRouter())
 	app.mm.RegisterServices(app.configurator)
+	app.setUpgradeHandlers()
+	if app.historyServer != nil {
+		history.RegisterQueryServer(app.GRPCQueryRouter(), app.historyServer)
+	}
 
 	// create the simulation manager and define the order of the modules for deterministic simulations
 	//
@@ -646,8 +702,9 @@ func NewLinkApp(
 				FeegrantKeeper:  app.FeeGrantKeeper,
 				SigGasConsumer:  ante.DefaultSigVerificationGasConsumer,
 			},
-			IBCkeeper:  app.IBCKeeper,
-			WasmConfig: &wasmConfig,
+			IBCkeeper:       app.IBCKeeper,
+			WasmConfig:      &wasmConfig,
+			BlocklistKeeper: &app.BlocklistKeeper,
 		},
 	)
 	if err != nil {
@@ -682,6 +739,9 @@ func NewLinkApp(
 
 		// Initialize the keeper of bankkeeper
 		app.BankKeeper.InitializeBankPlus(ctx)
+
+		// Load the blocked addresses of the params
+		app.BlocklistKeeper.Refresh(ctx)
 	}
 
 	app.ScopedIBCKeeper = scopedIBCKeeper
@@ -709,6 +769,8 @@ func (app *LinkApp) BeginBlocker(ctx sdk.Context, req ocabci.RequestBeginBlock)
 
 // EndBlocker application updates every end block
 func (app *LinkApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	// asserted first as crisis was, which comes first in the end blockers
+	app.invariantSchedule.AssertInvariants(ctx, app.CrisisKeeper)
 	return app.mm.EndBlock(ctx, req)
 }
 
@@ -719,12 +781,125 @@ func (app *LinkApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci
 		panic(err)
 	}
 	app.UpgradeKeeper.SetModuleVersionMap(ctx, app.mm.GetVersionMap())
-	return app.mm.InitGenesis(ctx, app.appCodec, genesisState)
+
+	// genesis states of modules referenced by file are resolved against the
+	// directory of the genesis file, as validate-genesis does
+	res, err := app.initGenesis(ctx, genesisState, NewGenesisFileResolver(app.genesisFile))
+	if err != nil {
+		panic(err)
+	}
+	return res
 }
 
-// LoadHeight loads a particular height
+// Commit commits the block, streaming its state changes collected from the
+// flush of the deliver state by the streaming services. The block is streamed
+// once committed, as the listeners of the SDK are notified of the commit, or
+// before the commit if the services must not lose it, so that a failure to
+// stream it stops the node with the height uncommitted, which is executed
+// again on restart.
+func (app *LinkApp) Commit() abci.ResponseCommit {
+	if len(app.streamingServices) == 0 {
+		return app.BaseApp.Commit()
+	}
+
+	for _, service := range app.streamingServices {
+		service.ListenStartCommit()
+	}
+
+	if app.streamBeforeCommit {
+		// the deliver state is flushed here for the services to collect the
+		// change set of the block, and the flush in BaseApp.Commit is a no-op.
+		deliverState := app.BaseApp.NewContext(false, tmproto.Header{}).MultiStore()
+		ms, ok := deliverState.(sdk.CacheMultiStore)
+		if !ok {
+			panic(fmt.Errorf("unexpected deliver state of %T", deliverState))
+		}
+		ms.Write()
+		if err := app.listenCommit(); err != nil {
+			panic(fmt.Errorf("failed to stream the block, aborting the commit: %w", err))
+		}
+		return app.BaseApp.Commit()
+	}
+
+	res := app.BaseApp.Commit()
+	if err := app.listenCommit(); err != nil {
+		app.Logger().Error("failed to stream the block", "err", err)
+	}
+	return res
+}
+
+// listenCommit notifies the streaming services of the commit, returning the
+// first error of them.
+func (app *LinkApp) listenCommit() error {
+	var firstErr error
+	for _, service := range app.streamingServices {
+		if err := service.ListenCommit(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close closes the streaming services, waiting for them to finish.
+func (app *LinkApp) Close() error {
+	var firstErr error
+	for _, service := range app.streamingServices {
+		if err := service.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	app.streamingServices = nil
+	if app.streamingWG != nil {
+		app.streamingWG.Wait()
+	}
+
+	return firstErr
+}
+
+// LoadHeight loads a particular height, initializing the keepers caching the
+// state as loading the latest height does.
 func (app *LinkApp) LoadHeight(height int64) error {
-	return app.LoadVersion(height)
+	if err := app.LoadVersion(height); err != nil {
+		return err
+	}
+
+	ctx := app.BaseApp.NewUncachedContext(true, tmproto.Header{})
+	app.BankKeeper.InitializeBankPlus(ctx)
+	app.BlocklistKeeper.Refresh(ctx)
+	return nil
+}
+
+// CheckInvariants checks the invariants registered to crisis against the state
+// loaded, without panicking. The invariants may be selected by their full
+// routes ({module}/{route}) or their modules, and all of them are checked if
+// none are given.
+func (app *LinkApp) CheckInvariants(routes []string) ([]invariants.Result, error) {
+	selected := app.CrisisKeeper.Routes()
+	if len(routes) != 0 {
+		selected = nil
+		seen := map[string]bool{}
+		for _, name := range routes {
+			found := false
+			for _, route := range app.CrisisKeeper.Routes() {
+				if route.FullRoute() != name && route.ModuleName != name {
+					continue
+				}
+				found = true
+				if !seen[route.FullRoute()] {
+					seen[route.FullRoute()] = true
+					selected = append(selected, route)
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown invariant: %s", name)
+			}
+		}
+	}
+
+	// the invariants are checked on a branch of the committed state, as the
+	// check state is only reset on recheck.
+	ctx, _ := app.BaseApp.NewUncachedContext(true, tmproto.Header{Height: app.LastBlockHeight()}).CacheContext()
+	return invariants.Check(ctx, selected), nil
 }
 
 // ModuleAccountAddrs returns all the app's module account addresses.
@@ -738,11 +913,12 @@ func (app *LinkApp) ModuleAccountAddrs() map[string]bool {
 }
 
 // BlockedAddrs returns all the app's module account addresses that are not
-// allowed to receive external tokens.
+// allowed to receive external tokens, as of the last block.
 func (app *LinkApp) BlockedAddrs() map[string]bool {
 	blockedAddrs := make(map[string]bool)
 	for acc := range maccPerms {
-		blockedAddrs[authtypes.NewModuleAddress(acc).String()] = !allowedReceivingModAcc[acc]
+		addr := authtypes.NewModuleAddress(acc)
+		blockedAddrs[addr.String()] = app.BlocklistKeeper.BlockedAddr(addr)
 	}
 
 	return blockedAddrs
@@ -776,6 +952,12 @@ func (app *LinkApp) GetKey(storeKey string) *sdk.KVStoreKey {
 	return app.keys[storeKey]
 }
 
+// KVStoreKeys returns the keys of the persisted substores by their names, for
+// inspecting the state offline.
+func (app *LinkApp) KVStoreKeys() map[string]*sdk.KVStoreKey {
+	return app.keys
+}
+
 // GetMemKey returns the MemStoreKey for the provided mem key.
 //
 // NOTE: This is solely used for testing purposes.
@@ -813,6 +995,15 @@ func (app *LinkApp) RegisterAPIRoutes(apiSvr *api.Server, apiConfig config.APICo
 	// Register grpc-gateway routes for all modules.
 	ModuleBasics.RegisterGRPCGatewayRoutes(clientCtx, apiSvr.GRPCGatewayRouter)
 
+	if app.indexer != nil {
+		indexer.RegisterRoutes(apiSvr.Router, app.indexer)
+	}
+	if app.historyServer != nil {
+		if err := history.RegisterQueryHandlerClient(context.Background(), apiSvr.GRPCGatewayRouter, history.NewQueryClient(clientCtx)); err != nil {
+			panic(err)
+		}
+	}
+
 	// register swagger API from root so that other applications can override easily
 	if apiConfig.Swagger {
 		RegisterSwaggerAPI(apiSvr.Router)
@@ -845,6 +1036,15 @@ func RegisterSwaggerAPI(rtr *mux.Router) {
 	rtr.PathPrefix("/swagger/").Handler(http.StripPrefix("/swagger/", staticServer))
 }
 
+// moduleAccountNames returns the names of the app's module accounts
+func moduleAccountNames() []string {
+	names := make([]string, 0, len(maccPerms))
+	for acc := range maccPerms {
+		names = append(names, acc)
+	}
+	return names
+}
+
 // GetMaccPerms returns a copy of the module account permissions
 func GetMaccPerms() map[string][]string {
 	dupMaccPerms := make(map[string][]string)
@@ -870,6 +1070,7 @@ func initParamsKeeper(appCodec codec.BinaryCodec, legacyAmino *codec.LegacyAmino
 	paramsKeeper.Subspace(ibchost.ModuleName)
 	paramsKeeper.Subspace(icahosttypes.SubModuleName)
 	paramsKeeper.Subspace(wasmplustypes.ModuleName)
+	paramsKeeper.Subspace(blocklisttypes.ModuleName)
 
 	return paramsKeeper
 }
@@ -2,19 +2,27 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
+	"sync"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	ocabci "github.com/Finschia/ostracon/abci/types"
 	"github.com/Finschia/ostracon/libs/log"
 	abci "github.com/tendermint/tendermint/abci/types"
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/Finschia/finschia-sdk/baseapp"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
 	"github.com/Finschia/finschia-sdk/simapp"
+	"github.com/Finschia/finschia-sdk/store/prefix"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
 	"github.com/Finschia/finschia-sdk/tests/mocks"
 	sdk "github.com/Finschia/finschia-sdk/types"
 	"github.com/Finschia/finschia-sdk/types/module"
@@ -35,13 +43,22 @@ import (
 	"github.com/Finschia/wasmd/x/wasmplus"
 
 	"github.com/Finschia/finschia-sdk/x/mint"
+	minttypes "github.com/Finschia/finschia-sdk/x/mint/types"
 	"github.com/Finschia/finschia-sdk/x/params"
+	paramstypes "github.com/Finschia/finschia-sdk/x/params/types"
 	"github.com/Finschia/finschia-sdk/x/slashing"
 	"github.com/Finschia/finschia-sdk/x/staking"
 	tokenmodule "github.com/Finschia/finschia-sdk/x/token/module"
 	"github.com/Finschia/finschia-sdk/x/upgrade"
+	upgradetypes "github.com/Finschia/finschia-sdk/x/upgrade/types"
 	"github.com/Finschia/ibc-go/v3/modules/apps/transfer"
 	ibc "github.com/Finschia/ibc-go/v3/modules/core"
+
+	"github.com/Finschia/finschia/app/history"
+	"github.com/Finschia/finschia/app/invariants"
+	"github.com/Finschia/finschia/app/streaming"
+	"github.com/Finschia/finschia/app/testutil"
+	blocklisttypes "github.com/Finschia/finschia/x/blocklist/types"
 )
 
 func TestSimAppExportAndBlockedAddrs(t *testing.T) {
@@ -50,7 +67,7 @@ func TestSimAppExportAndBlockedAddrs(t *testing.T) {
 	app := NewLinkApp(log.NewOCLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, simapp.EmptyAppOptions{}, nil)
 
 	for acc := range maccPerms {
-		require.Equal(t, !allowedReceivingModAcc[acc], app.BankKeeper.BlockedAddr(app.AccountKeeper.GetModuleAddress(acc)),
+		require.True(t, app.BankKeeper.BlockedAddr(app.AccountKeeper.GetModuleAddress(acc)),
 			"ensure that blocked addresses are properly set in bank keeper")
 	}
 
@@ -73,6 +90,221 @@ func TestSimAppExportAndBlockedAddrs(t *testing.T) {
 	require.NoError(t, err, "ExportAppStateAndValidators should not have an error")
 }
 
+func TestStreaming(t *testing.T) {
+	encCfg := MakeEncodingConfig()
+	stateBytes, err := json.Marshal(NewDefaultGenesisState(encCfg.Marshaler))
+	require.NoError(t, err)
+
+	var dir string
+	var appOpts testutil.AppOptions
+	// the blocks are streamed after the commit, or before it with fsync
+	for _, fsync := range []bool{false, true} {
+		dir = t.TempDir()
+		appOpts = testutil.AppOptions{
+			OptServices:                   true,
+			streaming.OptStreamers:        []string{streaming.FileServiceName},
+			"streamers.file.keys":         []string{"*"},
+			streaming.OptFileWriteDir:     dir,
+			streaming.OptFileRotateBlocks: 1,
+			streaming.OptFileFsync:        fsync,
+		}
+		app := NewLinkApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, appOpts, nil)
+		require.Equal(t, fsync, app.streamBeforeCommit)
+
+		app.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+		app.Commit()
+
+		// the genesis state is committed at height 1
+		for height := int64(2); height <= 3; height++ {
+			app.BeginBlock(ocabci.RequestBeginBlock{Header: tmproto.Header{Height: height}})
+			app.EndBlock(abci.RequestEndBlock{Height: height})
+			app.Commit()
+		}
+		require.Equal(t, int64(3), app.LastBlockHeight())
+		require.NoError(t, app.Close())
+
+		var blocks []streaming.Block
+		require.NoError(t, streaming.Replay(dir, "", 0, func(block streaming.Block) error {
+			blocks = append(blocks, block)
+			return nil
+		}))
+		require.Len(t, blocks, 2)
+		for i, block := range blocks {
+			require.Equal(t, int64(i+2), block.Height)
+			require.Equal(t, block.Height, block.RequestBeginBlock.Header.Height)
+
+			// the minter is updated every block
+			stores := map[string]bool{}
+			for _, pair := range block.ChangeSet {
+				stores[pair.StoreKey] = true
+			}
+			require.True(t, stores[minttypes.StoreKey])
+		}
+	}
+
+	// the services do not run unless opted in, e.g. on export
+	files := fileSizes(t, dir)
+	appOpts[OptServices] = false
+	app := NewLinkApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, appOpts, nil)
+	app.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+	app.Commit()
+	require.NoError(t, app.Close())
+	require.Equal(t, files, fileSizes(t, dir))
+}
+
+// failingService is a streaming service failing to stream every block.
+type failingService struct{}
+
+func (failingService) Stream(_ *sync.WaitGroup) error {
+	return nil
+}
+
+func (failingService) Listeners() map[storetypes.StoreKey][]storetypes.WriteListener {
+	return nil
+}
+
+func (failingService) ListenBeginBlock(_ sdk.Context, _ ocabci.RequestBeginBlock, _ abci.ResponseBeginBlock) error {
+	return nil
+}
+
+func (failingService) ListenEndBlock(_ sdk.Context, _ abci.RequestEndBlock, _ abci.ResponseEndBlock) error {
+	return nil
+}
+
+func (failingService) ListenDeliverTx(_ sdk.Context, _ abci.RequestDeliverTx, _ abci.ResponseDeliverTx) error {
+	return nil
+}
+
+func (failingService) ListenStartCommit() {}
+
+func (failingService) ListenCommit() error {
+	return errors.New("broker down")
+}
+
+func (failingService) Close() error {
+	return nil
+}
+
+func TestStreamingStopNodeOnErr(t *testing.T) {
+	streaming.RegisterServiceConstructor("failing", func(_ servertypes.AppOptions, _ []storetypes.StoreKey) (streaming.Service, error) {
+		return failingService{}, nil
+	})
+	encCfg := MakeEncodingConfig()
+	stateBytes, err := json.Marshal(NewDefaultGenesisState(encCfg.Marshaler))
+	require.NoError(t, err)
+
+	for _, stopNodeOnErr := range []bool{false, true} {
+		appOpts := testutil.AppOptions{
+			OptServices:                true,
+			streaming.OptStreamers:     []string{"failing"},
+			"streamers.failing.keys":   []string{"*"},
+			streaming.OptStopNodeOnErr: stopNodeOnErr,
+		}
+		app := NewLinkApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, appOpts, nil)
+		app.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+
+		if stopNodeOnErr {
+			// the block is left uncommitted to be executed again on restart
+			require.PanicsWithError(t, "failed to stream the block, aborting the commit: broker down", func() { app.Commit() })
+			require.Zero(t, app.LastBlockHeight())
+		} else {
+			// the block is lost for the service
+			require.NotPanics(t, func() { app.Commit() })
+			require.Equal(t, int64(1), app.LastBlockHeight())
+		}
+		require.NoError(t, app.Close())
+	}
+}
+
+func fileSizes(t *testing.T, dir string) map[string]int64 {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	sizes := make(map[string]int64, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		require.NoError(t, err)
+		sizes[entry.Name()] = info.Size()
+	}
+
+	return sizes
+}
+
+func TestHistory(t *testing.T) {
+	encCfg := MakeEncodingConfig()
+	appOpts := testutil.AppOptions{
+		OptServices:       true,
+		flags.FlagHome:    t.TempDir(),
+		history.OptEnable: true,
+	}
+	app := NewLinkApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, appOpts, nil)
+	defer func() { require.NoError(t, app.Close()) }()
+
+	stateBytes, err := json.Marshal(NewDefaultGenesisState(encCfg.Marshaler))
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+	app.Commit()
+
+	// the history is served over the ABCI queries
+	req := &history.QueryHistoryRequest{Address: sdk.AccAddress("addr").String()}
+	bz, err := proto.Marshal(req)
+	require.NoError(t, err)
+	res := app.Query(abci.RequestQuery{Path: "/finschia.history.v1.Query/History", Data: bz})
+	require.True(t, res.IsOK(), res.Log)
+
+	var historyRes history.QueryHistoryResponse
+	require.NoError(t, proto.Unmarshal(res.Value, &historyRes))
+	require.Empty(t, historyRes.Transfers)
+
+	// the service is not registered unless the history is enabled and the
+	// services run
+	for _, opts := range []testutil.AppOptions{
+		{OptServices: true},
+		{flags.FlagHome: t.TempDir(), history.OptEnable: true},
+	} {
+		disabled := NewLinkApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, opts, nil)
+		res = disabled.Query(abci.RequestQuery{Path: "/finschia.history.v1.Query/History", Data: bz})
+		require.False(t, res.IsOK())
+	}
+}
+
+func TestInvariants(t *testing.T) {
+	encCfg := MakeEncodingConfig()
+	appOpts := testutil.AppOptions{
+		invariants.OptPeriods: map[string]interface{}{"bankplus": 1},
+	}
+	app := NewLinkApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, appOpts, nil)
+
+	stateBytes, err := json.Marshal(NewDefaultGenesisState(encCfg.Marshaler))
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+	app.Commit()
+
+	results, err := app.CheckInvariants(nil)
+	require.NoError(t, err)
+	require.Len(t, results, len(app.CrisisKeeper.Routes()))
+	for _, result := range results {
+		require.False(t, result.Broken, result.Message)
+	}
+
+	results, err = app.CheckInvariants([]string{"token", "bankplus/inactive-addresses", "bankplus"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "bankplus/inactive-addresses", results[0].Route)
+	require.Equal(t, "token/supply", results[1].Route)
+
+	_, err = app.CheckInvariants([]string{"token/unknown"})
+	require.Error(t, err)
+
+	// the invariants of bankplus are asserted every block, while the others
+	// never with inv-check-period of 0
+	header := tmproto.Header{Height: app.LastBlockHeight() + 1}
+	app.BeginBlock(ocabci.RequestBeginBlock{Header: header})
+	app.BankKeeper.AddToInactiveAddr(app.NewContext(false, header), sdk.AccAddress("contract____________"))
+	require.Panics(t, func() { app.EndBlock(abci.RequestEndBlock{Height: header.Height}) })
+}
+
 func TestGetMaccPerms(t *testing.T) {
 	dup := GetMaccPerms()
 	require.Equal(t, maccPerms, dup, "duplicated module account permissions differed from actual module account permissions")
@@ -266,3 +498,30 @@ func TestUpgradeStateOnGenesis(t *testing.T) {
 		require.Equal(t, vm[v], i.ConsensusVersion())
 	}
 }
+
+func TestUpgradeHandler(t *testing.T) {
+	encCfg := MakeEncodingConfig()
+	app := NewLinkApp(log.NewOCLogger(log.NewSyncWriter(os.Stdout)), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, simapp.EmptyAppOptions{}, nil)
+	stateBytes, err := json.MarshalIndent(NewDefaultGenesisState(encCfg.Marshaler), "", "  ")
+	require.NoError(t, err)
+	app.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+
+	// roll the state back to a chain without blocklist
+	ctx := app.NewContext(false, tmproto.Header{Height: 1})
+	prefix.NewStore(ctx.KVStore(app.GetKey(upgradetypes.StoreKey)), []byte{upgradetypes.VersionMapByte}).Delete([]byte(blocklisttypes.ModuleName))
+	paramsStore := prefix.NewStore(ctx.KVStore(app.GetKey(paramstypes.StoreKey)), []byte(blocklisttypes.ModuleName+"/"))
+	paramsStore.Delete(blocklisttypes.KeyReceivableModuleAccounts)
+	paramsStore.Delete(blocklisttypes.KeyBlockedAddresses)
+	subspace := app.GetSubspace(blocklisttypes.ModuleName)
+	require.False(t, subspace.Has(ctx, blocklisttypes.KeyReceivableModuleAccounts))
+
+	app.UpgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: UpgradeName, Height: ctx.BlockHeight()})
+
+	require.True(t, subspace.Has(ctx, blocklisttypes.KeyReceivableModuleAccounts))
+	require.True(t, subspace.Has(ctx, blocklisttypes.KeyBlockedAddresses))
+	params := app.BlocklistKeeper.GetParams(ctx)
+	require.Empty(t, params.ReceivableModuleAccounts)
+	require.Empty(t, params.BlockedAddresses)
+	vm := app.UpgradeKeeper.GetModuleVersionMap(ctx)
+	require.Equal(t, app.mm.Modules[blocklisttypes.ModuleName].ConsensusVersion(), vm[blocklisttypes.ModuleName])
+}
@@ -1,16 +1,26 @@
 package app
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 
 	servertypes "github.com/Finschia/finschia-sdk/server/types"
 	sdk "github.com/Finschia/finschia-sdk/types"
+	distrtypes "github.com/Finschia/finschia-sdk/x/distribution/types"
 	slashingtypes "github.com/Finschia/finschia-sdk/x/slashing/types"
 	"github.com/Finschia/finschia-sdk/x/staking"
 	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+
+	"github.com/Finschia/finschia/app/invariants"
+	"github.com/Finschia/finschia/app/zeroheight"
 )
 
 // ExportAppStateAndValidators exports the state of the application for a genesis
@@ -18,6 +28,46 @@ import (
 func (app *LinkApp) ExportAppStateAndValidators(
 	forZeroHeight bool, jailAllowedAddrs []string,
 ) (servertypes.ExportedApp, error) {
+	return app.ExportAppStateAndValidatorsForModules(forZeroHeight, jailAllowedAddrs, nil, nil)
+}
+
+// ExportAppStateAndValidatorsForModules exports the state of the application for
+// a genesis file, restricted to modulesToExport (all modules if empty) minus
+// modulesToExclude.
+func (app *LinkApp) ExportAppStateAndValidatorsForModules(
+	forZeroHeight bool, jailAllowedAddrs, modulesToExport, modulesToExclude []string,
+) (servertypes.ExportedApp, error) {
+	var appState bytes.Buffer
+	exported, err := app.exportAppStateAndValidators(&appState, true, forZeroHeight, jailAllowedAddrs, modulesToExport, modulesToExclude)
+	if err != nil {
+		return servertypes.ExportedApp{}, err
+	}
+
+	exported.AppState = appState.Bytes()
+	return exported, nil
+}
+
+// StreamAppStateAndValidators works like ExportAppStateAndValidatorsForModules,
+// but writes the app state to w as compact JSON with sorted keys, one module at
+// a time, instead of returning it. The AppState of the returned ExportedApp is
+// left empty.
+func (app *LinkApp) StreamAppStateAndValidators(
+	w io.Writer, forZeroHeight bool, jailAllowedAddrs, modulesToExport, modulesToExclude []string,
+) (servertypes.ExportedApp, error) {
+	return app.exportAppStateAndValidators(w, false, forZeroHeight, jailAllowedAddrs, modulesToExport, modulesToExclude)
+}
+
+func (app *LinkApp) exportAppStateAndValidators(
+	w io.Writer, indent, forZeroHeight bool, jailAllowedAddrs, modulesToExport, modulesToExclude []string,
+) (servertypes.ExportedApp, error) {
+	modules, err := app.exportModules(modulesToExport, modulesToExclude)
+	if err != nil {
+		return servertypes.ExportedApp{}, err
+	}
+	if _, err := validateJailAllowedAddrs(jailAllowedAddrs); err != nil {
+		return servertypes.ExportedApp{}, err
+	}
+
 	// as if they could withdraw from the start of the next block
 	ctx := app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
 
@@ -26,67 +76,249 @@ func (app *LinkApp) ExportAppStateAndValidators(
 	height := app.LastBlockHeight() + 1
 	if forZeroHeight {
 		height = 0
-		app.prepForZeroHeightGenesis(ctx, jailAllowedAddrs)
+		// the time of the last block, which the modules normalize their
+		// genesis against
+		if info, found := app.StakingKeeper.GetHistoricalInfo(ctx, ctx.BlockHeight()); found {
+			ctx = ctx.WithBlockTime(info.Header.Time)
+		}
+		report, err := app.prepForZeroHeightGenesis(ctx, jailAllowedAddrs)
+		if err != nil {
+			return servertypes.ExportedApp{}, fmt.Errorf("failed to prepare zero height genesis: %w", err)
+		}
+		for _, donation := range report.Donations {
+			app.Logger().Info("donated scraps to the community pool", "validator", donation.Validator.String(), "scraps", donation.Scraps.String())
+		}
+		app.Logger().Info("donated scraps of all validators to the community pool", "total", report.TotalDonated().String())
 	}
 
-	genState := app.mm.ExportGenesis(ctx, app.appCodec)
-	appState, err := json.MarshalIndent(genState, "", "  ")
-	if err != nil {
+	exportModule := func(moduleName string) (json.RawMessage, error) {
+		appModule := app.mm.Modules[moduleName]
+		if m, ok := appModule.(zeroheight.Module); ok && forZeroHeight {
+			genesis, err := m.ExportZeroHeightGenesis(ctx, app.appCodec)
+			if err != nil {
+				return nil, fmt.Errorf("failed to prepare zero height genesis of %s: %w", moduleName, err)
+			}
+			return genesis, nil
+		}
+		return appModule.ExportGenesis(ctx, app.appCodec), nil
+	}
+	if err := writeAppState(w, modules, exportModule, indent); err != nil {
 		return servertypes.ExportedApp{}, err
 	}
 
 	validators, err := staking.WriteValidators(ctx, app.StakingKeeper)
 	return servertypes.ExportedApp{
-		AppState:        appState,
 		Validators:      validators,
 		Height:          height,
 		ConsensusParams: app.BaseApp.GetConsensusParams(ctx),
 	}, err
 }
 
-// prepare for fresh start at zero height
-// NOTE zero height genesis is a temporary feature which will be deprecated
+// exportModules returns the names of the modules to be exported, in export
+// genesis order.
+func (app *LinkApp) exportModules(modulesToExport, modulesToExclude []string) ([]string, error) {
+	for _, moduleName := range append(append([]string{}, modulesToExport...), modulesToExclude...) {
+		if _, ok := app.mm.Modules[moduleName]; !ok {
+			return nil, fmt.Errorf("unknown module %q; available modules are %s", moduleName, strings.Join(app.mm.OrderExportGenesis, ", "))
+		}
+	}
+
+	included := make(map[string]bool, len(modulesToExport))
+	for _, moduleName := range modulesToExport {
+		included[moduleName] = true
+	}
+	excluded := make(map[string]bool, len(modulesToExclude))
+	for _, moduleName := range modulesToExclude {
+		excluded[moduleName] = true
+	}
+
+	var modules []string
+	for _, moduleName := range app.mm.OrderExportGenesis {
+		if len(included) != 0 && !included[moduleName] {
+			continue
+		}
+		if excluded[moduleName] {
+			continue
+		}
+		modules = append(modules, moduleName)
+	}
+
+	return modules, nil
+}
+
+// writeAppState writes the genesis of the given modules to w as a JSON object
+// keyed by module name. Modules are exported and written one by one, so only a
+// single module's state is held in memory at a time.
 //
-//	in favor of export at a block height
-func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs []string) {
-	applyAllowedAddrs := false
+// If indent is set, the output is identical to json.MarshalIndent(genState, "", "  ").
+// Otherwise it is compact and the keys of each module's state are sorted, as
+// sdk.MustSortJSON would do.
+func writeAppState(w io.Writer, modules []string, exportModule func(string) (json.RawMessage, error), indent bool) error {
+	sorted := append([]string{}, modules...)
+	sort.Strings(sorted)
+
+	if len(sorted) == 0 {
+		_, err := io.WriteString(w, "{}")
+		return err
+	}
 
-	// check if there is a allowed address list
-	if len(jailAllowedAddrs) > 0 {
-		applyAllowedAddrs = true
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("{"); err != nil {
+		return err
 	}
+	for i, moduleName := range sorted {
+		if i > 0 {
+			if _, err := bw.WriteString(","); err != nil {
+				return err
+			}
+		}
+		if indent {
+			if _, err := bw.WriteString("\n  "); err != nil {
+				return err
+			}
+		}
 
-	allowedAddrsMap := make(map[string]bool)
+		key, err := json.Marshal(moduleName)
+		if err != nil {
+			return err
+		}
+		if _, err := bw.Write(key); err != nil {
+			return err
+		}
+		if _, err := bw.WriteString(":"); err != nil {
+			return err
+		}
 
-	for _, addr := range jailAllowedAddrs {
-		_, err := sdk.ValAddressFromBech32(addr)
+		genesis, err := exportModule(moduleName)
+		if err != nil {
+			return err
+		}
+		if genesis == nil {
+			// as json.Marshal does for a nil json.RawMessage
+			genesis = json.RawMessage("null")
+		}
+
+		var value []byte
+		if indent {
+			if _, err := bw.WriteString(" "); err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			err = json.Indent(&buf, genesis, "  ", "  ")
+			value = buf.Bytes()
+		} else {
+			value, err = sdk.SortJSON(genesis)
+		}
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("failed to encode genesis of module %s: %w", moduleName, err)
+		}
+		if _, err := bw.Write(value); err != nil {
+			return err
+		}
+	}
+	if indent {
+		if _, err := bw.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+	if _, err := bw.WriteString("}"); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// ScrapsDonation is the unwithdrawn fraction of the outstanding rewards of a
+// validator, donated to the community pool by a zero height export.
+type ScrapsDonation struct {
+	Validator sdk.ValAddress
+	Scraps    sdk.DecCoins
+}
+
+// ZeroHeightReport reports the changes made to the state by a zero height
+// export, which are not visible in the exported genesis.
+type ZeroHeightReport struct {
+	// Donations are the non-zero scraps donated to the community pool, in the
+	// order of the validators.
+	Donations []ScrapsDonation
+}
+
+// TotalDonated returns the sum of the scraps donated to the community pool.
+func (r ZeroHeightReport) TotalDonated() sdk.DecCoins {
+	total := sdk.DecCoins{}
+	for _, donation := range r.Donations {
+		total = total.Add(donation.Scraps...)
+	}
+	return total
+}
+
+// validateJailAllowedAddrs checks the addresses of the validators not to jail,
+// and returns them as a set.
+func validateJailAllowedAddrs(jailAllowedAddrs []string) (map[string]bool, error) {
+	allowedAddrsMap := make(map[string]bool, len(jailAllowedAddrs))
+	for _, addr := range jailAllowedAddrs {
+		if _, err := sdk.ValAddressFromBech32(addr); err != nil {
+			return nil, fmt.Errorf("invalid jail allowed address %q: %w", addr, err)
 		}
 		allowedAddrsMap[addr] = true
 	}
+	return allowedAddrsMap, nil
+}
+
+// prepare for fresh start at zero height
+// NOTE zero height genesis is a temporary feature which will be deprecated
+//
+//	in favor of export at a block height
+func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs []string) (ZeroHeightReport, error) {
+	var report ZeroHeightReport
+
+	// check if there is a allowed address list
+	applyAllowedAddrs := len(jailAllowedAddrs) > 0
+
+	allowedAddrsMap, err := validateJailAllowedAddrs(jailAllowedAddrs)
+	if err != nil {
+		return report, err
+	}
 
-	/* Just to be safe, assert the invariants on current state. */
-	app.CrisisKeeper.AssertInvariants(ctx)
+	/* Just to be safe, check the invariants on current state. */
+	var broken []string
+	for _, result := range invariants.Check(ctx, app.CrisisKeeper.Routes()) {
+		if result.Broken {
+			broken = append(broken, result.Route)
+		}
+	}
+	if len(broken) != 0 {
+		return report, fmt.Errorf("invariants broken: %s", strings.Join(broken, ", "))
+	}
 
 	/* Handle fee distribution state. */
 
 	// withdraw all validator commission
 	app.StakingKeeper.IterateValidators(ctx, func(_ int64, val stakingtypes.ValidatorI) (stop bool) {
-		_, _ = app.DistrKeeper.WithdrawValidatorCommission(ctx, val.GetOperator()) // nolint: errcheck
-		return false
+		_, err = app.DistrKeeper.WithdrawValidatorCommission(ctx, val.GetOperator())
+		if errors.Is(err, distrtypes.ErrNoValidatorCommission) {
+			err = nil
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to withdraw commission of validator %s: %w", val.GetOperator(), err)
+		}
+		return err != nil
 	})
+	if err != nil {
+		return report, err
+	}
 
 	// withdraw all delegator rewards
 	dels := app.StakingKeeper.GetAllDelegations(ctx)
 	for _, delegation := range dels {
-		valAddr, err := sdk.ValAddressFromBech32(delegation.ValidatorAddress)
+		valAddr, delAddr, err := delegationAddresses(delegation)
 		if err != nil {
-			panic(err)
+			return report, err
 		}
-		delAddr := sdk.MustAccAddressFromBech32(delegation.DelegatorAddress)
 
-		_, _ = app.DistrKeeper.WithdrawDelegationRewards(ctx, delAddr, valAddr) // nolint: errcheck
+		if _, err := app.DistrKeeper.WithdrawDelegationRewards(ctx, delAddr, valAddr); err != nil {
+			return report, fmt.Errorf("failed to withdraw rewards of delegation from %s to %s: %w", delAddr, valAddr, err)
+		}
 	}
 
 	// clear validator slash events
@@ -106,6 +338,9 @@ func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs [
 		feePool := app.DistrKeeper.GetFeePool(ctx)
 		feePool.CommunityPool = feePool.CommunityPool.Add(scraps...)
 		app.DistrKeeper.SetFeePool(ctx, feePool)
+		if !scraps.IsZero() {
+			report.Donations = append(report.Donations, ScrapsDonation{Validator: val.GetOperator(), Scraps: scraps})
+		}
 
 		app.DistrKeeper.Hooks().AfterValidatorCreated(ctx, val.GetOperator())
 		return false
@@ -113,11 +348,10 @@ func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs [
 
 	// reinitialize all delegations
 	for _, del := range dels {
-		valAddr, err := sdk.ValAddressFromBech32(del.ValidatorAddress)
+		valAddr, delAddr, err := delegationAddresses(del)
 		if err != nil {
-			panic(err)
+			return report, err
 		}
-		delAddr := sdk.MustAccAddressFromBech32(del.DelegatorAddress)
 		app.DistrKeeper.Hooks().BeforeDelegationCreated(ctx, delAddr, valAddr)
 		app.DistrKeeper.Hooks().AfterDelegationModified(ctx, delAddr, valAddr)
 	}
@@ -149,13 +383,13 @@ func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs [
 	// update bond intra-tx counters.
 	store := ctx.KVStore(app.keys[stakingtypes.StoreKey])
 	iter := sdk.KVStoreReversePrefixIterator(store, stakingtypes.ValidatorsKey)
-	counter := int16(0)
+	defer iter.Close()
 
 	for ; iter.Valid(); iter.Next() {
-		addr := sdk.ValAddress(iter.Key()[1:])
+		addr := sdk.ValAddress(stakingtypes.AddressFromValidatorsKey(iter.Key()))
 		validator, found := app.StakingKeeper.GetValidator(ctx, addr)
 		if !found {
-			panic("expected validator, not found")
+			return report, fmt.Errorf("expected validator %s, not found", addr)
 		}
 
 		validator.UnbondingHeight = 0
@@ -164,14 +398,10 @@ func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs [
 		}
 
 		app.StakingKeeper.SetValidator(ctx, validator)
-		counter++
 	}
 
-	iter.Close()
-
-	_, err := app.StakingKeeper.ApplyAndReturnValidatorSetUpdates(ctx)
-	if err != nil {
-		log.Fatal(err)
+	if _, err := app.StakingKeeper.ApplyAndReturnValidatorSetUpdates(ctx); err != nil {
+		return report, fmt.Errorf("failed to apply validator set updates: %w", err)
 	}
 
 	/* Handle slashing state. */
@@ -185,4 +415,18 @@ func (app *LinkApp) prepForZeroHeightGenesis(ctx sdk.Context, jailAllowedAddrs [
 			return false
 		},
 	)
+
+	return report, nil
+}
+
+func delegationAddresses(delegation stakingtypes.Delegation) (sdk.ValAddress, sdk.AccAddress, error) {
+	valAddr, err := sdk.ValAddressFromBech32(delegation.ValidatorAddress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed delegation validator address %q: %w", delegation.ValidatorAddress, err)
+	}
+	delAddr, err := sdk.AccAddressFromBech32(delegation.DelegatorAddress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed delegation delegator address %q: %w", delegation.DelegatorAddress, err)
+	}
+	return valAddr, delAddr, nil
 }
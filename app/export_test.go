@@ -0,0 +1,302 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+	"github.com/Finschia/ostracon/libs/log"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/crypto/keys/ed25519"
+	"github.com/Finschia/finschia-sdk/simapp"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	distrtypes "github.com/Finschia/finschia-sdk/x/distribution/types"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+	foundationkeeper "github.com/Finschia/finschia-sdk/x/foundation/keeper"
+	minttypes "github.com/Finschia/finschia-sdk/x/mint/types"
+	"github.com/Finschia/finschia-sdk/x/token"
+	wasmplustypes "github.com/Finschia/wasmd/x/wasmplus/types"
+
+	"github.com/Finschia/finschia/app/zeroheight"
+)
+
+func newExportTestApp(t *testing.T) *LinkApp {
+	encCfg := MakeEncodingConfig()
+	db := dbm.NewMemDB()
+	app := NewLinkApp(log.NewOCLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, map[int64]bool{}, DefaultNodeHome, 0, encCfg, simapp.EmptyAppOptions{}, nil)
+
+	genesisState := NewDefaultGenesisState(encCfg.Marshaler)
+	stateBytes, err := json.MarshalIndent(genesisState, "", "  ")
+	require.NoError(t, err)
+
+	app.InitChain(
+		abci.RequestInitChain{
+			Validators:    []abci.ValidatorUpdate{},
+			AppStateBytes: stateBytes,
+		},
+	)
+	app.Commit()
+
+	return app
+}
+
+func TestExportAppStateAndValidatorsForModules(t *testing.T) {
+	app := newExportTestApp(t)
+
+	var allButWasm []string
+	for _, moduleName := range app.mm.OrderExportGenesis {
+		if moduleName != wasmplustypes.ModuleName {
+			allButWasm = append(allButWasm, moduleName)
+		}
+	}
+
+	testCases := map[string]struct {
+		modules        []string
+		excludeModules []string
+		expected       []string
+		valid          bool
+	}{
+		"all modules": {
+			expected: app.mm.OrderExportGenesis,
+			valid:    true,
+		},
+		"include": {
+			modules:  []string{banktypes.ModuleName, wasmplustypes.ModuleName, collection.ModuleName},
+			expected: []string{banktypes.ModuleName, wasmplustypes.ModuleName, collection.ModuleName},
+			valid:    true,
+		},
+		"include and exclude": {
+			modules:        []string{banktypes.ModuleName, token.ModuleName},
+			excludeModules: []string{token.ModuleName},
+			expected:       []string{banktypes.ModuleName},
+			valid:          true,
+		},
+		"exclude": {
+			excludeModules: []string{wasmplustypes.ModuleName},
+			expected:       allButWasm,
+			valid:          true,
+		},
+		"unknown module": {
+			modules: []string{"nosuchmodule"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			exported, err := app.ExportAppStateAndValidatorsForModules(false, nil, tc.modules, tc.excludeModules)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var genState GenesisState
+			require.NoError(t, json.Unmarshal(exported.AppState, &genState))
+			require.Len(t, genState, len(tc.expected))
+			for _, moduleName := range tc.expected {
+				require.Contains(t, genState, moduleName)
+			}
+		})
+	}
+}
+
+func TestStreamAppStateAndValidators(t *testing.T) {
+	app := newExportTestApp(t)
+
+	exported, err := app.ExportAppStateAndValidators(false, nil)
+	require.NoError(t, err)
+
+	// the default export must be identical to the former json.MarshalIndent
+	// of the whole app state
+	ctx := app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+	expected, err := json.MarshalIndent(app.mm.ExportGenesis(ctx, app.appCodec), "", "  ")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(exported.AppState))
+
+	var buf bytes.Buffer
+	streamed, err := app.StreamAppStateAndValidators(&buf, false, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, streamed.AppState)
+	require.Equal(t, exported.Height, streamed.Height)
+	require.Equal(t, string(sdk.MustSortJSON(exported.AppState)), buf.String())
+}
+
+func TestExportForZeroHeightInvalidJailAllowedAddrs(t *testing.T) {
+	app := newExportTestApp(t)
+
+	_, err := app.ExportAppStateAndValidators(true, []string{"link1invalid"})
+	require.Error(t, err)
+}
+
+func TestPrepForZeroHeightGenesisReport(t *testing.T) {
+	app := newExportTestApp(t)
+
+	operator := sdk.AccAddress("operator____________")
+	valAddr := sdk.ValAddress(operator)
+	require.NoError(t, app.InitInPlaceTestnet(InPlaceTestnetArgs{
+		ValidatorConsPubKey: ed25519.GenPrivKey().PubKey(),
+		ValidatorOperator:   operator,
+		ValidatorPower:      100,
+		VotingPeriod:        time.Minute,
+	}))
+	header := tmproto.Header{Height: app.LastBlockHeight() + 1, Time: time.Now()}
+	app.BeginBlock(ocabci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	app.Commit()
+
+	ctx := app.NewUncachedContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+
+	// leave an unwithdrawable fraction of a reward to the validator
+	scraps := sdk.NewDecCoins(sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDecWithPrec(5, 1)))
+	funds := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1))
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, funds))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, distrtypes.ModuleName, funds))
+	app.DistrKeeper.SetValidatorOutstandingRewards(ctx, valAddr, distrtypes.ValidatorOutstandingRewards{Rewards: scraps})
+	feePool := app.DistrKeeper.GetFeePool(ctx)
+	feePool.CommunityPool = feePool.CommunityPool.Add(scraps...)
+	app.DistrKeeper.SetFeePool(ctx, feePool)
+
+	testCases := map[string]struct {
+		jailAllowedAddrs []string
+		malleate         func(t *testing.T, ctx sdk.Context)
+		valid            bool
+		errMsg           string
+	}{
+		"valid": {
+			jailAllowedAddrs: []string{valAddr.String()},
+			valid:            true,
+		},
+		"invalid jail allowed address": {
+			jailAllowedAddrs: []string{operator.String()},
+		},
+		"invariant broken": {
+			jailAllowedAddrs: []string{valAddr.String()},
+			malleate: func(t *testing.T, ctx sdk.Context) {
+				// bankplus keeps the inactive addresses in memory as well
+				addr := sdk.AccAddress("contract____________")
+				app.BankKeeper.AddToInactiveAddr(ctx, addr)
+				t.Cleanup(func() { app.BankKeeper.DeleteFromInactiveAddr(ctx, addr) })
+			},
+			errMsg: "invariants broken: bankplus/inactive-addresses",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, _ := ctx.CacheContext()
+			if tc.malleate != nil {
+				tc.malleate(t, ctx)
+			}
+			communityPool := app.DistrKeeper.GetFeePoolCommunityCoins(ctx)
+
+			var report ZeroHeightReport
+			var err error
+			require.NotPanics(t, func() { report, err = app.prepForZeroHeightGenesis(ctx, tc.jailAllowedAddrs) })
+			if !tc.valid {
+				require.Error(t, err)
+				if tc.errMsg != "" {
+					require.EqualError(t, err, tc.errMsg)
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, []ScrapsDonation{{Validator: valAddr, Scraps: scraps}}, report.Donations)
+			require.Equal(t, scraps, report.TotalDonated())
+			require.Equal(t, communityPool.Add(scraps...), app.DistrKeeper.GetFeePoolCommunityCoins(ctx))
+		})
+	}
+}
+
+func TestExportForZeroHeightReimport(t *testing.T) {
+	app := newExportTestApp(t)
+	for _, moduleName := range []string{foundation.ModuleName, wasmplustypes.ModuleName} {
+		require.Implements(t, (*zeroheight.Module)(nil), app.mm.Modules[moduleName])
+	}
+
+	operator := sdk.AccAddress("operator____________")
+	require.NoError(t, app.InitInPlaceTestnet(InPlaceTestnetArgs{
+		ValidatorConsPubKey: ed25519.GenPrivKey().PubKey(),
+		ValidatorOperator:   operator,
+		ValidatorPower:      100,
+		VotingPeriod:        time.Hour,
+	}))
+	header := tmproto.Header{Height: app.LastBlockHeight() + 1, Time: time.Now()}
+	app.BeginBlock(ocabci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	app.Commit()
+
+	ctx := app.NewUncachedContext(false, header)
+	goCtx := sdk.WrapSDKContext(ctx)
+
+	// submit a live proposal and a withdrawn one, both voted
+	msgServer := foundationkeeper.NewMsgServer(app.FoundationKeeper)
+	var proposalIDs []uint64
+	for i := 0; i < 2; i++ {
+		msg := &foundation.MsgSubmitProposal{Proposers: []string{operator.String()}}
+		require.NoError(t, msg.SetMsgs([]sdk.Msg{&foundation.MsgWithdrawFromTreasury{
+			Authority: app.FoundationKeeper.GetAuthority(),
+			To:        operator.String(),
+			Amount:    sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1)),
+		}}))
+		res, err := msgServer.SubmitProposal(goCtx, msg)
+		require.NoError(t, err)
+		proposalIDs = append(proposalIDs, res.ProposalId)
+
+		_, err = msgServer.Vote(goCtx, &foundation.MsgVote{ProposalId: res.ProposalId, Voter: operator.String(), Option: foundation.VOTE_OPTION_YES})
+		require.NoError(t, err)
+	}
+	_, err := msgServer.WithdrawProposal(goCtx, &foundation.MsgWithdrawProposal{ProposalId: proposalIDs[1], Address: operator.String()})
+	require.NoError(t, err)
+
+	// deactivate a contract which does not exist
+	contract := sdk.AccAddress("contract____________")
+	ctx.KVStore(app.keys[wasmplustypes.StoreKey]).Set(wasmplustypes.GetInactiveContractKey(contract), contract)
+	app.BankKeeper.AddToInactiveAddr(ctx, contract)
+
+	header = tmproto.Header{Height: app.LastBlockHeight() + 1, Time: header.Time.Add(time.Second)}
+	app.BeginBlock(ocabci.RequestBeginBlock{Header: header})
+	app.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	app.Commit()
+
+	exported, err := app.ExportAppStateAndValidators(true, nil)
+	require.NoError(t, err)
+	require.Zero(t, exported.Height)
+
+	var genesisState GenesisState
+	require.NoError(t, json.Unmarshal(exported.AppState, &genesisState))
+	require.NoError(t, ModuleBasics.ValidateGenesis(app.appCodec, MakeEncodingConfig().TxConfig, genesisState))
+
+	var foundationGenesis foundation.GenesisState
+	require.NoError(t, app.appCodec.UnmarshalJSON(genesisState[foundation.ModuleName], &foundationGenesis))
+	require.Len(t, foundationGenesis.Proposals, 1)
+	require.Equal(t, proposalIDs[0], foundationGenesis.Proposals[0].Id)
+	require.Len(t, foundationGenesis.Votes, 1)
+	require.Equal(t, proposalIDs[0], foundationGenesis.Votes[0].ProposalId)
+
+	var wasmGenesis wasmplustypes.GenesisState
+	require.NoError(t, app.appCodec.UnmarshalJSON(genesisState[wasmplustypes.ModuleName], &wasmGenesis))
+	require.Empty(t, wasmGenesis.InactiveContractAddresses)
+
+	// re-import into a fresh app
+	newApp := NewLinkApp(log.NewOCLogger(log.NewSyncWriter(os.Stdout)), dbm.NewMemDB(), nil, true, map[int64]bool{}, DefaultNodeHome, 0, MakeEncodingConfig(), simapp.EmptyAppOptions{}, nil)
+	newApp.InitChain(abci.RequestInitChain{
+		Time:          header.Time,
+		AppStateBytes: exported.AppState,
+	})
+	newApp.Commit()
+
+	newCtx := newApp.NewContext(true, tmproto.Header{Height: newApp.LastBlockHeight()})
+	require.NotPanics(t, func() { newApp.CrisisKeeper.AssertInvariants(newCtx) })
+	require.Len(t, newApp.StakingKeeper.GetAllValidators(newCtx), 1)
+}
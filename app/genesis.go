@@ -1,9 +1,22 @@
 package app
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cast"
+	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/Finschia/finschia-sdk/codec"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
 )
 
 // GenesisState
@@ -20,3 +33,140 @@ type GenesisState map[string]json.RawMessage
 func NewDefaultGenesisState(cdc codec.JSONCodec) GenesisState {
 	return ModuleBasics.DefaultGenesis(cdc)
 }
+
+// GenesisFileRefKey is the key of an object that may stand in for the genesis
+// state of a module in the app state. Its value is the path of a file holding
+// the genesis state of the module, relative to the directory of genesis.json
+// unless absolute, and GenesisFileHashKey is the hex encoded SHA-256 of the
+// file, checked before the state is loaded:
+//
+//	"app_state": {
+//	  "auth": { ... },
+//	  "wasm": {"@file": "genesis/wasm.json", "@sha256": "9f86d0..."}
+//	}
+//
+// Referenced states are loaded one module at a time, so that very large states
+// never have to be held in memory as a whole.
+const (
+	GenesisFileRefKey  = "@file"
+	GenesisFileHashKey = "@sha256"
+)
+
+type genesisFileRef struct {
+	File   string `json:"@file"`
+	SHA256 string `json:"@sha256"`
+}
+
+// NewGenesisFileRef returns the reference to the genesis state of a module
+// held by the given file, whose content is state.
+func NewGenesisFileRef(file string, state []byte) json.RawMessage {
+	hash := sha256.Sum256(state)
+	bz, err := json.Marshal(genesisFileRef{File: file, SHA256: hex.EncodeToString(hash[:])})
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// OptGenesisFile is the app option of the genesis file of the node, genesis_file
+// of config.toml, relative to the home unless absolute.
+const OptGenesisFile = "genesis_file"
+
+// genesisFilePath returns the path of the genesis file of the node of the home.
+func genesisFilePath(homePath string, appOpts servertypes.AppOptions) string {
+	path := cast.ToString(appOpts.Get(OptGenesisFile))
+	if path == "" {
+		path = filepath.Join("config", "genesis.json")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(homePath, path)
+	}
+	return path
+}
+
+// GenesisFileResolver loads the genesis states of modules, reading the ones
+// referenced by file (see GenesisFileRefKey). Relative paths are resolved
+// against the directory of the genesis file, so that the chain and
+// validate-genesis load the same files.
+type GenesisFileResolver struct {
+	baseDir string
+}
+
+// NewGenesisFileResolver returns the resolver of the references of the given
+// genesis file.
+func NewGenesisFileResolver(genesisFile string) GenesisFileResolver {
+	return GenesisFileResolver{baseDir: filepath.Dir(genesisFile)}
+}
+
+// LoadModuleGenesis returns the genesis state of the given module in gs,
+// reading it from the referenced file if the state is a reference. It returns
+// nil if the module has no genesis state.
+func (r GenesisFileResolver) LoadModuleGenesis(gs GenesisState, moduleName string) (json.RawMessage, error) {
+	state := gs[moduleName]
+	if state == nil {
+		return nil, nil
+	}
+
+	// a reference has no other fields, so the decoding fails fast on an
+	// inlined state
+	var ref genesisFileRef
+	dec := json.NewDecoder(bytes.NewReader(state))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ref); err != nil || ref.File == "" {
+		return state, nil
+	}
+	if ref.SHA256 == "" {
+		return nil, fmt.Errorf("no %s in the genesis reference of module %s", GenesisFileHashKey, moduleName)
+	}
+
+	path := ref.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.baseDir, path)
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis of module %s: %w", moduleName, err)
+	}
+	if hash := sha256.Sum256(bz); !strings.EqualFold(hex.EncodeToString(hash[:]), ref.SHA256) {
+		return nil, fmt.Errorf("%s of genesis file %s of module %s mismatch: expected %s, got %X", GenesisFileHashKey, path, moduleName, ref.SHA256, hash)
+	}
+	if !json.Valid(bz) {
+		return nil, fmt.Errorf("invalid JSON in genesis file %s of module %s", path, moduleName)
+	}
+
+	return bz, nil
+}
+
+// initGenesis performs init genesis functionality for the modules as the
+// module manager does, loading and applying the genesis state one module at a
+// time.
+func (app *LinkApp) initGenesis(ctx sdk.Context, genesisState GenesisState, resolver GenesisFileResolver) (abci.ResponseInitChain, error) {
+	var validatorUpdates []abci.ValidatorUpdate
+	for _, moduleName := range app.mm.OrderInitGenesis {
+		moduleGenesis, err := resolver.LoadModuleGenesis(genesisState, moduleName)
+		if err != nil {
+			return abci.ResponseInitChain{}, err
+		}
+		if moduleGenesis == nil {
+			continue
+		}
+
+		moduleValUpdates := app.mm.Modules[moduleName].InitGenesis(ctx, app.appCodec, moduleGenesis)
+
+		// release the state of the module before loading the next one
+		delete(genesisState, moduleName)
+
+		// use these validator updates if provided, the module manager assumes
+		// only one module will update the validator set
+		if len(moduleValUpdates) > 0 {
+			if len(validatorUpdates) > 0 {
+				return abci.ResponseInitChain{}, errors.New("validator InitGenesis updates already set by a previous module")
+			}
+			validatorUpdates = moduleValUpdates
+		}
+	}
+
+	return abci.ResponseInitChain{
+		Validators: validatorUpdates,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/ostracon/libs/log"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	wasmplustypes "github.com/Finschia/wasmd/x/wasmplus/types"
+
+	"github.com/Finschia/finschia/app/testutil"
+)
+
+func TestLoadModuleGenesis(t *testing.T) {
+	baseDir := t.TempDir()
+	moduleGenesis := json.RawMessage(`{"params":{}}`)
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "bank.json"), moduleGenesis, 0o600))
+	hash := sha256.Sum256(moduleGenesis)
+
+	testCases := map[string]struct {
+		state    json.RawMessage
+		expected json.RawMessage
+		valid    bool
+	}{
+		"inlined": {
+			state:    moduleGenesis,
+			expected: moduleGenesis,
+			valid:    true,
+		},
+		"inlined with the key": {
+			state:    json.RawMessage(`{"@file":"bank.json","params":{}}`),
+			expected: json.RawMessage(`{"@file":"bank.json","params":{}}`),
+			valid:    true,
+		},
+		"relative reference": {
+			state:    NewGenesisFileRef("bank.json", moduleGenesis),
+			expected: moduleGenesis,
+			valid:    true,
+		},
+		"absolute reference": {
+			state:    NewGenesisFileRef(filepath.Join(baseDir, "bank.json"), moduleGenesis),
+			expected: moduleGenesis,
+			valid:    true,
+		},
+		"upper case hash": {
+			state:    json.RawMessage(`{"@file":"bank.json","@sha256":"` + strings.ToUpper(hex.EncodeToString(hash[:])) + `"}`),
+			expected: moduleGenesis,
+			valid:    true,
+		},
+		"missing": {
+			valid: true,
+		},
+		"file not found": {
+			state: NewGenesisFileRef("nosuchfile.json", moduleGenesis),
+		},
+		"no hash": {
+			state: json.RawMessage(`{"@file":"bank.json"}`),
+		},
+		"hash mismatch": {
+			state: NewGenesisFileRef("bank.json", []byte(`{"params":null}`)),
+		},
+	}
+
+	resolver := NewGenesisFileResolver(filepath.Join(baseDir, "genesis.json"))
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			genState := GenesisState{}
+			if tc.state != nil {
+				genState[banktypes.ModuleName] = tc.state
+			}
+
+			loaded, err := resolver.LoadModuleGenesis(genState, banktypes.ModuleName)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, loaded)
+		})
+	}
+}
+
+func TestInitChainWithGenesisFileRefs(t *testing.T) {
+	// the references are resolved against the directory of the genesis file
+	// given by the config, as validate-genesis does
+	homePath := t.TempDir()
+	genesisDir := filepath.Join(homePath, "network", "genesis")
+	require.NoError(t, os.MkdirAll(genesisDir, 0o755))
+
+	encCfg := MakeEncodingConfig()
+	appOpts := testutil.AppOptions{OptGenesisFile: filepath.Join("network", "genesis.json")}
+	app := NewLinkApp(log.NewOCLogger(log.NewSyncWriter(os.Stdout)), dbm.NewMemDB(), nil, true, map[int64]bool{}, homePath, 0, encCfg, appOpts, nil)
+
+	// move the genesis of bank and wasm out to their own files
+	genesisState := NewDefaultGenesisState(encCfg.Marshaler)
+	bankGenesis := banktypes.DefaultGenesisState()
+	bankGenesis.Supply = sdk.NewCoins(sdk.NewInt64Coin("stake", 1000))
+	bankGenesis.Balances = []banktypes.Balance{{
+		Address: sdk.AccAddress("addr1_______________").String(),
+		Coins:   bankGenesis.Supply,
+	}}
+	genesisState[banktypes.ModuleName] = encCfg.Marshaler.MustMarshalJSON(bankGenesis)
+
+	for _, moduleName := range []string{banktypes.ModuleName, wasmplustypes.ModuleName} {
+		path := filepath.Join(genesisDir, moduleName+".json")
+		require.NoError(t, os.WriteFile(path, genesisState[moduleName], 0o600))
+		genesisState[moduleName] = NewGenesisFileRef("genesis/"+moduleName+".json", genesisState[moduleName])
+	}
+
+	stateBytes, err := json.MarshalIndent(genesisState, "", "  ")
+	require.NoError(t, err)
+
+	app.InitChain(
+		abci.RequestInitChain{
+			Validators:    []abci.ValidatorUpdate{},
+			AppStateBytes: stateBytes,
+		},
+	)
+	app.Commit()
+
+	ctx := app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+	require.Equal(t, bankGenesis.Supply, app.BankKeeper.GetAllBalances(ctx, sdk.AccAddress("addr1_______________")))
+}
@@ -0,0 +1,714 @@
+package helpers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Finschia/ostracon/libs/log"
+	ostrand "github.com/Finschia/ostracon/libs/rand"
+	"github.com/Finschia/ostracon/node"
+	"github.com/Finschia/ostracon/p2p"
+	"github.com/Finschia/ostracon/privval"
+	"github.com/Finschia/ostracon/proxy"
+	ostclient "github.com/Finschia/ostracon/rpc/client"
+	rpchttp "github.com/Finschia/ostracon/rpc/client/http"
+	octypes "github.com/Finschia/ostracon/types"
+	osttime "github.com/Finschia/ostracon/types/time"
+	dbm "github.com/tendermint/tm-db"
+	"google.golang.org/grpc"
+
+	"github.com/Finschia/finschia-sdk/baseapp"
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/tx"
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
+	cryptotypes "github.com/Finschia/finschia-sdk/crypto/types"
+	"github.com/Finschia/finschia-sdk/server"
+	"github.com/Finschia/finschia-sdk/server/api"
+	srvconfig "github.com/Finschia/finschia-sdk/server/config"
+	servergrpc "github.com/Finschia/finschia-sdk/server/grpc"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	"github.com/Finschia/finschia-sdk/testutil"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	authtypes "github.com/Finschia/finschia-sdk/x/auth/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/genutil"
+	genutiltypes "github.com/Finschia/finschia-sdk/x/genutil/types"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+
+	linkapp "github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/params"
+)
+
+// NetworkConfig defines how a Network is bootstrapped.
+type NetworkConfig struct {
+	EncodingConfig params.EncodingConfig
+	// GenesisState is the genesis the validators and their accounts are added
+	// to. It defaults to the default genesis of LinkApp.
+	GenesisState  map[string]json.RawMessage
+	ChainID       string
+	NumValidators int
+	// TimeoutCommit controls the block time.
+	TimeoutCommit time.Duration
+	BondDenom     string
+	MinGasPrices  string
+	// AccountTokens are the tokens of the unique denom of each validator, e.g.
+	// 1000node0.
+	AccountTokens sdk.Int
+	// StakingTokens are the tokens of BondDenom of each validator operator, of
+	// which BondedTokens are bonded.
+	StakingTokens sdk.Int
+	BondedTokens  sdk.Int
+	// Logger receives the logs of the nodes. They are discarded if nil.
+	Logger log.Logger
+}
+
+// DefaultNetworkConfig returns the configuration of a network of 4 validators,
+// producing a block per second.
+func DefaultNetworkConfig() NetworkConfig {
+	encCfg := linkapp.MakeEncodingConfig()
+
+	return NetworkConfig{
+		EncodingConfig: encCfg,
+		GenesisState:   linkapp.NewDefaultGenesisState(encCfg.Marshaler),
+		ChainID:        "chain-" + ostrand.NewRand().Str(6),
+		NumValidators:  4,
+		TimeoutCommit:  time.Second,
+		BondDenom:      sdk.DefaultBondDenom,
+		MinGasPrices:   fmt.Sprintf("0.000006%s", sdk.DefaultBondDenom),
+		AccountTokens:  sdk.TokensFromConsensusPower(1000, sdk.DefaultPowerReduction),
+		StakingTokens:  sdk.TokensFromConsensusPower(500, sdk.DefaultPowerReduction),
+		BondedTokens:   sdk.TokensFromConsensusPower(100, sdk.DefaultPowerReduction),
+	}
+}
+
+// Network is a network of LinkApp validators running in the current process.
+// The state of the apps and the nodes is held in memory, and the nodes connect
+// to each other over the loopback interface on ephemeral ports.
+//
+// NOTE: the p2p transport of ostracon only listens on TCP, so the nodes cannot
+// be connected in memory. The ephemeral ports are kept bound from the moment
+// they are allocated until the node using them starts, so that parallel tests
+// allocating ports cannot be handed the same ones.
+//
+// Every validator exposes the RPC, REST and gRPC endpoints on ephemeral ports
+// of its own, so several networks can run at a time. As ostracon serves the RPC
+// of a single node per process, the RPC calls to the nodes of the process are
+// served one at a time.
+type Network struct {
+	// BaseDir is empty for the networks of NewNetworkFromHomes.
+	BaseDir    string
+	Validators []*NetworkValidator
+	Config     NetworkConfig
+
+	stopOnce sync.Once
+}
+
+// NetworkValidator is a validator of a Network. The keyring of its operator,
+// named after the moniker, is in Dir.
+type NetworkValidator struct {
+	Moniker    string
+	Dir        string
+	NodeID     string
+	PubKey     cryptotypes.PubKey
+	Address    sdk.AccAddress
+	ValAddress sdk.ValAddress
+
+	P2PAddress  string
+	RPCAddress  string
+	APIAddress  string
+	GRPCAddress string
+	// ClientCtx is connected to the RPC of the validator once it is started.
+	ClientCtx client.Context
+	RPCClient ostclient.Client
+
+	ctx       *server.Context
+	appConfig *srvconfig.Config
+	// ports keeps the ports of the validator bound until it starts.
+	ports []net.Listener
+
+	app     *linkapp.LinkApp
+	node    *node.Node
+	rpc     net.Listener
+	api     *api.Server
+	grpc    *grpc.Server
+	grpcWeb *http.Server
+}
+
+// NewNetwork generates the genesis and the node files of cfg.NumValidators
+// validators in baseDir, and starts them.
+func NewNetwork(baseDir string, cfg NetworkConfig) (*Network, error) {
+	if cfg.NumValidators < 1 {
+		return nil, errors.New("at least one validator is required")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewNopLogger()
+	}
+	if cfg.GenesisState == nil {
+		cfg.GenesisState = linkapp.NewDefaultGenesisState(cfg.EncodingConfig.Marshaler)
+	}
+
+	n := &Network{
+		BaseDir:    baseDir,
+		Validators: make([]*NetworkValidator, cfg.NumValidators),
+		Config:     cfg,
+	}
+	if err := n.init(); err != nil {
+		n.Stop()
+		return nil, err
+	}
+	if err := n.start(); err != nil {
+		n.Stop()
+		return nil, err
+	}
+	return n, nil
+}
+
+// NewNetworkFromHomes starts the validators of the nodes initialized in homes,
+// e.g. by fnsad init, gentx and collect-gentxs, with their keys, genesis and
+// keyrings of the test backend. The chain ID is taken from the genesis, and the
+// configs of the nodes are replaced by those of NewNetwork, connecting the
+// nodes to each other. Their state is held in memory as well.
+func NewNetworkFromHomes(homes []string, cfg NetworkConfig) (*Network, error) {
+	if len(homes) < 1 {
+		return nil, errors.New("at least one validator is required")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.NewNopLogger()
+	}
+
+	n := &Network{
+		Validators: make([]*NetworkValidator, len(homes)),
+		Config:     cfg,
+	}
+	if err := n.initFromHomes(homes); err != nil {
+		n.Stop()
+		return nil, err
+	}
+	if err := n.start(); err != nil {
+		n.Stop()
+		return nil, err
+	}
+	return n, nil
+}
+
+// newValidator returns the validator of the node in home, reserving the ports
+// of its endpoints. Its keys and genesis are left to the caller.
+func (n *Network) newValidator(home, moniker string) (*NetworkValidator, error) {
+	cfg := n.Config
+	if err := os.MkdirAll(filepath.Join(home, "config"), 0o755); err != nil {
+		return nil, err
+	}
+
+	appConfig := srvconfig.DefaultConfig()
+	appConfig.Pruning = storetypes.PruningOptionNothing
+	appConfig.MinGasPrices = cfg.MinGasPrices
+	appConfig.API.Swagger = false
+	appConfig.Telemetry.Enabled = false
+
+	ctx := server.NewDefaultContext()
+	ctx.Logger = cfg.Logger.With("module", moniker)
+	nodeConfig := ctx.Config
+	nodeConfig.SetRoot(home)
+	nodeConfig.Moniker = moniker
+	nodeConfig.DBBackend = string(dbm.MemDBBackend)
+	nodeConfig.Consensus.TimeoutCommit = cfg.TimeoutCommit
+	nodeConfig.Instrumentation.Prometheus = false
+	nodeConfig.P2P.AddrBookStrict = false
+	nodeConfig.P2P.AllowDuplicateIP = true
+	// the RPC is served by startRPC instead of the node
+	nodeConfig.RPC.ListenAddress = ""
+
+	val := &NetworkValidator{
+		Moniker:   moniker,
+		Dir:       home,
+		ctx:       ctx,
+		appConfig: appConfig,
+	}
+
+	// NOTE: the app is connected to the node by a local client, so the
+	// proxy app address is never listened on.
+	p2pAddr, err := val.reservePort()
+	if err != nil {
+		return val, err
+	}
+	nodeConfig.P2P.ListenAddress = "tcp://" + p2pAddr
+	val.P2PAddress = nodeConfig.P2P.ListenAddress
+
+	rpcAddr, err := val.reservePort()
+	if err != nil {
+		return val, err
+	}
+	val.RPCAddress = "tcp://" + rpcAddr
+
+	apiAddr, err := val.reservePort()
+	if err != nil {
+		return val, err
+	}
+	appConfig.API.Enable = true
+	appConfig.API.Address = "tcp://" + apiAddr
+	val.APIAddress = "http://" + apiAddr
+
+	if appConfig.GRPC.Address, err = val.reservePort(); err != nil {
+		return val, err
+	}
+	appConfig.GRPC.Enable = true
+	val.GRPCAddress = appConfig.GRPC.Address
+
+	if appConfig.GRPCWeb.Address, err = val.reservePort(); err != nil {
+		return val, err
+	}
+	appConfig.GRPCWeb.Enable = true
+
+	kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, home, bufio.NewReader(os.Stdin))
+	if err != nil {
+		return val, err
+	}
+	val.ClientCtx = client.Context{}.
+		WithKeyringDir(home).
+		WithKeyring(kb).
+		WithHomeDir(home).
+		WithChainID(cfg.ChainID).
+		WithInterfaceRegistry(cfg.EncodingConfig.InterfaceRegistry).
+		WithCodec(cfg.EncodingConfig.Marshaler).
+		WithLegacyAmino(cfg.EncodingConfig.Amino).
+		WithTxConfig(cfg.EncodingConfig.TxConfig).
+		WithAccountRetriever(authtypes.AccountRetriever{})
+
+	return val, nil
+}
+
+// init generates the keys, gentxs, configs and genesis of the validators.
+func (n *Network) init() error {
+	cfg := n.Config
+
+	var (
+		genAccounts []authtypes.GenesisAccount
+		genBalances []banktypes.Balance
+	)
+
+	for i := range n.Validators {
+		nodeDirName := fmt.Sprintf("node%d", i)
+		nodeDir := filepath.Join(n.BaseDir, nodeDirName)
+		gentxsDir := filepath.Join(n.BaseDir, "gentxs")
+
+		val, err := n.newValidator(nodeDir, nodeDirName)
+		n.Validators[i] = val
+		if err != nil {
+			return err
+		}
+
+		val.NodeID, val.PubKey, err = genutil.InitializeNodeValidatorFiles(val.ctx.Config)
+		if err != nil {
+			return err
+		}
+
+		kb := val.ClientCtx.Keyring
+		addr, _, err := testutil.GenerateSaveCoinKey(kb, nodeDirName, "", true, hd.Secp256k1)
+		if err != nil {
+			return err
+		}
+		val.Address = addr
+		val.ValAddress = sdk.ValAddress(addr)
+
+		balances := sdk.NewCoins(
+			sdk.NewCoin(fmt.Sprintf("%stoken", nodeDirName), cfg.AccountTokens),
+			sdk.NewCoin(cfg.BondDenom, cfg.StakingTokens),
+		)
+		genBalances = append(genBalances, banktypes.Balance{Address: addr.String(), Coins: balances})
+		genAccounts = append(genAccounts, authtypes.NewBaseAccount(addr, nil, 0, 0))
+
+		createValMsg, err := stakingtypes.NewMsgCreateValidator(
+			val.ValAddress,
+			val.PubKey,
+			sdk.NewCoin(cfg.BondDenom, cfg.BondedTokens),
+			stakingtypes.NewDescription(nodeDirName, "", "", "", ""),
+			stakingtypes.NewCommissionRates(sdk.NewDecWithPrec(5, 1), sdk.OneDec(), sdk.OneDec()),
+			sdk.OneInt(),
+		)
+		if err != nil {
+			return err
+		}
+
+		p2pURL, err := url.Parse(val.P2PAddress)
+		if err != nil {
+			return err
+		}
+		memo := fmt.Sprintf("%s@%s", val.NodeID, p2pURL.Host)
+
+		txBuilder := cfg.EncodingConfig.TxConfig.NewTxBuilder()
+		if err := txBuilder.SetMsgs(createValMsg); err != nil {
+			return err
+		}
+		txBuilder.SetMemo(memo)
+
+		txFactory := tx.Factory{}.
+			WithChainID(cfg.ChainID).
+			WithMemo(memo).
+			WithKeybase(kb).
+			WithTxConfig(cfg.EncodingConfig.TxConfig)
+		if err := tx.Sign(txFactory, nodeDirName, txBuilder, true); err != nil {
+			return err
+		}
+
+		txBz, err := cfg.EncodingConfig.TxConfig.TxJSONEncoder()(txBuilder.GetTx())
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(gentxsDir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(gentxsDir, nodeDirName+".json"), txBz, 0o644); err != nil { // nolint: gosec
+			return err
+		}
+
+		srvconfig.WriteConfigFile(filepath.Join(nodeDir, "config", "app.toml"), val.appConfig)
+	}
+
+	return n.initGenesis(genAccounts, genBalances)
+}
+
+// initFromHomes loads the validators of the nodes in homes, connecting them to
+// each other.
+func (n *Network) initFromHomes(homes []string) error {
+	for i, home := range homes {
+		val, err := n.newValidator(home, filepath.Base(home))
+		n.Validators[i] = val
+		if err != nil {
+			return err
+		}
+
+		val.NodeID, val.PubKey, err = genutil.InitializeNodeValidatorFiles(val.ctx.Config)
+		if err != nil {
+			return err
+		}
+
+		genDoc, err := octypes.GenesisDocFromFile(val.ctx.Config.GenesisFile())
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			n.Config.ChainID = genDoc.ChainID
+		} else if genDoc.ChainID != n.Config.ChainID {
+			return fmt.Errorf("chain ID %s of %s differs from %s", genDoc.ChainID, home, n.Config.ChainID)
+		}
+		val.ClientCtx = val.ClientCtx.WithChainID(genDoc.ChainID)
+
+		if val.ValAddress, err = n.operatorOf(genDoc, val.PubKey); err != nil {
+			return fmt.Errorf("failed to find the validator of %s: %w", home, err)
+		}
+		val.Address = sdk.AccAddress(val.ValAddress)
+	}
+
+	// the peers in the configs of the nodes are of the ports they were
+	// initialized with
+	for _, val := range n.Validators {
+		var peers []string
+		for _, peer := range n.Validators {
+			if peer == val {
+				continue
+			}
+			p2pURL, err := url.Parse(peer.P2PAddress)
+			if err != nil {
+				return err
+			}
+			peers = append(peers, fmt.Sprintf("%s@%s", peer.NodeID, p2pURL.Host))
+		}
+		val.ctx.Config.P2P.PersistentPeers = strings.Join(peers, ",")
+	}
+	return nil
+}
+
+// operatorOf returns the operator of the validator of pubKey, created by a
+// gentx of the genesis.
+func (n *Network) operatorOf(genDoc *octypes.GenesisDoc, pubKey cryptotypes.PubKey) (sdk.ValAddress, error) {
+	txConfig := n.Config.EncodingConfig.TxConfig
+
+	var appState map[string]json.RawMessage
+	if err := json.Unmarshal(genDoc.AppState, &appState); err != nil {
+		return nil, err
+	}
+	genutilState := genutiltypes.GetGenesisStateFromAppState(n.Config.EncodingConfig.Marshaler, appState)
+	for _, genTx := range genutilState.GenTxs {
+		tx, err := txConfig.TxJSONDecoder()(genTx)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range tx.GetMsgs() {
+			msg, ok := msg.(*stakingtypes.MsgCreateValidator)
+			if !ok {
+				continue
+			}
+			if pk, ok := msg.Pubkey.GetCachedValue().(cryptotypes.PubKey); ok && pk.Equals(pubKey) {
+				return sdk.ValAddressFromBech32(msg.ValidatorAddress)
+			}
+		}
+	}
+	return nil, fmt.Errorf("no gentx creates the validator of %s", pubKey)
+}
+
+// initGenesis writes the genesis of the network, with the gentxs of the
+// validators, to the config directory of each node.
+func (n *Network) initGenesis(genAccounts []authtypes.GenesisAccount, genBalances []banktypes.Balance) error {
+	cfg := n.Config
+	cdc := cfg.EncodingConfig.Marshaler
+
+	genesisState := make(map[string]json.RawMessage, len(cfg.GenesisState))
+	for moduleName, genesis := range cfg.GenesisState {
+		genesisState[moduleName] = genesis
+	}
+
+	var authGenState authtypes.GenesisState
+	cdc.MustUnmarshalJSON(genesisState[authtypes.ModuleName], &authGenState)
+	accounts, err := authtypes.PackAccounts(genAccounts)
+	if err != nil {
+		return err
+	}
+	authGenState.Accounts = append(authGenState.Accounts, accounts...)
+	genesisState[authtypes.ModuleName] = cdc.MustMarshalJSON(&authGenState)
+
+	var bankGenState banktypes.GenesisState
+	cdc.MustUnmarshalJSON(genesisState[banktypes.ModuleName], &bankGenState)
+	bankGenState.Balances = append(bankGenState.Balances, genBalances...)
+	genesisState[banktypes.ModuleName] = cdc.MustMarshalJSON(&bankGenState)
+
+	var stakingGenState stakingtypes.GenesisState
+	cdc.MustUnmarshalJSON(genesisState[stakingtypes.ModuleName], &stakingGenState)
+	stakingGenState.Params.BondDenom = cfg.BondDenom
+	genesisState[stakingtypes.ModuleName] = cdc.MustMarshalJSON(&stakingGenState)
+
+	appState, err := json.MarshalIndent(genesisState, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	genDoc := octypes.GenesisDoc{ChainID: cfg.ChainID, AppState: appState}
+	genTime := osttime.Now()
+	gentxsDir := filepath.Join(n.BaseDir, "gentxs")
+	for _, val := range n.Validators {
+		nodeConfig := val.ctx.Config
+		if err := genDoc.SaveAs(nodeConfig.GenesisFile()); err != nil {
+			return err
+		}
+
+		initCfg := genutiltypes.NewInitConfig(cfg.ChainID, gentxsDir, val.NodeID, val.PubKey)
+		nodeAppState, err := genutil.GenAppStateFromConfig(cdc, cfg.EncodingConfig.TxConfig, nodeConfig, initCfg, genDoc, banktypes.GenesisBalancesIterator{})
+		if err != nil {
+			return err
+		}
+		if err := genutil.ExportGenesisFileWithTime(nodeConfig.GenesisFile(), cfg.ChainID, nil, nodeAppState, genTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start starts the nodes of the validators, and then their servers at once,
+// as each server is assumed started after a while.
+func (n *Network) start() error {
+	for _, val := range n.Validators {
+		if err := n.startNode(val); err != nil {
+			return fmt.Errorf("failed to start %s: %w", val.Moniker, err)
+		}
+	}
+
+	errs := make([]error, len(n.Validators))
+	var wg sync.WaitGroup
+	for i, val := range n.Validators {
+		wg.Add(1)
+		go func(i int, val *NetworkValidator) {
+			defer wg.Done()
+			if err := val.startServers(); err != nil {
+				errs[i] = fmt.Errorf("failed to start the servers of %s: %w", val.Moniker, err)
+			}
+		}(i, val)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startNode starts the app and the node of val.
+func (n *Network) startNode(val *NetworkValidator) error {
+	nodeConfig := val.ctx.Config
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(nodeConfig.NodeKeyFile())
+	if err != nil {
+		return err
+	}
+
+	app := linkapp.NewLinkApp(
+		val.ctx.Logger, dbm.NewMemDB(), nil, true, map[int64]bool{}, val.Dir, 0,
+		n.Config.EncodingConfig, EmptyAppOptions{}, nil,
+		baseapp.SetPruning(storetypes.NewPruningOptionsFromString(val.appConfig.Pruning)),
+		baseapp.SetMinGasPrices(val.appConfig.MinGasPrices),
+	)
+	val.app = app
+
+	// the ports are released as late as possible, right before the node and
+	// the servers listen on them.
+	val.releasePorts()
+	val.node, err = node.NewNode(
+		nodeConfig,
+		privval.LoadOrGenFilePV(nodeConfig.PrivValidatorKeyFile(), nodeConfig.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.NewLocalClientCreator(app),
+		node.DefaultGenesisDocProviderFunc(nodeConfig),
+		node.DefaultDBProvider,
+		node.DefaultMetricsProvider(nodeConfig.Instrumentation),
+		val.ctx.Logger,
+	)
+	if err != nil {
+		return err
+	}
+	return val.node.Start()
+}
+
+// startServers starts the RPC, REST and gRPC servers of the node of val.
+func (val *NetworkValidator) startServers() error {
+	app := val.app
+	if err := val.startRPC(val.ctx.Logger.With("module", "rpc-server")); err != nil {
+		return err
+	}
+	rpcClient, err := rpchttp.New(val.RPCAddress, "/websocket")
+	if err != nil {
+		return err
+	}
+	val.RPCClient = rpcClient
+	val.ClientCtx = val.ClientCtx.WithNodeURI(val.RPCAddress).WithClient(rpcClient)
+	app.RegisterTxService(val.ClientCtx)
+	app.RegisterTendermintService(val.ClientCtx)
+	app.RegisterNodeService(val.ClientCtx)
+
+	val.api = api.New(val.ClientCtx, val.ctx.Logger.With("module", "api-server"))
+	app.RegisterAPIRoutes(val.api, val.appConfig.API)
+	errCh := make(chan error, 1)
+	go func() {
+		if err := val.api.Start(*val.appConfig); err != nil {
+			errCh <- err
+		}
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(servertypes.ServerStartTime): // assume server started successfully
+	}
+
+	val.grpc, err = servergrpc.StartGRPCServer(val.ClientCtx, app, val.appConfig.GRPC.Address)
+	if err != nil {
+		return err
+	}
+	val.grpcWeb, err = servergrpc.StartGRPCWeb(val.grpc, *val.appConfig)
+	return err
+}
+
+// LatestHeight returns the latest height of the network.
+func (n *Network) LatestHeight() (int64, error) {
+	status, err := n.Validators[0].RPCClient.Status(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// WaitForHeight waits for the network to reach height h, returning the latest
+// height. It fails if h is not reached within timeout.
+func (n *Network) WaitForHeight(h int64, timeout time.Duration) (int64, error) {
+	ticker := time.NewTicker(n.Config.TimeoutCommit / 2)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	var latestHeight int64
+	for {
+		select {
+		case <-deadline:
+			return latestHeight, fmt.Errorf("timeout exceeded waiting for height %d", h)
+		case <-ticker.C:
+			if height, err := n.LatestHeight(); err == nil {
+				latestHeight = height
+				if latestHeight >= h {
+					return latestHeight, nil
+				}
+			}
+		}
+	}
+}
+
+// WaitForNextBlock waits for the block after the latest one to be committed.
+func (n *Network) WaitForNextBlock() error {
+	height, err := n.LatestHeight()
+	if err != nil {
+		return err
+	}
+	_, err = n.WaitForHeight(height+1, 10*n.Config.TimeoutCommit+10*time.Second)
+	return err
+}
+
+// Stop stops the validators and their servers. The files of the nodes are left
+// to the caller. Calling Stop more than once has no effect.
+func (n *Network) Stop() {
+	n.stopOnce.Do(n.stop)
+}
+
+func (n *Network) stop() {
+	for _, val := range n.Validators {
+		if val == nil {
+			continue
+		}
+		val.releasePorts()
+		if val.node != nil && val.node.IsRunning() {
+			_ = val.node.Stop()
+			val.node.Wait()
+		}
+		if val.rpc != nil {
+			_ = val.rpc.Close()
+		}
+		if val.api != nil {
+			_ = val.api.Close()
+		}
+		if val.grpc != nil {
+			val.grpc.Stop()
+		}
+		if val.grpcWeb != nil {
+			_ = val.grpcWeb.Close()
+		}
+		if val.app != nil {
+			_ = val.app.Close()
+		}
+	}
+}
+
+// reservePort binds an ephemeral port of the loopback interface, kept bound
+// until releasePorts, and returns its address.
+func (val *NetworkValidator) reservePort() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	val.ports = append(val.ports, ln)
+	return ln.Addr().String(), nil
+}
+
+// releasePorts unbinds the ports reserved by reservePort.
+func (val *NetworkValidator) releasePorts() {
+	for _, ln := range val.ports {
+		_ = ln.Close()
+	}
+	val.ports = nil
+}
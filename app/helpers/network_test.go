@@ -0,0 +1,137 @@
+package helpers
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/Finschia/ostracon/privval"
+	rpccore "github.com/Finschia/ostracon/rpc/core"
+
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+)
+
+func TestNetwork(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultNetworkConfig()
+	cfg.NumValidators = 2
+	cfg.TimeoutCommit = 100 * time.Millisecond
+
+	network, err := NewNetwork(t.TempDir(), cfg)
+	require.NoError(t, err)
+	defer network.Stop()
+
+	_, err = network.WaitForHeight(3, 30*time.Second)
+	require.NoError(t, err)
+
+	// every validator serves its own node
+	for _, val := range network.Validators {
+		status, err := val.RPCClient.Status(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, val.NodeID, string(status.NodeInfo.DefaultNodeID))
+
+		validators, err := val.RPCClient.Validators(context.Background(), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, validators.Validators, 2)
+
+		conn, err := grpc.Dial(val.GRPCAddress, grpc.WithInsecure()) // nolint: staticcheck
+		require.NoError(t, err)
+		defer conn.Close()
+		res, err := banktypes.NewQueryClient(conn).Balance(context.Background(), &banktypes.QueryBalanceRequest{
+			Address: network.Validators[1].Address.String(),
+			Denom:   "node1token",
+		})
+		require.NoError(t, err)
+		require.Equal(t, cfg.AccountTokens, res.Balance.Amount)
+	}
+}
+
+func TestNetworksInParallel(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultNetworkConfig()
+	cfg.NumValidators = 1
+	cfg.TimeoutCommit = 100 * time.Millisecond
+
+	first, err := NewNetwork(t.TempDir(), cfg)
+	require.NoError(t, err)
+	defer first.Stop()
+
+	cfg.ChainID = "other-chain"
+	second, err := NewNetwork(t.TempDir(), cfg)
+	require.NoError(t, err)
+	defer second.Stop()
+
+	_, err = first.WaitForHeight(2, 30*time.Second)
+	require.NoError(t, err)
+	_, err = second.WaitForHeight(2, 30*time.Second)
+	require.NoError(t, err)
+
+	status, err := second.Validators[0].RPCClient.Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "other-chain", status.NodeInfo.Network)
+}
+
+func TestNetworkFromHomes(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultNetworkConfig()
+	cfg.NumValidators = 2
+	cfg.TimeoutCommit = 100 * time.Millisecond
+
+	// initialize the homes by a network
+	network, err := NewNetwork(t.TempDir(), cfg)
+	require.NoError(t, err)
+	network.Stop()
+
+	// the validators sign the new chain from its start, as those of new homes
+	homes := make([]string, len(network.Validators))
+	for i, val := range network.Validators {
+		homes[i] = val.Dir
+		nodeConfig := val.ctx.Config
+		privval.LoadFilePV(nodeConfig.PrivValidatorKeyFile(), nodeConfig.PrivValidatorStateFile()).Reset()
+	}
+	cfg.ChainID = ""
+	restarted, err := NewNetworkFromHomes(homes, cfg)
+	require.NoError(t, err)
+	defer restarted.Stop()
+
+	_, err = restarted.WaitForHeight(3, 30*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, network.Config.ChainID, restarted.Config.ChainID)
+	for i, val := range restarted.Validators {
+		require.Equal(t, network.Validators[i].ValAddress, val.ValAddress)
+		require.Equal(t, network.Validators[i].NodeID, val.NodeID)
+	}
+}
+
+func TestNetworkStopTwice(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultNetworkConfig()
+	cfg.NumValidators = 1
+	cfg.TimeoutCommit = 100 * time.Millisecond
+
+	network, err := NewNetwork(t.TempDir(), cfg)
+	require.NoError(t, err)
+	network.Stop()
+	require.NotPanics(t, network.Stop)
+}
+
+func TestRPCRoutes(t *testing.T) {
+	var expected, actual []string
+	for name := range rpccore.Routes {
+		expected = append(expected, name)
+	}
+	for name := range rpcRoutes {
+		actual = append(actual, name)
+	}
+	sort.Strings(expected)
+	sort.Strings(actual)
+	require.Equal(t, expected, actual)
+}
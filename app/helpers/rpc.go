@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Finschia/ostracon/libs/log"
+	tmpubsub "github.com/Finschia/ostracon/libs/pubsub"
+	"github.com/Finschia/ostracon/node"
+	rpccore "github.com/Finschia/ostracon/rpc/core"
+	rpcserver "github.com/Finschia/ostracon/rpc/jsonrpc/server"
+)
+
+// rpcLock serializes the RPC calls to the nodes of the process. ostracon keeps
+// the node its RPC serves in a global environment, so each call sets it to its
+// own node and holds it until it returns, e.g. broadcast_tx_commit until the
+// tx is committed.
+var rpcLock = new(sync.Mutex)
+
+// rpcRoute is a route of rpccore.Routes with the names of its args, which
+// RPCFunc does not expose.
+type rpcRoute struct {
+	f    interface{}
+	args string
+	ws   bool
+}
+
+var rpcRoutes = map[string]rpcRoute{
+	"subscribe":       {rpccore.Subscribe, "query", true},
+	"unsubscribe":     {rpccore.Unsubscribe, "query", true},
+	"unsubscribe_all": {rpccore.UnsubscribeAll, "", true},
+
+	"health":               {rpccore.Health, "", false},
+	"status":               {rpccore.Status, "", false},
+	"net_info":             {rpccore.NetInfo, "", false},
+	"blockchain":           {rpccore.BlockchainInfo, "minHeight,maxHeight", false},
+	"genesis":              {rpccore.Genesis, "", false},
+	"genesis_chunked":      {rpccore.GenesisChunked, "chunk", false},
+	"block":                {rpccore.Block, "height", false},
+	"block_by_hash":        {rpccore.BlockByHash, "hash", false},
+	"block_results":        {rpccore.BlockResults, "height", false},
+	"commit":               {rpccore.Commit, "height", false},
+	"check_tx":             {rpccore.CheckTx, "tx", false},
+	"tx":                   {rpccore.Tx, "hash,prove", false},
+	"tx_search":            {rpccore.TxSearch, "query,prove,page,per_page,order_by", false},
+	"block_search":         {rpccore.BlockSearch, "query,page,per_page,order_by", false},
+	"validators":           {rpccore.Validators, "height,page,per_page", false},
+	"dump_consensus_state": {rpccore.DumpConsensusState, "", false},
+	"consensus_state":      {rpccore.ConsensusState, "", false},
+	"consensus_params":     {rpccore.ConsensusParams, "height", false},
+	"unconfirmed_txs":      {rpccore.UnconfirmedTxs, "limit", false},
+	"num_unconfirmed_txs":  {rpccore.NumUnconfirmedTxs, "", false},
+
+	"broadcast_tx_commit": {rpccore.BroadcastTxCommit, "tx", false},
+	"broadcast_tx_sync":   {rpccore.BroadcastTxSync, "tx", false},
+	"broadcast_tx_async":  {rpccore.BroadcastTxAsync, "tx", false},
+
+	"abci_query": {rpccore.ABCIQuery, "path,data,height,prove", false},
+	"abci_info":  {rpccore.ABCIInfo, "", false},
+
+	"broadcast_evidence": {rpccore.BroadcastEvidence, "evidence", false},
+}
+
+// nodeRoutes returns the routes of the RPC serving n.
+func nodeRoutes(n *node.Node) map[string]*rpcserver.RPCFunc {
+	routes := make(map[string]*rpcserver.RPCFunc, len(rpcRoutes))
+	for name, route := range rpcRoutes {
+		f := reflect.ValueOf(route.f)
+		fnType := f.Type()
+		wrapped := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+			rpcLock.Lock()
+			defer rpcLock.Unlock()
+
+			if err := n.ConfigureRPC(); err != nil {
+				// the routes return a result and an error
+				return []reflect.Value{reflect.Zero(fnType.Out(0)), reflect.ValueOf(&err).Elem()}
+			}
+			return f.Call(args)
+		}).Interface()
+
+		if route.ws {
+			routes[name] = rpcserver.NewWSRPCFunc(wrapped, route.args)
+		} else {
+			routes[name] = rpcserver.NewRPCFunc(wrapped, route.args)
+		}
+	}
+	return routes
+}
+
+// startRPC serves the RPC of the node of val on its RPCAddress, as the node
+// would if it listened on it.
+func (val *NetworkValidator) startRPC(logger log.Logger) error {
+	rpcConfig := val.ctx.Config.RPC
+
+	config := rpcserver.DefaultConfig()
+	config.MaxBodyBytes = rpcConfig.MaxBodyBytes
+	config.MaxHeaderBytes = rpcConfig.MaxHeaderBytes
+	config.MaxOpenConnections = rpcConfig.MaxOpenConnections
+	if config.WriteTimeout <= rpcConfig.TimeoutBroadcastTxCommit {
+		config.WriteTimeout = rpcConfig.TimeoutBroadcastTxCommit + time.Second
+	}
+
+	routes := nodeRoutes(val.node)
+	mux := http.NewServeMux()
+	wmLogger := logger.With("protocol", "websocket")
+	wm := rpcserver.NewWebsocketManager(routes,
+		rpcserver.OnDisconnect(func(remoteAddr string) {
+			err := val.node.EventBus().UnsubscribeAll(context.Background(), remoteAddr)
+			if err != nil && err != tmpubsub.ErrSubscriptionNotFound {
+				wmLogger.Error("Failed to unsubscribe addr from events", "addr", remoteAddr, "err", err)
+			}
+		}),
+		rpcserver.ReadLimit(config.MaxBodyBytes),
+		rpcserver.WriteChanCapacity(rpcConfig.WebSocketWriteBufferSize),
+	)
+	wm.SetLogger(wmLogger)
+	mux.HandleFunc("/websocket", wm.WebsocketHandler)
+	rpcserver.RegisterRPCFuncs(mux, routes, logger)
+
+	listener, err := rpcserver.Listen(val.RPCAddress, config)
+	if err != nil {
+		return err
+	}
+	val.rpc = listener
+	go func() {
+		if err := rpcserver.Serve(listener, mux, logger, config); err != nil && !errors.Is(err, net.ErrClosed) {
+			logger.Error("Error serving RPC", "err", err)
+		}
+	}()
+	return nil
+}
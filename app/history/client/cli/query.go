@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/version"
+
+	"github.com/Finschia/finschia/app/history"
+)
+
+// flags of the history query
+const (
+	FlagContractID = "contract-id"
+	FlagPageKey    = "page-key"
+	FlagLimit      = "limit"
+	FlagAscending  = "ascending"
+)
+
+// AddQueryCommands adds the history query to the query commands of token and
+// collection under the query command.
+func AddQueryCommands(queryCmd *cobra.Command) {
+	for _, cmd := range queryCmd.Commands() {
+		switch module := cmd.Name(); module {
+		case history.ModuleToken, history.ModuleCollection:
+			cmd.AddCommand(NewQueryCmdHistory(module))
+		}
+	}
+}
+
+// NewQueryCmdHistory returns the query of the transfer history of the module.
+func NewQueryCmdHistory(module string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history [address]",
+		Args:  cobra.ExactArgs(1),
+		Short: fmt.Sprintf("query the transfer history of the %s module by a given address", module),
+		Long: fmt.Sprintf(`Query the transfers of the %s module involving the address as the sender,
+the receiver or the operator, including mint and burn, from the newest one.
+
+The history is kept off the consensus state, by the nodes enabling it in app.toml.`, module),
+		Example: fmt.Sprintf(`$ %s query %s history <address> --%s <contract-id>`, version.AppName, module, FlagContractID),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &history.QueryHistoryRequest{
+				Address: args[0],
+				Module:  module,
+			}
+			if req.ContractId, err = cmd.Flags().GetString(FlagContractID); err != nil {
+				return err
+			}
+			if req.Limit, err = cmd.Flags().GetUint64(FlagLimit); err != nil {
+				return err
+			}
+			if req.Ascending, err = cmd.Flags().GetBool(FlagAscending); err != nil {
+				return err
+			}
+			pageKey, err := cmd.Flags().GetString(FlagPageKey)
+			if err != nil {
+				return err
+			}
+			if req.Key, err = base64.StdEncoding.DecodeString(pageKey); err != nil {
+				return err
+			}
+
+			queryClient := history.NewQueryClient(clientCtx)
+			res, err := queryClient.History(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().String(FlagContractID, "", "the contract id of the transfers")
+	cmd.Flags().String(FlagPageKey, "", "the next key of the former page, in base64")
+	cmd.Flags().Uint64(FlagLimit, history.DefaultLimit, "the maximum number of the transfers")
+	cmd.Flags().Bool(FlagAscending, false, "from the oldest transfer")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
@@ -0,0 +1,78 @@
+package history
+
+import (
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+)
+
+// transferEvents are the types of the typed events of the transfers
+var transferEvents = map[string]bool{}
+
+func init() {
+	for _, event := range []proto.Message{
+		&token.EventSent{},
+		&token.EventMinted{},
+		&token.EventBurned{},
+		&collection.EventSent{},
+		&collection.EventMintedFT{},
+		&collection.EventMintedNFT{},
+		&collection.EventBurned{},
+		&collection.EventOwnerChanged{},
+	} {
+		transferEvents[proto.MessageName(event)] = true
+	}
+}
+
+// eventTransfer returns the transfer of the event if it is of a transfer.
+func eventTransfer(event abci.Event) (*Transfer, error) {
+	if !transferEvents[event.Type] {
+		return nil, nil
+	}
+	msg, err := sdk.ParseTypedEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Transfer{Type: event.Type}
+	tokenAmount := func(amount sdk.Int) []*Coin {
+		return []*Coin{{Amount: amount.String()}}
+	}
+	collAmount := func(amount []collection.Coin) []*Coin {
+		coins := make([]*Coin, len(amount))
+		for i, coin := range amount {
+			coins[i] = &Coin{TokenId: coin.TokenId, Amount: coin.Amount.String()}
+		}
+		return coins
+	}
+
+	switch e := msg.(type) {
+	case *token.EventSent:
+		t.Module, t.ContractId, t.Operator, t.From, t.To, t.Amount = ModuleToken, e.ContractId, e.Operator, e.From, e.To, tokenAmount(e.Amount)
+	case *token.EventMinted:
+		t.Module, t.ContractId, t.Operator, t.To, t.Amount = ModuleToken, e.ContractId, e.Operator, e.To, tokenAmount(e.Amount)
+	case *token.EventBurned:
+		t.Module, t.ContractId, t.Operator, t.From, t.Amount = ModuleToken, e.ContractId, e.Operator, e.From, tokenAmount(e.Amount)
+
+	case *collection.EventSent:
+		t.Module, t.ContractId, t.Operator, t.From, t.To, t.Amount = ModuleCollection, e.ContractId, e.Operator, e.From, e.To, collAmount(e.Amount)
+	case *collection.EventMintedFT:
+		t.Module, t.ContractId, t.Operator, t.To, t.Amount = ModuleCollection, e.ContractId, e.Operator, e.To, collAmount(e.Amount)
+	case *collection.EventMintedNFT:
+		t.Module, t.ContractId, t.Operator, t.To = ModuleCollection, e.ContractId, e.Operator, e.To
+		for _, nft := range e.Tokens {
+			t.Amount = append(t.Amount, &Coin{TokenId: nft.TokenId, Amount: sdk.OneInt().String()})
+		}
+	case *collection.EventBurned:
+		t.Module, t.ContractId, t.Operator, t.From, t.Amount = ModuleCollection, e.ContractId, e.Operator, e.From, collAmount(e.Amount)
+	case *collection.EventOwnerChanged:
+		// a descendant of an nft sent along with it
+		t.Module, t.ContractId, t.From, t.To = ModuleCollection, e.ContractId, e.From, e.To
+		t.Amount = []*Coin{{TokenId: e.TokenId, Amount: sdk.OneInt().String()}}
+	}
+
+	return t, nil
+}
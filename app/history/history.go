@@ -0,0 +1,242 @@
+// Package history keeps the history of the transfers of the tokens of token
+// and collection per account, including mint, burn and operator transfers, in
+// a database of the node off the consensus state.
+//
+// The transfers are collected from the typed events of the modules by a
+// streaming service of LinkApp, and written on commit. They are served by the
+// gRPC service finschia.history.v1.Query, over ABCI queries as well as the
+// gRPC server, and over REST by its gateway. The messages and the service are
+// generated from proto/finschia/history/v1.
+package history
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/spf13/cast"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/client/flags"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/address"
+)
+
+// options of the history in app.toml
+const (
+	OptEnable = "history.enable"
+)
+
+// DBName is the name of the database of the history in the data directory.
+const DBName = "history"
+
+// modules of the transfers
+const (
+	ModuleToken      = "token"
+	ModuleCollection = "collection"
+)
+
+// limits of the transfers of a page
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// prefixes of the keys of the store
+var (
+	// transferKeyPrefix | height | seq -> Transfer
+	transferKeyPrefix = []byte{0x00}
+	// accountKeyPrefix | len(address) | address | height | seq, where the
+	// address is in bytes so that any case of its bech32 matches
+	accountKeyPrefix = []byte{0x01}
+	// contractKeyPrefix | len(address) | address | len(contract id) | contract id | height | seq
+	contractKeyPrefix = []byte{0x02}
+)
+
+// positionLen is the length of the position of a transfer, its height and its
+// sequence in the block.
+const positionLen = 8 + 4
+
+// Store is the store of the history.
+type Store struct {
+	db dbm.DB
+}
+
+// NewStore returns the store of the history over the database.
+func NewStore(db dbm.DB) *Store {
+	return &Store{db: db}
+}
+
+// NewServiceFromOptions opens the store of the history in the data directory
+// of the node, returning the streaming service feeding it.
+func NewServiceFromOptions(opts servertypes.AppOptions) (*Store, *Service, error) {
+	dir := filepath.Join(cast.ToString(opts.Get(flags.FlagHome)), "data")
+	db, err := dbm.NewDB(DBName, dbm.GoLevelDBBackend, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store := NewStore(db)
+	return store, NewService(store), nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// write writes the transfers of the block of the height, in the order of
+// their sequences. The transfers of a block replayed overwrite the former ones.
+func (s *Store) write(height int64, transfers []*Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	for seq, transfer := range transfers {
+		pos := position(height, uint32(seq))
+		bz, err := proto.Marshal(transfer)
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(append(transferKeyPrefix, pos...), bz); err != nil {
+			return err
+		}
+
+		addrs, err := transfer.accounts()
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			if err := batch.Set(accountKey(addr, pos), []byte{}); err != nil {
+				return err
+			}
+			if err := batch.Set(contractKey(addr, transfer.ContractId, pos), []byte{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return batch.Write()
+}
+
+// History returns a page of the transfers of the request.
+//
+// NOTE: the transfers are indexed by the contract ids regardless of their
+// modules, so a page filtered by the module may hold less transfers than the
+// limit even if it is not the last one.
+func (s *Store) History(req *QueryHistoryRequest) (*QueryHistoryResponse, error) {
+	addr, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Key) != 0 && len(req.Key) != positionLen {
+		return nil, fmt.Errorf("invalid page key of %d bytes", len(req.Key))
+	}
+	limit := int(req.Limit)
+	switch {
+	case limit == 0:
+		limit = DefaultLimit
+	case limit > MaxLimit:
+		limit = MaxLimit
+	}
+
+	prefix := accountKey(addr, nil)
+	if req.ContractId != "" {
+		prefix = contractKey(addr, req.ContractId, nil)
+	}
+	start, end := prefix, storetypes.PrefixEndBytes(prefix)
+
+	var it dbm.Iterator
+	if req.Ascending {
+		if len(req.Key) != 0 {
+			start = append(prefix, req.Key...)
+		}
+		it, err = s.db.Iterator(start, end)
+	} else {
+		if len(req.Key) != 0 {
+			// the key is inclusive
+			end = append(append(prefix, req.Key...), 0)
+		}
+		it, err = s.db.ReverseIterator(start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	res := &QueryHistoryResponse{Transfers: []*Transfer{}}
+	for count := 0; it.Valid(); it.Next() {
+		pos := it.Key()[len(prefix):]
+		if count == limit {
+			res.NextKey = append([]byte(nil), pos...)
+			break
+		}
+		count++
+
+		bz, err := s.db.Get(append(transferKeyPrefix, pos...))
+		if err != nil {
+			return nil, err
+		}
+		var transfer Transfer
+		if err := proto.Unmarshal(bz, &transfer); err != nil {
+			return nil, err
+		}
+		if req.Module != "" && transfer.Module != req.Module {
+			continue
+		}
+		res.Transfers = append(res.Transfers, &transfer)
+	}
+
+	return res, it.Error()
+}
+
+// accounts returns the accounts involved in the transfer.
+func (m *Transfer) accounts() ([]sdk.AccAddress, error) {
+	var addrs []sdk.AccAddress
+	for _, bech32 := range []string{m.From, m.To, m.Operator} {
+		if bech32 == "" {
+			continue
+		}
+		addr, err := sdk.AccAddressFromBech32(bech32)
+		if err != nil {
+			return nil, err
+		}
+		dup := false
+		for _, a := range addrs {
+			dup = dup || a.Equals(addr)
+		}
+		if !dup {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs, nil
+}
+
+func position(height int64, seq uint32) []byte {
+	pos := make([]byte, positionLen)
+	binary.BigEndian.PutUint64(pos, uint64(height))
+	binary.BigEndian.PutUint32(pos[8:], seq)
+	return pos
+}
+
+func accountKey(addr sdk.AccAddress, pos []byte) []byte {
+	key := append(append([]byte{}, accountKeyPrefix...), address.MustLengthPrefix(addr)...)
+	return append(key, pos...)
+}
+
+func contractKey(addr sdk.AccAddress, contractID string, pos []byte) []byte {
+	key := append(append([]byte{}, contractKeyPrefix...), address.MustLengthPrefix(addr)...)
+	key = append(key, lengthPrefixed(contractID)...)
+	return append(key, pos...)
+}
+
+func lengthPrefixed(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
@@ -0,0 +1,179 @@
+package history_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+	"github.com/Finschia/ostracon/libs/log"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/crypto/keys/secp256k1"
+	"github.com/Finschia/finschia-sdk/server/api"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+
+	"github.com/Finschia/finschia/app/history"
+	"github.com/Finschia/finschia/app/testutil"
+)
+
+var (
+	alice   = sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+	bob     = sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+	charlie = sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+)
+
+// commitBlock streams a block of the events of BeginBlock, a tx and EndBlock
+// into the service.
+func commitBlock(t *testing.T, service *history.Service, height int64, begin, tx, end []abci.Event) {
+	ctx := sdk.Context{}
+	require.NoError(t, service.ListenBeginBlock(ctx, ocabci.RequestBeginBlock{Header: tmproto.Header{Height: height}}, abci.ResponseBeginBlock{Events: begin}))
+	require.NoError(t, service.ListenDeliverTx(ctx, abci.RequestDeliverTx{Tx: []byte{byte(height)}}, abci.ResponseDeliverTx{Events: tx}))
+	require.NoError(t, service.ListenEndBlock(ctx, abci.RequestEndBlock{Height: height}, abci.ResponseEndBlock{Events: end}))
+	service.ListenStartCommit()
+	require.NoError(t, service.ListenCommit())
+}
+
+func testServer(t *testing.T) history.QueryServer {
+	store := history.NewStore(dbm.NewMemDB())
+	service := history.NewService(store)
+	t.Cleanup(func() { require.NoError(t, service.Close()) })
+
+	for i := 0; i < 2; i++ {
+		// the blocks are replayed without duplicates
+		commitBlock(t, service, 2, nil, []abci.Event{
+			{Type: "message", Attributes: []abci.EventAttribute{{Key: []byte("sender"), Value: []byte(alice)}}},
+			testutil.TypedEvent(t, &token.EventMinted{ContractId: "fee1dead", Operator: alice, To: alice, Amount: sdk.NewInt(100)}),
+			testutil.TypedEvent(t, &token.EventSent{ContractId: "fee1dead", Operator: charlie, From: alice, To: bob, Amount: sdk.NewInt(10)}),
+		}, nil)
+	}
+	commitBlock(t, service, 3, []abci.Event{
+		testutil.TypedEvent(t, &token.EventBurned{ContractId: "fee1dead", Operator: bob, From: bob, Amount: sdk.NewInt(1)}),
+	}, []abci.Event{
+		testutil.TypedEvent(t, &collection.EventSent{ContractId: "deadbeef", Operator: bob, From: bob, To: alice, Amount: []collection.Coin{collection.NewCoin("0000000100000000", sdk.NewInt(5))}}),
+	}, []abci.Event{
+		testutil.TypedEvent(t, &collection.EventMintedNFT{ContractId: "deadbeef", Operator: alice, To: bob, Tokens: []collection.NFT{{TokenId: "1000000100000001"}}}),
+	})
+
+	return history.NewQueryServer(store)
+}
+
+func TestHistory(t *testing.T) {
+	server := testServer(t)
+	ctx := context.Background()
+
+	res, err := server.History(ctx, &history.QueryHistoryRequest{Address: bob})
+	require.NoError(t, err)
+	require.Len(t, res.Transfers, 4)
+	require.Nil(t, res.NextKey)
+
+	minted := res.Transfers[0]
+	require.Equal(t, int64(3), minted.Height)
+	require.Empty(t, minted.TxHash)
+	require.Equal(t, uint32(2), minted.EventIndex)
+	require.Equal(t, history.ModuleCollection, minted.Module)
+	require.Equal(t, "lbm.collection.v1.EventMintedNFT", minted.Type)
+	require.Equal(t, []*history.Coin{{TokenId: "1000000100000001", Amount: "1"}}, minted.Amount)
+
+	sent := res.Transfers[3]
+	require.Equal(t, int64(2), sent.Height)
+	require.Len(t, sent.TxHash, 64)
+	require.Equal(t, uint32(2), sent.EventIndex)
+	require.Equal(t, "lbm.token.v1.EventSent", sent.Type)
+	require.Equal(t, []string{charlie, alice, bob}, []string{sent.Operator, sent.From, sent.To})
+	require.Equal(t, []*history.Coin{{Amount: "10"}}, sent.Amount)
+
+	// the address in upper case
+	res, err = server.History(ctx, &history.QueryHistoryRequest{Address: strings.ToUpper(bob)})
+	require.NoError(t, err)
+	require.Len(t, res.Transfers, 4)
+
+	// the operator
+	res, err = server.History(ctx, &history.QueryHistoryRequest{Address: charlie})
+	require.NoError(t, err)
+	require.Equal(t, []*history.Transfer{sent}, res.Transfers)
+
+	// by the module and the contract
+	res, err = server.History(ctx, &history.QueryHistoryRequest{Address: bob, Module: history.ModuleToken})
+	require.NoError(t, err)
+	require.Len(t, res.Transfers, 2)
+	res, err = server.History(ctx, &history.QueryHistoryRequest{Address: alice, ContractId: "fee1dead"})
+	require.NoError(t, err)
+	require.Len(t, res.Transfers, 2)
+	require.Equal(t, "lbm.token.v1.EventSent", res.Transfers[0].Type)
+	require.Equal(t, "lbm.token.v1.EventMinted", res.Transfers[1].Type)
+
+	// pages in the both orders
+	for _, ascending := range []bool{false, true} {
+		var heights []int64
+		req := &history.QueryHistoryRequest{Address: bob, Limit: 3, Ascending: ascending}
+		for {
+			res, err := server.History(ctx, req)
+			require.NoError(t, err)
+			for _, transfer := range res.Transfers {
+				heights = append(heights, transfer.Height)
+			}
+			if res.NextKey == nil {
+				break
+			}
+			req.Key = res.NextKey
+		}
+		if ascending {
+			require.Equal(t, []int64{2, 3, 3, 3}, heights)
+		} else {
+			require.Equal(t, []int64{3, 3, 3, 2}, heights)
+		}
+	}
+
+	// invalid requests
+	for name, req := range map[string]*history.QueryHistoryRequest{
+		"address": {Address: "invalid"},
+		"module":  {Address: bob, Module: "bank"},
+		"key":     {Address: bob, Key: []byte{1}},
+	} {
+		_, err := server.History(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+}
+
+func TestGateway(t *testing.T) {
+	apiSvr := api.New(client.Context{}, log.NewNopLogger())
+	require.NoError(t, history.RegisterQueryHandlerServer(context.Background(), apiSvr.GRPCGatewayRouter, testServer(t)))
+
+	get := func(url string, code int) history.QueryHistoryResponse {
+		w := httptest.NewRecorder()
+		apiSvr.GRPCGatewayRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, code, w.Code, w.Body.String())
+
+		var res history.QueryHistoryResponse
+		if code == http.StatusOK {
+			require.NoError(t, jsonpb.Unmarshal(w.Body, &res))
+		}
+		return res
+	}
+
+	path := "/finschia/history/v1/accounts/" + bob + "/transfers"
+	res := get(path+"?module=collection&limit=1", http.StatusOK)
+	require.Len(t, res.Transfers, 1)
+	require.NotNil(t, res.NextKey)
+	res = get(path+"?module=collection&key="+url.QueryEscape(base64.StdEncoding.EncodeToString(res.NextKey)), http.StatusOK)
+	require.Len(t, res.Transfers, 1)
+	require.Equal(t, "lbm.collection.v1.EventSent", res.Transfers[0].Type)
+
+	get(path+"?limit=-1", http.StatusBadRequest)
+	get("/finschia/history/v1/accounts/invalid/transfers", http.StatusBadRequest)
+}
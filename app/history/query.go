@@ -0,0 +1,42 @@
+package history
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ QueryServer = (*queryServer)(nil)
+
+type queryServer struct {
+	store *Store
+}
+
+// NewQueryServer returns the QueryServer over the store of the history.
+func NewQueryServer(store *Store) QueryServer {
+	return &queryServer{store: store}
+}
+
+func (s *queryServer) History(_ context.Context, req *QueryHistoryRequest) (*QueryHistoryResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	switch req.Module {
+	case "", ModuleToken, ModuleCollection:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid module %q", req.Module)
+	}
+
+	res, err := s.store.History(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, nil
+}
+
+func fmtHash(bz []byte) string {
+	return strings.ToUpper(hex.EncodeToString(bz))
+}
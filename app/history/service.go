@@ -0,0 +1,114 @@
+package history
+
+import (
+	"sync"
+
+	"github.com/Finschia/ostracon/crypto/tmhash"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/app/streaming"
+)
+
+var _ streaming.Service = (*Service)(nil)
+
+// Service is the streaming service collecting the transfers of a block from
+// the events of BeginBlock, the txs and EndBlock, which are written into the
+// store on commit.
+type Service struct {
+	store *Store
+
+	mtx       sync.Mutex
+	height    int64
+	events    uint32 // events of the block seen
+	transfers []*Transfer
+}
+
+// NewService returns the streaming service writing into the store.
+func NewService(store *Store) *Service {
+	return &Service{store: store}
+}
+
+// Listeners satisfies the baseapp.StreamingService interface. The service
+// listens to no store.
+func (s *Service) Listeners() map[storetypes.StoreKey][]storetypes.WriteListener {
+	return nil
+}
+
+// ListenBeginBlock satisfies the baseapp.ABCIListener interface
+func (s *Service) ListenBeginBlock(_ sdk.Context, req ocabci.RequestBeginBlock, res abci.ResponseBeginBlock) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.height = req.Header.Height
+	s.events = 0
+	s.transfers = nil
+	return s.collect("", res.Events)
+}
+
+// ListenDeliverTx satisfies the baseapp.ABCIListener interface
+func (s *Service) ListenDeliverTx(_ sdk.Context, req abci.RequestDeliverTx, res abci.ResponseDeliverTx) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.collect(fmtHash(tmhash.Sum(req.Tx)), res.Events)
+}
+
+// ListenEndBlock satisfies the baseapp.ABCIListener interface
+func (s *Service) ListenEndBlock(_ sdk.Context, _ abci.RequestEndBlock, res abci.ResponseEndBlock) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.collect("", res.Events)
+}
+
+func (s *Service) collect(txHash string, events []abci.Event) error {
+	for _, event := range events {
+		transfer, err := eventTransfer(event)
+		if err != nil {
+			return err
+		}
+		if transfer != nil {
+			transfer.Height = s.height
+			transfer.TxHash = txHash
+			transfer.EventIndex = s.events
+			s.transfers = append(s.transfers, transfer)
+		}
+		s.events++
+	}
+
+	return nil
+}
+
+// ListenStartCommit satisfies the streaming.Service interface
+func (s *Service) ListenStartCommit() {}
+
+// ListenCommit satisfies the streaming.Service interface. It writes the
+// transfers of the block into the store.
+func (s *Service) ListenCommit() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	height, transfers := s.height, s.transfers
+	s.height, s.transfers = 0, nil
+	if height == 0 {
+		return nil
+	}
+
+	return s.store.write(height, transfers)
+}
+
+// Stream satisfies the baseapp.StreamingService interface. The service writes
+// the transfers synchronously, so it has no loop to run.
+func (s *Service) Stream(_ *sync.WaitGroup) error {
+	return nil
+}
+
+// Close satisfies the io.Closer interface. It closes the store.
+func (s *Service) Close() error {
+	return s.store.Close()
+}
@@ -0,0 +1,134 @@
+package indexer
+
+import (
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+)
+
+// modules of the balance changes
+const (
+	ModuleBank       = "bank"
+	ModuleToken      = "token"
+	ModuleCollection = "collection"
+)
+
+// balanceChange is a change of the balance of an address by an event. The
+// denom is the one of bank, or the token id of collection, while the contract
+// id is the one of token or collection.
+type balanceChange struct {
+	Module     string
+	Address    string
+	ContractID string
+	Denom      string
+	Amount     sdk.Int
+}
+
+// typed events of token and collection changing the balances
+var (
+	tokenSentType        = proto.MessageName(&token.EventSent{})
+	tokenMintedType      = proto.MessageName(&token.EventMinted{})
+	tokenBurnedType      = proto.MessageName(&token.EventBurned{})
+	collSentType         = proto.MessageName(&collection.EventSent{})
+	collMintedFTType     = proto.MessageName(&collection.EventMintedFT{})
+	collMintedNFTType    = proto.MessageName(&collection.EventMintedNFT{})
+	collBurnedType       = proto.MessageName(&collection.EventBurned{})
+	collOwnerChangedType = proto.MessageName(&collection.EventOwnerChanged{})
+)
+
+// balanceChanges returns the balance changes of the event. The coins of bank
+// are tracked by the events of coin_spent and coin_received, and the tokens of
+// token and collection by their typed events.
+func balanceChanges(event abci.Event) ([]balanceChange, error) {
+	switch event.Type {
+	case banktypes.EventTypeCoinSpent:
+		return bankChanges(event, banktypes.AttributeKeySpender, true)
+	case banktypes.EventTypeCoinReceived:
+		return bankChanges(event, banktypes.AttributeKeyReceiver, false)
+	case tokenSentType, tokenMintedType, tokenBurnedType,
+		collSentType, collMintedFTType, collMintedNFTType, collBurnedType, collOwnerChangedType:
+	default:
+		return nil, nil
+	}
+
+	msg, err := sdk.ParseTypedEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []balanceChange
+	tokenChange := func(contractID, addr string, amount sdk.Int) {
+		changes = append(changes, balanceChange{Module: ModuleToken, Address: addr, ContractID: contractID, Amount: amount})
+	}
+	collChanges := func(contractID, addr string, amount []collection.Coin, sign int64) {
+		for _, coin := range amount {
+			changes = append(changes, balanceChange{
+				Module:     ModuleCollection,
+				Address:    addr,
+				ContractID: contractID,
+				Denom:      coin.TokenId,
+				Amount:     coin.Amount.MulRaw(sign),
+			})
+		}
+	}
+
+	switch e := msg.(type) {
+	case *token.EventSent:
+		tokenChange(e.ContractId, e.From, e.Amount.Neg())
+		tokenChange(e.ContractId, e.To, e.Amount)
+	case *token.EventMinted:
+		tokenChange(e.ContractId, e.To, e.Amount)
+	case *token.EventBurned:
+		tokenChange(e.ContractId, e.From, e.Amount.Neg())
+
+	case *collection.EventSent:
+		collChanges(e.ContractId, e.From, e.Amount, -1)
+		collChanges(e.ContractId, e.To, e.Amount, 1)
+	case *collection.EventMintedFT:
+		collChanges(e.ContractId, e.To, e.Amount, 1)
+	case *collection.EventMintedNFT:
+		for _, nft := range e.Tokens {
+			collChanges(e.ContractId, e.To, []collection.Coin{collection.NewCoin(nft.TokenId, sdk.OneInt())}, 1)
+		}
+	case *collection.EventBurned:
+		collChanges(e.ContractId, e.From, e.Amount, -1)
+	case *collection.EventOwnerChanged:
+		// the descendants of an nft sent along with it
+		nft := []collection.Coin{collection.NewCoin(e.TokenId, sdk.OneInt())}
+		collChanges(e.ContractId, e.From, nft, -1)
+		collChanges(e.ContractId, e.To, nft, 1)
+	}
+
+	return changes, nil
+}
+
+func bankChanges(event abci.Event, addrKey string, spent bool) ([]balanceChange, error) {
+	var addr string
+	var coins sdk.Coins
+	for _, attr := range event.Attributes {
+		switch string(attr.Key) {
+		case addrKey:
+			addr = string(attr.Value)
+		case sdk.AttributeKeyAmount:
+			var err error
+			if coins, err = sdk.ParseCoinsNormalized(string(attr.Value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	changes := make([]balanceChange, 0, len(coins))
+	for _, coin := range coins {
+		amount := coin.Amount
+		if spent {
+			amount = amount.Neg()
+		}
+		changes = append(changes, balanceChange{Module: ModuleBank, Address: addr, Denom: coin.Denom, Amount: amount})
+	}
+
+	return changes, nil
+}
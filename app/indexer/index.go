@@ -0,0 +1,169 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Finschia/ostracon/crypto/tmhash"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/app/streaming"
+)
+
+// Attribute is an attribute of an event
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Index indexes the block in a database transaction, replacing the index of
+// its height if any.
+func (idx *Indexer) Index(ctx context.Context, block streaming.Block) (err error) {
+	dbTx, err := idx.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dbTx.Rollback()
+		}
+	}()
+
+	w := &blockWriter{idx: idx, dbTx: dbTx, ctx: ctx, height: block.Height}
+	for _, table := range tables {
+		if err := w.exec(fmt.Sprintf("DELETE FROM %s WHERE height = ?", table), block.Height); err != nil {
+			return err
+		}
+	}
+
+	header := block.RequestBeginBlock.Header
+	if err := w.exec("INSERT INTO blocks (height, hash, time, proposer, num_txs) VALUES (?, ?, ?, ?, ?)",
+		block.Height, hexString(block.RequestBeginBlock.Hash), formatTime(header.Time),
+		hexString(header.ProposerAddress), len(block.DeliverTxs)); err != nil {
+		return err
+	}
+
+	if err := w.events(PhaseBeginBlock, noTx, block.ResponseBeginBlock.Events); err != nil {
+		return err
+	}
+	for i, tx := range block.DeliverTxs {
+		if err := w.tx(i, tx); err != nil {
+			return err
+		}
+	}
+	if err := w.events(PhaseEndBlock, noTx, block.ResponseEndBlock.Events); err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// blockWriter writes the rows of a block in a database transaction.
+type blockWriter struct {
+	idx    *Indexer
+	dbTx   *sql.Tx
+	ctx    context.Context
+	height int64
+}
+
+func (w *blockWriter) exec(query string, args ...interface{}) error {
+	_, err := w.dbTx.ExecContext(w.ctx, w.idx.rebind(query), args...)
+	return err
+}
+
+func (w *blockWriter) tx(txIndex int, tx streaming.DeliverTx) error {
+	res := tx.Response
+
+	var memo, fee string
+	decoded, err := w.idx.txDecoder(tx.Request.Tx)
+	if err == nil {
+		if txWithMemo, ok := decoded.(sdk.TxWithMemo); ok {
+			memo = txWithMemo.GetMemo()
+		}
+		if feeTx, ok := decoded.(sdk.FeeTx); ok {
+			fee = feeTx.GetFee().String()
+		}
+	}
+
+	if err := w.exec(`INSERT INTO txs (height, tx_index, hash, code, codespace, log, gas_wanted, gas_used, memo, fee)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		w.height, txIndex, TxHash(tx.Request.Tx), res.Code, res.Codespace, res.Log,
+		res.GasWanted, res.GasUsed, memo, fee); err != nil {
+		return err
+	}
+
+	// the undecodable txs are indexed without their messages
+	if decoded != nil {
+		for i, msg := range decoded.GetMsgs() {
+			body, err := w.idx.cdc.MarshalInterfaceJSON(msg)
+			if err != nil {
+				return err
+			}
+			var signer string
+			if signers := msg.GetSigners(); len(signers) != 0 {
+				signer = signers[0].String()
+			}
+
+			if err := w.exec("INSERT INTO messages (height, tx_index, msg_index, type_url, signer, body) VALUES (?, ?, ?, ?, ?, ?)",
+				w.height, txIndex, i, sdk.MsgTypeURL(msg), signer, string(body)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.events(PhaseTx, txIndex, res.Events)
+}
+
+func (w *blockWriter) events(phase string, txIndex int, events []abci.Event) error {
+	for i, event := range events {
+		attrs := make([]Attribute, len(event.Attributes))
+		for j, attr := range event.Attributes {
+			attrs[j] = Attribute{Key: string(attr.Key), Value: string(attr.Value)}
+		}
+		bz, err := json.Marshal(attrs)
+		if err != nil {
+			return err
+		}
+
+		if err := w.exec("INSERT INTO events (height, phase, tx_index, event_index, type, attributes) VALUES (?, ?, ?, ?, ?, ?)",
+			w.height, phase, txIndex, i, event.Type, string(bz)); err != nil {
+			return err
+		}
+		for j, attr := range attrs {
+			if err := w.exec(`INSERT INTO event_attributes (height, phase, tx_index, event_index, attr_index, composite_key, value)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				w.height, phase, txIndex, i, j, event.Type+"."+attr.Key, attr.Value); err != nil {
+				return err
+			}
+		}
+
+		changes, err := balanceChanges(event)
+		if err != nil {
+			return fmt.Errorf("failed to decode the balance changes of %s: %w", event.Type, err)
+		}
+		for j, change := range changes {
+			if err := w.exec(`INSERT INTO balance_changes (height, phase, tx_index, event_index, change_index, module, address, contract_id, denom, amount)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				w.height, phase, txIndex, i, j, change.Module, change.Address, change.ContractID, change.Denom, change.Amount.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// TxHash returns the hash of the tx in upper case hex, as ostracon does.
+func TxHash(tx []byte) string {
+	return hexString(tmhash.Sum(tx))
+}
+
+func hexString(bz []byte) string {
+	return strings.ToUpper(hex.EncodeToString(bz))
+}
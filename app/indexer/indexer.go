@@ -0,0 +1,184 @@
+// Package indexer indexes the blocks, the txs, their messages and events, and
+// the balance changes of bank, token and collection into a SQL database, out
+// of the blocks streamed from LinkApp, and serves them over REST.
+//
+// The database is either an embedded SQLite file or a Postgres-compatible
+// server. The blocks are indexed in the background after they are committed,
+// and indexed again if replayed on restart.
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	// drivers of the databases
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/codec"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/app/streaming"
+)
+
+// app.toml options of the indexer
+const (
+	OptEnable       = "indexer.enable"
+	OptDriver       = "indexer.driver"
+	OptDSN          = "indexer.dsn"
+	OptBufferBlocks = "indexer.buffer_blocks"
+)
+
+// the drivers of the databases supported
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// DefaultSQLiteFile is the SQLite file in the data directory of the node
+// used if no DSN is given.
+const DefaultSQLiteFile = "index.db"
+
+var _ streaming.Sink = (*Indexer)(nil)
+
+// Indexer indexes the blocks into a SQL database.
+type Indexer struct {
+	db        *sql.DB
+	driver    string
+	txDecoder sdk.TxDecoder
+	cdc       codec.JSONCodec
+}
+
+// Open opens the database of the driver at the DSN and creates the tables of
+// the index if they do not exist. The txs are decoded by txDecoder, and their
+// messages are encoded in JSON by cdc.
+func Open(driver, dsn string, txDecoder sdk.TxDecoder, cdc codec.JSONCodec) (*Indexer, error) {
+	switch driver {
+	case DriverSQLite, DriverPostgres:
+	default:
+		return nil, fmt.Errorf("unsupported indexer driver %q", driver)
+	}
+	if dsn == "" {
+		return nil, errors.New("indexer DSN not specified")
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == DriverSQLite {
+		// SQLite serializes the writes anyway
+		db.SetMaxOpenConns(1)
+	}
+
+	idx := &Indexer{
+		db:        db,
+		driver:    driver,
+		txDecoder: txDecoder,
+		cdc:       cdc,
+	}
+	if err := idx.migrate(context.Background()); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create the tables of the index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// NewStreamingServiceFromOptions opens the indexer configured by the options,
+// returning it along with the streaming service feeding it.
+func NewStreamingServiceFromOptions(opts servertypes.AppOptions, txDecoder sdk.TxDecoder, cdc codec.JSONCodec) (*Indexer, streaming.Service, error) {
+	driver := cast.ToString(opts.Get(OptDriver))
+	if driver == "" {
+		driver = DriverSQLite
+	}
+	dsn := cast.ToString(opts.Get(OptDSN))
+	if dsn == "" && driver == DriverSQLite {
+		dsn = filepath.Join(cast.ToString(opts.Get(flags.FlagHome)), "data", DefaultSQLiteFile)
+	}
+	bufferBlocks := streaming.DefaultBufferBlocks
+	if opt := opts.Get(OptBufferBlocks); opt != nil {
+		bufferBlocks = cast.ToInt(opt)
+	}
+
+	idx, err := Open(driver, dsn, txDecoder, cdc)
+	if err != nil {
+		return nil, nil, err
+	}
+	service, err := streaming.NewBusStreamingService(idx, bufferBlocks, streaming.DefaultSendTimeout, nil)
+	if err != nil {
+		_ = idx.Close()
+		return nil, nil, err
+	}
+
+	return idx, service, nil
+}
+
+// Send satisfies the streaming.Sink interface. It indexes the block, replacing
+// the index of the height if any.
+func (idx *Indexer) Send(ctx context.Context, _ int64, bz []byte) error {
+	block, err := streaming.DecodeBlock(bz)
+	if err != nil {
+		return err
+	}
+
+	return idx.Index(ctx, block)
+}
+
+// Close satisfies the streaming.Sink interface
+func (idx *Indexer) Close() error {
+	return idx.db.Close()
+}
+
+// LatestHeight returns the height of the latest block indexed, or 0 if none.
+func (idx *Indexer) LatestHeight(ctx context.Context) (int64, error) {
+	var height sql.NullInt64
+	if err := idx.db.QueryRowContext(ctx, "SELECT MAX(height) FROM blocks").Scan(&height); err != nil {
+		return 0, err
+	}
+
+	return height.Int64, nil
+}
+
+// rebind rewrites the placeholders of the query to the ones of the driver.
+func (idx *Indexer) rebind(query string) string {
+	if idx.driver != DriverPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (idx *Indexer) migrate(ctx context.Context) error {
+	for _, stmt := range schema {
+		if _, err := idx.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatTime formats the time of a block, which sorts as a text.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
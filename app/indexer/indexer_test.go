@@ -0,0 +1,204 @@
+package indexer_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+
+	"github.com/Finschia/finschia-sdk/crypto/keys/secp256k1"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+
+	linkapp "github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/indexer"
+	"github.com/Finschia/finschia/app/streaming"
+	"github.com/Finschia/finschia/app/testutil"
+)
+
+var (
+	alice = sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+	bob   = sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+)
+
+// testBlock returns a block of a tx sending coins and tokens from alice to
+// bob, and minting an nft to bob at EndBlock.
+func testBlock(t *testing.T, height int64) (streaming.Block, []byte) {
+	encCfg := linkapp.MakeEncodingConfig()
+	builder := encCfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(banktypes.NewMsgSend(sdk.MustAccAddressFromBech32(alice), sdk.MustAccAddressFromBech32(bob), sdk.NewCoins(sdk.NewInt64Coin("cony", 10)))))
+	builder.SetMemo("memo")
+	builder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("cony", 1)))
+	tx, err := encCfg.TxConfig.TxEncoder()(builder.GetTx())
+	require.NoError(t, err)
+
+	return streaming.Block{
+		Height: height,
+		RequestBeginBlock: ocabci.RequestBeginBlock{
+			Hash:   []byte{1, 2},
+			Header: tmproto.Header{Height: height, Time: time.Unix(1000, 0)},
+		},
+		DeliverTxs: []streaming.DeliverTx{{
+			Request: abci.RequestDeliverTx{Tx: tx},
+			Response: abci.ResponseDeliverTx{
+				GasUsed: 100,
+				Events: []abci.Event{
+					abci.Event(banktypes.NewCoinSpentEvent(sdk.MustAccAddressFromBech32(alice), sdk.NewCoins(sdk.NewInt64Coin("cony", 10)))),
+					abci.Event(banktypes.NewCoinReceivedEvent(sdk.MustAccAddressFromBech32(bob), sdk.NewCoins(sdk.NewInt64Coin("cony", 10)))),
+					testutil.TypedEvent(t, &token.EventSent{ContractId: "9be17165", Operator: alice, From: alice, To: bob, Amount: sdk.NewInt(3)}),
+				},
+			},
+		}},
+		ResponseEndBlock: abci.ResponseEndBlock{
+			Events: []abci.Event{
+				testutil.TypedEvent(t, &collection.EventMintedNFT{ContractId: "deadbeef", Operator: alice, To: bob, Tokens: []collection.NFT{{TokenId: "1000000100000001"}}}),
+			},
+		},
+	}, tx
+}
+
+func TestIndexer(t *testing.T) {
+	encCfg := linkapp.MakeEncodingConfig()
+	idx, err := indexer.Open(indexer.DriverSQLite, filepath.Join(t.TempDir(), "index.db"), encCfg.TxConfig.TxDecoder(), encCfg.Marshaler)
+	require.NoError(t, err)
+	defer idx.Close()
+	ctx := context.Background()
+
+	block, tx := testBlock(t, 5)
+	require.NoError(t, idx.Index(ctx, block))
+	// replayed on restart
+	require.NoError(t, idx.Index(ctx, block))
+
+	height, err := idx.LatestHeight(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), height)
+
+	indexed, err := idx.Block(ctx, 5)
+	require.NoError(t, err)
+	require.Equal(t, "0102", indexed.Hash)
+	require.Equal(t, 1, indexed.NumTxs)
+	require.Len(t, indexed.Txs, 1)
+	require.Len(t, indexed.Events, 1)
+	require.Equal(t, indexer.PhaseEndBlock, indexed.Events[0].Phase)
+	_, err = idx.Block(ctx, 6)
+	require.ErrorIs(t, err, indexer.ErrNotFound)
+
+	indexedTx, err := idx.TxByHash(ctx, indexer.TxHash(tx))
+	require.NoError(t, err)
+	require.Equal(t, "memo", indexedTx.Memo)
+	require.Equal(t, "1cony", indexedTx.Fee)
+	require.Len(t, indexedTx.Messages, 1)
+	require.Equal(t, "/cosmos.bank.v1beta1.MsgSend", indexedTx.Messages[0].TypeURL)
+	require.Equal(t, alice, indexedTx.Messages[0].Signer)
+	require.Len(t, indexedTx.Events, 3)
+
+	for name, tc := range map[string]struct {
+		filter indexer.TxFilter
+		found  bool
+	}{
+		"sender":      {indexer.TxFilter{Sender: alice}, true},
+		"not sender":  {indexer.TxFilter{Sender: bob}, false},
+		"msg type":    {indexer.TxFilter{MsgType: "/cosmos.bank.v1beta1.MsgSend"}, true},
+		"event":       {indexer.TxFilter{Events: []indexer.EventAttribute{{CompositeKey: "coin_received.receiver", Value: bob}}}, true},
+		"not event":   {indexer.TxFilter{Events: []indexer.EventAttribute{{CompositeKey: "coin_received.receiver", Value: alice}}}, false},
+		"heights":     {indexer.TxFilter{HeightRange: indexer.HeightRange{From: 5, To: 5}}, true},
+		"not heights": {indexer.TxFilter{HeightRange: indexer.HeightRange{From: 6}}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			txs, err := idx.Txs(ctx, tc.filter, indexer.Page{})
+			require.NoError(t, err)
+			if tc.found {
+				require.Len(t, txs, 1)
+			} else {
+				require.Empty(t, txs)
+			}
+		})
+	}
+
+	changes, err := idx.BalanceChanges(ctx, indexer.BalanceChangeFilter{Address: bob}, indexer.Page{})
+	require.NoError(t, err)
+	require.Equal(t, []indexer.BalanceChange{
+		{Height: 5, Phase: indexer.PhaseTx, Module: indexer.ModuleBank, Address: bob, Denom: "cony", Amount: "10"},
+		{Height: 5, Phase: indexer.PhaseTx, Module: indexer.ModuleToken, Address: bob, ContractID: "9be17165", Amount: "3"},
+		{Height: 5, Phase: indexer.PhaseEndBlock, TxIndex: -1, Module: indexer.ModuleCollection, Address: bob, ContractID: "deadbeef", Denom: "1000000100000001", Amount: "1"},
+	}, changes)
+
+	changes, err = idx.BalanceChanges(ctx, indexer.BalanceChangeFilter{Address: alice, Module: indexer.ModuleToken}, indexer.Page{})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "-3", changes[0].Amount)
+
+	// the messages of the txs of a page
+	next, _ := testBlock(t, 6)
+	require.NoError(t, idx.Index(ctx, next))
+	txs, err := idx.Txs(ctx, indexer.TxFilter{}, indexer.Page{Desc: true})
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	for i, height := range []int64{6, 5} {
+		require.Equal(t, height, txs[i].Height)
+		require.Len(t, txs[i].Messages, 1)
+		require.Equal(t, alice, txs[i].Messages[0].Signer)
+	}
+}
+
+func TestIndexerREST(t *testing.T) {
+	encCfg := linkapp.MakeEncodingConfig()
+	idx, err := indexer.Open(indexer.DriverSQLite, filepath.Join(t.TempDir(), "index.db"), encCfg.TxConfig.TxDecoder(), encCfg.Marshaler)
+	require.NoError(t, err)
+	defer idx.Close()
+
+	for height := int64(1); height <= 3; height++ {
+		block, _ := testBlock(t, height)
+		require.NoError(t, idx.Index(context.Background(), block))
+	}
+
+	router := mux.NewRouter()
+	indexer.RegisterRoutes(router, idx)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	get := func(path string, status int, res interface{}) {
+		resp, err := http.Get(server.URL + indexer.RoutePrefix + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode, path)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+
+	var blocks struct{ Blocks []indexer.Block }
+	get("/blocks?order=desc&limit=2", http.StatusOK, &blocks)
+	require.Len(t, blocks.Blocks, 2)
+	require.Equal(t, int64(3), blocks.Blocks[0].Height)
+
+	var txs struct{ Txs []indexer.Tx }
+	get("/txs?from_height=2&events=coin_spent.spender%3D"+alice, http.StatusOK, &txs)
+	require.Len(t, txs.Txs, 2)
+	require.Equal(t, int64(2), txs.Txs[0].Height)
+
+	var changes struct {
+		BalanceChanges []indexer.BalanceChange `json:"balance_changes"`
+	}
+	get("/balance_changes?address="+bob+"&module=collection&offset=1", http.StatusOK, &changes)
+	require.Len(t, changes.BalanceChanges, 2)
+
+	var block struct{ Block indexer.Block }
+	get("/blocks/2", http.StatusOK, &block)
+	require.Len(t, block.Block.Txs, 1)
+
+	var errRes struct{ Error string }
+	get("/blocks/4", http.StatusNotFound, &errRes)
+	get("/txs?events=invalid", http.StatusBadRequest, &errRes)
+	get("/txs/00", http.StatusNotFound, &errRes)
+}
@@ -0,0 +1,406 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is returned if the block or the tx is not indexed
+var ErrNotFound = errors.New("not found")
+
+// limits of the rows of a page
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Block is an indexed block
+type Block struct {
+	Height   int64  `json:"height"`
+	Hash     string `json:"hash"`
+	Time     string `json:"time"`
+	Proposer string `json:"proposer"`
+	NumTxs   int    `json:"num_txs"`
+
+	// Txs and Events are loaded for a block queried by height
+	Txs    []Tx    `json:"txs,omitempty"`
+	Events []Event `json:"events,omitempty"`
+}
+
+// Tx is an indexed tx
+type Tx struct {
+	Height    int64     `json:"height"`
+	Index     int       `json:"index"`
+	Hash      string    `json:"hash"`
+	Code      uint32    `json:"code"`
+	Codespace string    `json:"codespace"`
+	Log       string    `json:"log"`
+	GasWanted int64     `json:"gas_wanted"`
+	GasUsed   int64     `json:"gas_used"`
+	Memo      string    `json:"memo"`
+	Fee       string    `json:"fee"`
+	Messages  []Message `json:"messages"`
+
+	// Events are loaded for a tx queried by hash
+	Events []Event `json:"events,omitempty"`
+}
+
+// Message is a message of an indexed tx
+type Message struct {
+	Index   int             `json:"index"`
+	TypeURL string          `json:"type_url"`
+	Signer  string          `json:"signer"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// Event is an indexed event
+type Event struct {
+	Phase      string      `json:"phase"`
+	Index      int         `json:"index"`
+	Type       string      `json:"type"`
+	Attributes []Attribute `json:"attributes"`
+}
+
+// BalanceChange is an indexed change of a balance, whose amount is signed.
+type BalanceChange struct {
+	Height     int64  `json:"height"`
+	Phase      string `json:"phase"`
+	TxIndex    int    `json:"tx_index"`
+	Module     string `json:"module"`
+	Address    string `json:"address"`
+	ContractID string `json:"contract_id,omitempty"`
+	Denom      string `json:"denom,omitempty"`
+	Amount     string `json:"amount"`
+}
+
+// Page is the range of the rows to query. The rows are in the ascending
+// order of the heights unless Desc.
+type Page struct {
+	Limit  int
+	Offset int
+	Desc   bool
+}
+
+// HeightRange filters the rows by their heights, where a zero bound is open.
+type HeightRange struct {
+	From int64
+	To   int64
+}
+
+// TxFilter filters the txs. The empty fields match any.
+type TxFilter struct {
+	HeightRange
+	Sender  string
+	MsgType string
+	// Events match the txs with an event of every attribute
+	Events []EventAttribute
+}
+
+// EventAttribute matches an attribute of the type of event.
+type EventAttribute struct {
+	// CompositeKey is the type and the key of the attribute joined by a dot,
+	// e.g. transfer.recipient
+	CompositeKey string
+	Value        string
+}
+
+// BalanceChangeFilter filters the balance changes. The empty fields match any.
+type BalanceChangeFilter struct {
+	HeightRange
+	Address    string
+	Module     string
+	ContractID string
+	Denom      string
+}
+
+// Blocks returns the blocks in the range of the heights.
+func (idx *Indexer) Blocks(ctx context.Context, heights HeightRange, page Page) ([]Block, error) {
+	var q query
+	q.heights("height", heights)
+
+	rows, err := idx.query(ctx, "SELECT height, hash, time, proposer, num_txs FROM blocks"+q.where()+
+		page.orderBy("height")+page.limit(), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []Block{}
+	for rows.Next() {
+		var b Block
+		if err := rows.Scan(&b.Height, &b.Hash, &b.Time, &b.Proposer, &b.NumTxs); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+
+	return blocks, rows.Err()
+}
+
+// Block returns the block of the height along with its txs and the events of
+// BeginBlock and EndBlock.
+func (idx *Indexer) Block(ctx context.Context, height int64) (*Block, error) {
+	blocks, err := idx.Blocks(ctx, HeightRange{From: height, To: height}, Page{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, ErrNotFound
+	}
+	block := blocks[0]
+
+	if block.Txs, err = idx.Txs(ctx, TxFilter{HeightRange: HeightRange{From: height, To: height}}, Page{Limit: MaxLimit}); err != nil {
+		return nil, err
+	}
+	if block.Events, err = idx.events(ctx, height, noTx, PhaseBeginBlock, PhaseEndBlock); err != nil {
+		return nil, err
+	}
+
+	return &block, nil
+}
+
+// Txs returns the txs matching the filter, along with their messages.
+func (idx *Indexer) Txs(ctx context.Context, filter TxFilter, page Page) ([]Tx, error) {
+	var q query
+	q.heights("t.height", filter.HeightRange)
+	if filter.Sender != "" {
+		q.add("EXISTS (SELECT 1 FROM messages m WHERE m.height = t.height AND m.tx_index = t.tx_index AND m.signer = ?)", filter.Sender)
+	}
+	if filter.MsgType != "" {
+		q.add("EXISTS (SELECT 1 FROM messages m WHERE m.height = t.height AND m.tx_index = t.tx_index AND m.type_url = ?)", filter.MsgType)
+	}
+	for _, attr := range filter.Events {
+		q.add("EXISTS (SELECT 1 FROM event_attributes a WHERE a.height = t.height AND a.phase = ? AND a.tx_index = t.tx_index AND a.composite_key = ? AND a.value = ?)",
+			PhaseTx, attr.CompositeKey, attr.Value)
+	}
+
+	return idx.txs(ctx, q, page)
+}
+
+// TxByHash returns the tx of the hash in hex along with its messages and
+// events. A tx included in several blocks is the latest one.
+func (idx *Indexer) TxByHash(ctx context.Context, hash string) (*Tx, error) {
+	var q query
+	q.add("t.hash = ?", strings.ToUpper(hash))
+
+	txs, err := idx.txs(ctx, q, Page{Limit: 1, Desc: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, ErrNotFound
+	}
+	tx := txs[0]
+
+	if tx.Events, err = idx.events(ctx, tx.Height, tx.Index, PhaseTx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+func (idx *Indexer) txs(ctx context.Context, q query, page Page) ([]Tx, error) {
+	rows, err := idx.query(ctx, `SELECT t.height, t.tx_index, t.hash, t.code, t.codespace, t.log, t.gas_wanted, t.gas_used, t.memo, t.fee
+		FROM txs t`+q.where()+page.orderBy("t.height", "t.tx_index")+page.limit(), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := []Tx{}
+	for rows.Next() {
+		var tx Tx
+		if err := rows.Scan(&tx.Height, &tx.Index, &tx.Hash, &tx.Code, &tx.Codespace, &tx.Log,
+			&tx.GasWanted, &tx.GasUsed, &tx.Memo, &tx.Fee); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := idx.messages(ctx, txs); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// messages loads the messages of the txs at once.
+func (idx *Indexer) messages(ctx context.Context, txs []Tx) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	type txKey struct {
+		height  int64
+		txIndex int
+	}
+	byKey := make(map[txKey]*Tx, len(txs))
+	values := make([]string, len(txs))
+	args := make([]interface{}, 0, 2*len(txs))
+	for i := range txs {
+		txs[i].Messages = []Message{}
+		byKey[txKey{txs[i].Height, txs[i].Index}] = &txs[i]
+		values[i] = "(CAST(? AS BIGINT), CAST(? AS INTEGER))"
+		args = append(args, txs[i].Height, txs[i].Index)
+	}
+
+	// SQLite takes a row value IN only from a subquery, hence VALUES, whose
+	// parameters are cast not to be taken for text by PostgreSQL
+	rows, err := idx.query(ctx, "SELECT height, tx_index, msg_index, type_url, signer, body FROM messages"+
+		" WHERE (height, tx_index) IN (VALUES "+strings.Join(values, ", ")+") ORDER BY height, tx_index, msg_index", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key txKey
+		var msg Message
+		var body string
+		if err := rows.Scan(&key.height, &key.txIndex, &msg.Index, &msg.TypeURL, &msg.Signer, &body); err != nil {
+			return err
+		}
+		msg.Body = json.RawMessage(body)
+		if tx, ok := byKey[key]; ok {
+			tx.Messages = append(tx.Messages, msg)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (idx *Indexer) events(ctx context.Context, height int64, txIndex int, phases ...string) ([]Event, error) {
+	var events []Event
+	for _, phase := range phases {
+		rows, err := idx.query(ctx, "SELECT event_index, type, attributes FROM events WHERE height = ? AND phase = ? AND tx_index = ? ORDER BY event_index",
+			height, phase, txIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			event := Event{Phase: phase}
+			var attrs string
+			if err := rows.Scan(&event.Index, &event.Type, &attrs); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if err := json.Unmarshal([]byte(attrs), &event.Attributes); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			events = append(events, event)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// BalanceChanges returns the balance changes matching the filter.
+func (idx *Indexer) BalanceChanges(ctx context.Context, filter BalanceChangeFilter, page Page) ([]BalanceChange, error) {
+	var q query
+	q.heights("height", filter.HeightRange)
+	for _, cond := range []struct {
+		column string
+		value  string
+	}{
+		{"address", filter.Address},
+		{"module", filter.Module},
+		{"contract_id", filter.ContractID},
+		{"denom", filter.Denom},
+	} {
+		if cond.value != "" {
+			q.add(cond.column+" = ?", cond.value)
+		}
+	}
+
+	rows, err := idx.query(ctx, "SELECT height, phase, tx_index, module, address, contract_id, denom, amount FROM balance_changes"+
+		q.where()+page.orderBy("height", phaseOrder, "tx_index", "event_index", "change_index")+page.limit(), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []BalanceChange{}
+	for rows.Next() {
+		var c BalanceChange
+		if err := rows.Scan(&c.Height, &c.Phase, &c.TxIndex, &c.Module, &c.Address, &c.ContractID, &c.Denom, &c.Amount); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+// phaseOrder sorts the phases in the order of a block
+var phaseOrder = "CASE phase WHEN '" + PhaseBeginBlock + "' THEN 0 WHEN '" + PhaseTx + "' THEN 1 ELSE 2 END"
+
+func (idx *Indexer) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return idx.db.QueryContext(ctx, idx.rebind(query), args...)
+}
+
+// query builds the conditions of a query.
+type query struct {
+	conds []string
+	args  []interface{}
+}
+
+func (q *query) add(cond string, args ...interface{}) {
+	q.conds = append(q.conds, cond)
+	q.args = append(q.args, args...)
+}
+
+func (q *query) heights(column string, heights HeightRange) {
+	if heights.From > 0 {
+		q.add(column+" >= ?", heights.From)
+	}
+	if heights.To > 0 {
+		q.add(column+" <= ?", heights.To)
+	}
+}
+
+func (q *query) where() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// orderBy returns the ORDER BY clause of the columns.
+func (p Page) orderBy(columns ...string) string {
+	order := " ASC"
+	if p.Desc {
+		order = " DESC"
+	}
+	return " ORDER BY " + strings.Join(columns, order+", ") + order
+}
+
+// limit returns the LIMIT clause, with the limit clamped to MaxLimit.
+func (p Page) limit() string {
+	limit := p.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultLimit
+	case limit > MaxLimit:
+		limit = MaxLimit
+	}
+	offset := p.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	return " LIMIT " + strconv.Itoa(limit) + " OFFSET " + strconv.Itoa(offset)
+}
@@ -0,0 +1,197 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RoutePrefix is the prefix of the REST routes of the indexer
+const RoutePrefix = "/finschia/indexer/v1"
+
+// RegisterRoutes registers the REST routes of the indexer:
+//
+//	GET /finschia/indexer/v1/blocks?from_height=&to_height=
+//	GET /finschia/indexer/v1/blocks/{height}
+//	GET /finschia/indexer/v1/txs?from_height=&to_height=&sender=&msg_type=&events=transfer.recipient%3D{address}
+//	GET /finschia/indexer/v1/txs/{hash}
+//	GET /finschia/indexer/v1/balance_changes?address=&module=&contract_id=&denom=&from_height=&to_height=
+//
+// The lists are paginated by limit and offset, in the ascending order of the
+// heights unless order=desc.
+func RegisterRoutes(r *mux.Router, idx *Indexer) {
+	r.HandleFunc(RoutePrefix+"/blocks", idx.handle(queryBlocks)).Methods(http.MethodGet)
+	r.HandleFunc(RoutePrefix+"/blocks/{height}", idx.handle(queryBlock)).Methods(http.MethodGet)
+	r.HandleFunc(RoutePrefix+"/txs", idx.handle(queryTxs)).Methods(http.MethodGet)
+	r.HandleFunc(RoutePrefix+"/txs/{hash}", idx.handle(queryTx)).Methods(http.MethodGet)
+	r.HandleFunc(RoutePrefix+"/balance_changes", idx.handle(queryBalanceChanges)).Methods(http.MethodGet)
+}
+
+// errBadRequest wraps the errors of the invalid requests
+var errBadRequest = errors.New("bad request")
+
+type queryFunc func(ctx context.Context, idx *Indexer, r *http.Request) (interface{}, error)
+
+func (idx *Indexer) handle(fn queryFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := fn(r.Context(), idx, r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, errBadRequest):
+				status = http.StatusBadRequest
+			case errors.Is(err, ErrNotFound):
+				status = http.StatusNotFound
+			}
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func queryBlocks(ctx context.Context, idx *Indexer, r *http.Request) (interface{}, error) {
+	heights, page, err := parseRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := idx.Blocks(ctx, heights, page)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"blocks": blocks}, nil
+}
+
+func queryBlock(ctx context.Context, idx *Indexer, r *http.Request) (interface{}, error) {
+	height, err := parseHeight("height", mux.Vars(r)["height"])
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := idx.Block(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"block": block}, nil
+}
+
+func queryTxs(ctx context.Context, idx *Indexer, r *http.Request) (interface{}, error) {
+	heights, page, err := parseRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	values := r.URL.Query()
+	filter := TxFilter{
+		HeightRange: heights,
+		Sender:      values.Get("sender"),
+		MsgType:     values.Get("msg_type"),
+	}
+	for _, event := range values["events"] {
+		key, value, ok := strings.Cut(event, "=")
+		if !ok || !strings.Contains(key, ".") {
+			return nil, fmt.Errorf("%w: event %q not of {type}.{key}={value}", errBadRequest, event)
+		}
+		filter.Events = append(filter.Events, EventAttribute{CompositeKey: key, Value: value})
+	}
+
+	txs, err := idx.Txs(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"txs": txs}, nil
+}
+
+func queryTx(ctx context.Context, idx *Indexer, r *http.Request) (interface{}, error) {
+	tx, err := idx.TxByHash(ctx, mux.Vars(r)["hash"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"tx": tx}, nil
+}
+
+func queryBalanceChanges(ctx context.Context, idx *Indexer, r *http.Request) (interface{}, error) {
+	heights, page, err := parseRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	values := r.URL.Query()
+	changes, err := idx.BalanceChanges(ctx, BalanceChangeFilter{
+		HeightRange: heights,
+		Address:     values.Get("address"),
+		Module:      values.Get("module"),
+		ContractID:  values.Get("contract_id"),
+		Denom:       values.Get("denom"),
+	}, page)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"balance_changes": changes}, nil
+}
+
+// parseRange parses the range of the heights and the page of the request.
+func parseRange(r *http.Request) (HeightRange, Page, error) {
+	values := r.URL.Query()
+
+	var heights HeightRange
+	var page Page
+	var err error
+	if heights.From, err = parseHeight("from_height", values.Get("from_height")); err != nil {
+		return heights, page, err
+	}
+	if heights.To, err = parseHeight("to_height", values.Get("to_height")); err != nil {
+		return heights, page, err
+	}
+	if page.Limit, err = parseInt("limit", values.Get("limit")); err != nil {
+		return heights, page, err
+	}
+	if page.Offset, err = parseInt("offset", values.Get("offset")); err != nil {
+		return heights, page, err
+	}
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		page.Desc = true
+	default:
+		return heights, page, fmt.Errorf("%w: invalid order %q", errBadRequest, order)
+	}
+
+	return heights, page, nil
+}
+
+func parseHeight(name, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	height, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || height < 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errBadRequest, name, value)
+	}
+	return height, nil
+}
+
+func parseInt(name, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errBadRequest, name, value)
+	}
+	return n, nil
+}
@@ -0,0 +1,87 @@
+package indexer
+
+// phases of a block the events are emitted in
+const (
+	PhaseBeginBlock = "begin_block"
+	PhaseTx         = "tx"
+	PhaseEndBlock   = "end_block"
+)
+
+// noTx is the tx index of the events of BeginBlock and EndBlock
+const noTx = -1
+
+// schema creates the tables of the index, which are portable across SQLite
+// and Postgres. The amounts are signed integers in text, being arbitrarily
+// large.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS blocks (
+		height BIGINT NOT NULL PRIMARY KEY,
+		hash TEXT NOT NULL,
+		time TEXT NOT NULL,
+		proposer TEXT NOT NULL,
+		num_txs INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS txs (
+		height BIGINT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		hash TEXT NOT NULL,
+		code INTEGER NOT NULL,
+		codespace TEXT NOT NULL,
+		log TEXT NOT NULL,
+		gas_wanted BIGINT NOT NULL,
+		gas_used BIGINT NOT NULL,
+		memo TEXT NOT NULL,
+		fee TEXT NOT NULL,
+		PRIMARY KEY (height, tx_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS txs_hash ON txs (hash)`,
+	`CREATE TABLE IF NOT EXISTS messages (
+		height BIGINT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		msg_index INTEGER NOT NULL,
+		type_url TEXT NOT NULL,
+		signer TEXT NOT NULL,
+		body TEXT NOT NULL,
+		PRIMARY KEY (height, tx_index, msg_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS messages_type_url ON messages (type_url, height)`,
+	`CREATE INDEX IF NOT EXISTS messages_signer ON messages (signer, height)`,
+	`CREATE TABLE IF NOT EXISTS events (
+		height BIGINT NOT NULL,
+		phase TEXT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		event_index INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		attributes TEXT NOT NULL,
+		PRIMARY KEY (height, phase, tx_index, event_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS events_type ON events (type, height)`,
+	`CREATE TABLE IF NOT EXISTS event_attributes (
+		height BIGINT NOT NULL,
+		phase TEXT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		event_index INTEGER NOT NULL,
+		attr_index INTEGER NOT NULL,
+		composite_key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (height, phase, tx_index, event_index, attr_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS event_attributes_key ON event_attributes (composite_key, value, height)`,
+	`CREATE TABLE IF NOT EXISTS balance_changes (
+		height BIGINT NOT NULL,
+		phase TEXT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		event_index INTEGER NOT NULL,
+		change_index INTEGER NOT NULL,
+		module TEXT NOT NULL,
+		address TEXT NOT NULL,
+		contract_id TEXT NOT NULL,
+		denom TEXT NOT NULL,
+		amount TEXT NOT NULL,
+		PRIMARY KEY (height, phase, tx_index, event_index, change_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS balance_changes_address ON balance_changes (address, height)`,
+}
+
+// tables are the tables holding the rows of a height
+var tables = []string{"blocks", "txs", "messages", "events", "event_attributes", "balance_changes"}
@@ -0,0 +1,254 @@
+package indexevents
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	authtypes "github.com/Finschia/finschia-sdk/x/auth/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	crisistypes "github.com/Finschia/finschia-sdk/x/crisis/types"
+	distrtypes "github.com/Finschia/finschia-sdk/x/distribution/types"
+	evidencetypes "github.com/Finschia/finschia-sdk/x/evidence/types"
+	"github.com/Finschia/finschia-sdk/x/feegrant"
+	govtypes "github.com/Finschia/finschia-sdk/x/gov/types"
+	minttypes "github.com/Finschia/finschia-sdk/x/mint/types"
+	slashingtypes "github.com/Finschia/finschia-sdk/x/slashing/types"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+	icatypes "github.com/Finschia/ibc-go/v3/modules/apps/27-interchain-accounts/types"
+	ibctransfertypes "github.com/Finschia/ibc-go/v3/modules/apps/transfer/types"
+	clienttypes "github.com/Finschia/ibc-go/v3/modules/core/02-client/types"
+	connectiontypes "github.com/Finschia/ibc-go/v3/modules/core/03-connection/types"
+	channeltypes "github.com/Finschia/ibc-go/v3/modules/core/04-channel/types"
+	ibchost "github.com/Finschia/ibc-go/v3/modules/core/24-host"
+	wasmtypes "github.com/Finschia/wasmd/x/wasm/types"
+
+	blocklisttypes "github.com/Finschia/finschia/x/blocklist/types"
+
+	// the proto files of the typed events
+	_ "github.com/Finschia/finschia-sdk/x/authz"
+	_ "github.com/Finschia/finschia-sdk/x/collection"
+	_ "github.com/Finschia/finschia-sdk/x/foundation"
+	_ "github.com/Finschia/finschia-sdk/x/token"
+)
+
+// Event is an event type a module can emit, with its attribute keys.
+type Event struct {
+	Module     string   `json:"module"`
+	Type       string   `json:"type"`
+	Attributes []string `json:"attributes"`
+}
+
+// Keys returns the keys of the attributes of the event in the form of
+// {eventType}.{attributeKey}.
+func (e Event) Keys() []string {
+	keys := make([]string, len(e.Attributes))
+	for i, attr := range e.Attributes {
+		keys[i] = e.Type + "." + attr
+	}
+	return keys
+}
+
+// ModuleBaseApp is the pseudo module of the message events emitted by
+// baseapp for every message.
+const ModuleBaseApp = "baseapp"
+
+// legacyEvents are the events of the modules of the app emitted by
+// sdk.NewEvent, built from the event types and attribute keys the modules
+// export so that they follow the modules.
+//
+// NOTE: the custom events of the contracts, wasm-{type}, are not known in
+// advance, so they must be listed by their keys.
+var legacyEvents = []Event{
+	{ModuleBaseApp, sdk.EventTypeMessage, []string{sdk.AttributeKeyAction, sdk.AttributeKeyModule, sdk.AttributeKeySender}},
+
+	{authtypes.ModuleName, sdk.EventTypeTx, []string{sdk.AttributeKeyFee, sdk.AttributeKeyFeePayer, sdk.AttributeKeyAccountSequence, sdk.AttributeKeySignature}},
+
+	{banktypes.ModuleName, banktypes.EventTypeTransfer, []string{banktypes.AttributeKeyRecipient, banktypes.AttributeKeySender, sdk.AttributeKeyAmount}},
+	{banktypes.ModuleName, banktypes.EventTypeCoinSpent, []string{banktypes.AttributeKeySpender, sdk.AttributeKeyAmount}},
+	{banktypes.ModuleName, banktypes.EventTypeCoinReceived, []string{banktypes.AttributeKeyReceiver, sdk.AttributeKeyAmount}},
+	{banktypes.ModuleName, banktypes.EventTypeCoinMint, []string{banktypes.AttributeKeyMinter, sdk.AttributeKeyAmount}},
+	{banktypes.ModuleName, banktypes.EventTypeCoinBurn, []string{banktypes.AttributeKeyBurner, sdk.AttributeKeyAmount}},
+
+	{blocklisttypes.ModuleName, blocklisttypes.EventTypeBlockAddress, []string{blocklisttypes.AttributeKeyAddress}},
+	{blocklisttypes.ModuleName, blocklisttypes.EventTypeUnblockAddress, []string{blocklisttypes.AttributeKeyAddress}},
+
+	{crisistypes.ModuleName, crisistypes.EventTypeInvariant, []string{crisistypes.AttributeKeyRoute}},
+
+	{distrtypes.ModuleName, distrtypes.EventTypeSetWithdrawAddress, []string{distrtypes.AttributeKeyWithdrawAddress}},
+	{distrtypes.ModuleName, distrtypes.EventTypeRewards, []string{sdk.AttributeKeyAmount, distrtypes.AttributeKeyValidator}},
+	{distrtypes.ModuleName, distrtypes.EventTypeCommission, []string{sdk.AttributeKeyAmount, distrtypes.AttributeKeyValidator}},
+	{distrtypes.ModuleName, distrtypes.EventTypeWithdrawRewards, []string{sdk.AttributeKeyAmount, distrtypes.AttributeKeyValidator}},
+	{distrtypes.ModuleName, distrtypes.EventTypeWithdrawCommission, []string{sdk.AttributeKeyAmount}},
+	{distrtypes.ModuleName, distrtypes.EventTypeProposerReward, []string{sdk.AttributeKeyAmount, distrtypes.AttributeKeyValidator}},
+
+	{evidencetypes.ModuleName, evidencetypes.EventTypeSubmitEvidence, []string{evidencetypes.AttributeKeyEvidenceHash}},
+
+	{feegrant.ModuleName, feegrant.EventTypeUseFeeGrant, []string{feegrant.AttributeKeyGranter, feegrant.AttributeKeyGrantee}},
+	{feegrant.ModuleName, feegrant.EventTypeRevokeFeeGrant, []string{feegrant.AttributeKeyGranter, feegrant.AttributeKeyGrantee}},
+	{feegrant.ModuleName, feegrant.EventTypeSetFeeGrant, []string{feegrant.AttributeKeyGranter, feegrant.AttributeKeyGrantee}},
+
+	{govtypes.ModuleName, govtypes.EventTypeSubmitProposal, []string{govtypes.AttributeKeyProposalID, govtypes.AttributeKeyProposalType, govtypes.AttributeKeyVotingPeriodStart}},
+	{govtypes.ModuleName, govtypes.EventTypeProposalDeposit, []string{sdk.AttributeKeyAmount, govtypes.AttributeKeyProposalID}},
+	{govtypes.ModuleName, govtypes.EventTypeProposalVote, []string{govtypes.AttributeKeyOption, govtypes.AttributeKeyProposalID}},
+	{govtypes.ModuleName, govtypes.EventTypeInactiveProposal, []string{govtypes.AttributeKeyProposalID, govtypes.AttributeKeyProposalResult}},
+	{govtypes.ModuleName, govtypes.EventTypeActiveProposal, []string{govtypes.AttributeKeyProposalID, govtypes.AttributeKeyProposalResult}},
+
+	{ibchost.ModuleName, clienttypes.EventTypeCreateClient, clientAttributes()},
+	{ibchost.ModuleName, clienttypes.EventTypeUpdateClient, clientAttributes(clienttypes.AttributeKeyHeader)},
+	{ibchost.ModuleName, clienttypes.EventTypeUpgradeClient, clientAttributes()},
+	{ibchost.ModuleName, clienttypes.EventTypeSubmitMisbehaviour, clientAttributes()},
+	{ibchost.ModuleName, clienttypes.EventTypeUpdateClientProposal, []string{clienttypes.AttributeKeySubjectClientID, clienttypes.AttributeKeyClientType, clienttypes.AttributeKeyConsensusHeight}},
+	{ibchost.ModuleName, clienttypes.EventTypeUpgradeClientProposal, []string{clienttypes.AttributeKeyUpgradePlanTitle, clienttypes.AttributeKeyUpgradePlanHeight}},
+	{ibchost.ModuleName, connectiontypes.EventTypeConnectionOpenInit, connectionAttributes()},
+	{ibchost.ModuleName, connectiontypes.EventTypeConnectionOpenTry, connectionAttributes()},
+	{ibchost.ModuleName, connectiontypes.EventTypeConnectionOpenAck, connectionAttributes()},
+	{ibchost.ModuleName, connectiontypes.EventTypeConnectionOpenConfirm, connectionAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelOpenInit, channelAttributes(channeltypes.AttributeVersion)},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelOpenTry, channelAttributes(channeltypes.AttributeVersion)},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelOpenAck, channelAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelOpenConfirm, channelAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelCloseInit, channelAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelCloseConfirm, channelAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeChannelClosed, channelAttributes(channeltypes.AttributeKeyChannelOrdering)},
+	{ibchost.ModuleName, channeltypes.EventTypeSendPacket, packetAttributes(channeltypes.AttributeKeyData, channeltypes.AttributeKeyDataHex)},
+	{ibchost.ModuleName, channeltypes.EventTypeRecvPacket, packetAttributes(channeltypes.AttributeKeyData, channeltypes.AttributeKeyDataHex)},
+	{ibchost.ModuleName, channeltypes.EventTypeWriteAck, packetAttributes(channeltypes.AttributeKeyData, channeltypes.AttributeKeyDataHex, channeltypes.AttributeKeyAck, channeltypes.AttributeKeyAckHex)},
+	{ibchost.ModuleName, channeltypes.EventTypeAcknowledgePacket, packetAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeTimeoutPacket, packetAttributes()},
+	{ibchost.ModuleName, channeltypes.EventTypeTimeoutPacketOnClose, packetAttributes()},
+
+	{icatypes.ModuleName, icatypes.EventTypePacket, []string{sdk.AttributeKeyModule, icatypes.AttributeKeyHostChannelID, icatypes.AttributeKeyAckError, icatypes.AttributeKeyAckSuccess}},
+
+	{minttypes.ModuleName, minttypes.EventTypeMint, []string{minttypes.AttributeKeyBondedRatio, minttypes.AttributeKeyInflation, minttypes.AttributeKeyAnnualProvisions, sdk.AttributeKeyAmount}},
+
+	{slashingtypes.ModuleName, slashingtypes.EventTypeSlash, []string{slashingtypes.AttributeKeyAddress, slashingtypes.AttributeKeyPower, slashingtypes.AttributeKeyReason, slashingtypes.AttributeKeyJailed}},
+	{slashingtypes.ModuleName, slashingtypes.EventTypeLiveness, []string{slashingtypes.AttributeKeyAddress, slashingtypes.AttributeKeyMissedBlocks, slashingtypes.AttributeKeyHeight}},
+
+	{stakingtypes.ModuleName, stakingtypes.EventTypeCompleteUnbonding, []string{sdk.AttributeKeyAmount, stakingtypes.AttributeKeyValidator, stakingtypes.AttributeKeyDelegator}},
+	{stakingtypes.ModuleName, stakingtypes.EventTypeCompleteRedelegation, []string{sdk.AttributeKeyAmount, stakingtypes.AttributeKeyDelegator, stakingtypes.AttributeKeySrcValidator, stakingtypes.AttributeKeyDstValidator}},
+	{stakingtypes.ModuleName, stakingtypes.EventTypeCreateValidator, []string{stakingtypes.AttributeKeyValidator, sdk.AttributeKeyAmount}},
+	{stakingtypes.ModuleName, stakingtypes.EventTypeEditValidator, []string{stakingtypes.AttributeKeyCommissionRate, stakingtypes.AttributeKeyMinSelfDelegation}},
+	{stakingtypes.ModuleName, stakingtypes.EventTypeDelegate, []string{stakingtypes.AttributeKeyValidator, sdk.AttributeKeyAmount, stakingtypes.AttributeKeyNewShares}},
+	{stakingtypes.ModuleName, stakingtypes.EventTypeUnbond, []string{stakingtypes.AttributeKeyValidator, sdk.AttributeKeyAmount, stakingtypes.AttributeKeyCompletionTime}},
+	{stakingtypes.ModuleName, stakingtypes.EventTypeRedelegate, []string{stakingtypes.AttributeKeySrcValidator, stakingtypes.AttributeKeyDstValidator, sdk.AttributeKeyAmount, stakingtypes.AttributeKeyCompletionTime}},
+
+	{ibctransfertypes.ModuleName, ibctransfertypes.EventTypePacket, []string{sdk.AttributeKeyModule, sdk.AttributeKeySender, ibctransfertypes.AttributeKeyReceiver, ibctransfertypes.AttributeKeyDenom, ibctransfertypes.AttributeKeyAmount, ibctransfertypes.AttributeKeyAckSuccess, ibctransfertypes.AttributeKeyAck, ibctransfertypes.AttributeKeyAckError}},
+	{ibctransfertypes.ModuleName, ibctransfertypes.EventTypeTransfer, []string{sdk.AttributeKeySender, ibctransfertypes.AttributeKeyReceiver}},
+	{ibctransfertypes.ModuleName, ibctransfertypes.EventTypeTimeout, []string{ibctransfertypes.AttributeKeyRefundReceiver, ibctransfertypes.AttributeKeyRefundDenom, ibctransfertypes.AttributeKeyRefundAmount}},
+	{ibctransfertypes.ModuleName, ibctransfertypes.EventTypeDenomTrace, []string{ibctransfertypes.AttributeKeyTraceHash, ibctransfertypes.AttributeKeyDenom}},
+
+	{wasmtypes.ModuleName, wasmtypes.WasmModuleEventType, []string{wasmtypes.AttributeKeyContractAddr}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeStoreCode, []string{wasmtypes.AttributeKeyChecksum, wasmtypes.AttributeKeyCodeID, wasmtypes.AttributeKeyRequiredCapability}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeInstantiate, []string{wasmtypes.AttributeKeyContractAddr, wasmtypes.AttributeKeyCodeID}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeExecute, []string{wasmtypes.AttributeKeyContractAddr}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeMigrate, []string{wasmtypes.AttributeKeyContractAddr, wasmtypes.AttributeKeyCodeID}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypePinCode, []string{wasmtypes.AttributeKeyCodeID}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeUnpinCode, []string{wasmtypes.AttributeKeyCodeID}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeSudo, []string{wasmtypes.AttributeKeyContractAddr}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeReply, []string{wasmtypes.AttributeKeyContractAddr}},
+	{wasmtypes.ModuleName, wasmtypes.EventTypeGovContractResult, []string{wasmtypes.AttributeKeyResultDataHex}},
+}
+
+func clientAttributes(extra ...string) []string {
+	return append([]string{clienttypes.AttributeKeyClientID, clienttypes.AttributeKeyClientType, clienttypes.AttributeKeyConsensusHeight}, extra...)
+}
+
+func connectionAttributes() []string {
+	return []string{
+		connectiontypes.AttributeKeyConnectionID, connectiontypes.AttributeKeyClientID,
+		connectiontypes.AttributeKeyCounterpartyClientID, connectiontypes.AttributeKeyCounterpartyConnectionID,
+	}
+}
+
+func channelAttributes(extra ...string) []string {
+	return append([]string{
+		channeltypes.AttributeKeyPortID, channeltypes.AttributeKeyChannelID,
+		channeltypes.AttributeCounterpartyPortID, channeltypes.AttributeCounterpartyChannelID,
+		channeltypes.AttributeKeyConnectionID,
+	}, extra...)
+}
+
+func packetAttributes(data ...string) []string {
+	return append(data,
+		channeltypes.AttributeKeyTimeoutHeight, channeltypes.AttributeKeyTimeoutTimestamp, channeltypes.AttributeKeySequence,
+		channeltypes.AttributeKeySrcPort, channeltypes.AttributeKeySrcChannel, channeltypes.AttributeKeyDstPort, channeltypes.AttributeKeyDstChannel,
+		channeltypes.AttributeKeyChannelOrdering, channeltypes.AttributeKeyConnection,
+	)
+}
+
+// typedEventFiles are the proto files of the typed events of the modules,
+// emitted by EmitTypedEvent.
+var typedEventFiles = map[string]string{
+	"authz":      "cosmos/authz/v1beta1/event.proto",
+	"collection": "lbm/collection/v1/event.proto",
+	"foundation": "lbm/foundation/v1/event.proto",
+	"token":      "lbm/token/v1/event.proto",
+	"wasm":       "lbm/wasm/v1/event.proto",
+}
+
+// Catalog returns the events the modules of the app can emit, sorted by the
+// modules and the types.
+func Catalog() ([]Event, error) {
+	events := append([]Event{}, legacyEvents...)
+	for module, file := range typedEventFiles {
+		typed, err := typedEvents(module, file)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, typed...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Module != events[j].Module {
+			return events[i].Module < events[j].Module
+		}
+		return events[i].Type < events[j].Type
+	})
+	return events, nil
+}
+
+// typedEvents returns the typed events declared in the proto file, the
+// messages named Event*, whose attributes are their fields.
+func typedEvents(module, file string) ([]Event, error) {
+	gz := proto.FileDescriptor(file)
+	if gz == nil {
+		return nil, fmt.Errorf("proto file %s not registered", file)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+	bz, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(bz, &fd); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, msg := range fd.MessageType {
+		if !strings.HasPrefix(msg.GetName(), "Event") {
+			continue
+		}
+		event := Event{
+			Module: module,
+			Type:   fd.GetPackage() + "." + msg.GetName(),
+		}
+		for _, field := range msg.Field {
+			event.Attributes = append(event.Attributes, field.GetName())
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
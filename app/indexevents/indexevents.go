@@ -0,0 +1,166 @@
+// Package indexevents resolves the events indexed by the node, given by the
+// index-events of app.toml and a preset of them, whose keys may have the
+// wildcards of the modules and of the event types, into the keys of the
+// attributes marked to index by baseapp.
+package indexevents
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	"github.com/Finschia/finschia-sdk/server"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+)
+
+// options of the events to index in app.toml
+const (
+	OptPreset  = "index_events.preset"
+	OptPresets = "index_events.presets"
+)
+
+// built-in presets
+const (
+	PresetMinimal  = "minimal"
+	PresetExchange = "exchange"
+	PresetExplorer = "explorer"
+)
+
+// Wildcard matches all the events, as well as no events given.
+const Wildcard = "*"
+
+// Presets are the built-in presets of the events to index.
+var Presets = map[string][]string{
+	// the senders and the actions of the messages, and the txs by their
+	// signatures
+	PresetMinimal: {
+		"message.action",
+		"message.sender",
+		"tx.acc_seq",
+		"tx.signature",
+	},
+	// the transfers of the coins of bank and IBC and the tokens of token and
+	// collection, for deposits and withdrawals
+	PresetExchange: {
+		"message.action",
+		"message.sender",
+		"tx.acc_seq",
+		"tx.signature",
+		"bank/*",
+		"token/*",
+		"collection/*",
+		"transfer/*",
+		"recv_packet.*",
+		"acknowledge_packet.*",
+		"timeout_packet.*",
+	},
+	// everything
+	PresetExplorer: {Wildcard},
+}
+
+// FromOptions returns the keys of the events to index by the index-events and
+// the preset of the app options, or nil to index all of them.
+func FromOptions(opts servertypes.AppOptions) ([]string, error) {
+	patterns := cast.ToStringSlice(opts.Get(server.FlagIndexEvents))
+
+	if name := cast.ToString(opts.Get(OptPreset)); name != "" {
+		preset, err := LookupPreset(opts, name)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, preset...)
+	}
+
+	return Resolve(patterns)
+}
+
+// LookupPreset returns the preset of the name, defined by index_events.presets
+// of the app options or built in.
+func LookupPreset(opts servertypes.AppOptions, name string) ([]string, error) {
+	if opt := opts.Get(OptPresets); opt != nil {
+		presets, err := cast.ToStringMapStringSliceE(opt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", OptPresets, err)
+		}
+		if preset, ok := presets[name]; ok {
+			return preset, nil
+		}
+	}
+	if preset, ok := Presets[name]; ok {
+		return preset, nil
+	}
+
+	return nil, fmt.Errorf("unknown preset of index events: %s", name)
+}
+
+// Resolve returns the keys of the events to index in the form of
+// {eventType}.{attributeKey} matched by the patterns, sorted, or nil to index
+// all of them. The patterns are either:
+//
+//	{eventType}.{attributeKey}  as is, e.g. wasm-swap.pool
+//	{eventType}.*               all the attributes of the event type, e.g. transfer.*
+//	{module}/*                  all the events of the module, e.g. bank/*
+//	"*"                         all the events
+func Resolve(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	catalog, err := Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case pattern == Wildcard:
+			return nil, nil
+
+		case strings.HasSuffix(pattern, "/"+Wildcard):
+			module := strings.TrimSuffix(pattern, "/"+Wildcard)
+			found := false
+			for _, event := range catalog {
+				if event.Module == module {
+					found = true
+					for _, key := range event.Keys() {
+						keys[key] = true
+					}
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("no events of module %s: %s", module, pattern)
+			}
+
+		case strings.HasSuffix(pattern, "."+Wildcard):
+			eventType := strings.TrimSuffix(pattern, "."+Wildcard)
+			found := false
+			for _, event := range catalog {
+				if event.Type == eventType {
+					found = true
+					for _, key := range event.Keys() {
+						keys[key] = true
+					}
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown attributes of event %s: %s", eventType, pattern)
+			}
+
+		default:
+			if i := strings.LastIndex(pattern, "."); i <= 0 || i == len(pattern)-1 {
+				return nil, fmt.Errorf("invalid index event, expected {eventType}.{attributeKey}: %s", pattern)
+			}
+			keys[pattern] = true
+		}
+	}
+
+	resolved := make([]string, 0, len(keys))
+	for key := range keys {
+		resolved = append(resolved, key)
+	}
+	sort.Strings(resolved)
+	return resolved, nil
+}
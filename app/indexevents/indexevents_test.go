@@ -0,0 +1,139 @@
+package indexevents_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia-sdk/server"
+
+	linkapp "github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/indexevents"
+	"github.com/Finschia/finschia/app/testutil"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog, err := indexevents.Catalog()
+	require.NoError(t, err)
+
+	events := map[string]indexevents.Event{}
+	for _, event := range catalog {
+		require.NotContains(t, events, event.Type)
+		events[event.Type] = event
+
+		// the modules are of the app
+		if event.Module != indexevents.ModuleBaseApp {
+			require.Contains(t, linkapp.ModuleBasics, event.Module)
+		}
+		require.NotEmpty(t, event.Attributes, event.Type)
+	}
+
+	require.Equal(t, indexevents.Event{
+		Module:     "token",
+		Type:       "lbm.token.v1.EventSent",
+		Attributes: []string{"contract_id", "operator", "from", "to", "amount"},
+	}, events["lbm.token.v1.EventSent"])
+	require.Equal(t, "collection", events["lbm.collection.v1.EventOwnerChanged"].Module)
+	require.Equal(t, "foundation", events["lbm.foundation.v1.EventWithdrawFromTreasury"].Module)
+	require.Equal(t, "bank", events["coin_spent"].Module)
+	require.Equal(t, "blocklist", events["block_address"].Module)
+}
+
+func TestResolve(t *testing.T) {
+	testCases := map[string]struct {
+		patterns []string
+		keys     []string
+		valid    bool
+	}{
+		"none": {
+			valid: true,
+		},
+		"all": {
+			patterns: []string{"message.sender", "*"},
+			valid:    true,
+		},
+		"keys": {
+			patterns: []string{"message.sender", "wasm-swap.pool", "message.sender"},
+			keys:     []string{"message.sender", "wasm-swap.pool"},
+			valid:    true,
+		},
+		"event type": {
+			patterns: []string{"coin_spent.*", "lbm.token.v1.EventMinted.*"},
+			keys: []string{
+				"coin_spent.amount", "coin_spent.spender",
+				"lbm.token.v1.EventMinted.amount", "lbm.token.v1.EventMinted.contract_id", "lbm.token.v1.EventMinted.operator", "lbm.token.v1.EventMinted.to",
+			},
+			valid: true,
+		},
+		"module": {
+			patterns: []string{"blocklist/*", "crisis/*"},
+			keys:     []string{"block_address.address", "invariant.route", "unblock_address.address"},
+			valid:    true,
+		},
+		"unknown module": {
+			patterns: []string{"nosuchmodule/*"},
+		},
+		"unknown event type": {
+			patterns: []string{"wasm-swap.*"},
+		},
+		"invalid key": {
+			patterns: []string{"message"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys, err := indexevents.Resolve(tc.patterns)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.keys, keys)
+		})
+	}
+}
+
+func TestFromOptions(t *testing.T) {
+	// the built-in presets are valid
+	for name, preset := range indexevents.Presets {
+		_, err := indexevents.Resolve(preset)
+		require.NoError(t, err, name)
+	}
+
+	keys, err := indexevents.FromOptions(testutil.AppOptions{
+		server.FlagIndexEvents: []string{"message.sender"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"message.sender"}, keys)
+
+	keys, err = indexevents.FromOptions(testutil.AppOptions{
+		server.FlagIndexEvents: []string{"message.sender"},
+		indexevents.OptPreset:  indexevents.PresetExchange,
+		indexevents.OptPresets: map[string]interface{}{"custom": []interface{}{"crisis/*"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, keys, "coin_received.receiver")
+	require.Contains(t, keys, "lbm.collection.v1.EventSent.to")
+	require.Contains(t, keys, "fungible_token_packet.receiver")
+	require.NotContains(t, keys, "invariant.route")
+
+	// the presets defined override the built-in ones
+	keys, err = indexevents.FromOptions(testutil.AppOptions{
+		indexevents.OptPreset:  indexevents.PresetMinimal,
+		indexevents.OptPresets: map[string]interface{}{indexevents.PresetMinimal: []interface{}{"crisis/*"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"invariant.route"}, keys)
+
+	keys, err = indexevents.FromOptions(testutil.AppOptions{
+		indexevents.OptPreset: indexevents.PresetExplorer,
+	})
+	require.NoError(t, err)
+	require.Nil(t, keys)
+
+	_, err = indexevents.FromOptions(testutil.AppOptions{
+		indexevents.OptPreset: "nosuchpreset",
+	})
+	require.Error(t, err)
+}
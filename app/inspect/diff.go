@@ -0,0 +1,257 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	dbm "github.com/tendermint/tm-db"
+
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	"github.com/Finschia/finschia-sdk/types/kv"
+)
+
+// Diff is a key whose values differ between two states of a store, with the
+// entries of the states, nil where the key is absent.
+type Diff struct {
+	Store string `json:"store"`
+	Key   string `json:"key"`
+	A     *Entry `json:"a"`
+	B     *Entry `json:"b"`
+}
+
+// LoadCommitInfo returns the commit info of the multistore at the version in
+// the application db, holding the hashes of the substores.
+func LoadCommitInfo(db dbm.DB, version int64) (*storetypes.CommitInfo, error) {
+	bz, err := db.Get([]byte(fmt.Sprintf("s/%d", version)))
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, fmt.Errorf("no commit info at height %d", version)
+	}
+
+	var info storetypes.CommitInfo
+	if err := info.Unmarshal(bz); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// DiffStore calls fn on the keys of the store whose values differ between the
+// version a of dbA and the version b of dbB, in the order of the keys, until
+// fn returns true. The IAVL trees of the store are descended from their roots
+// into the subtrees whose hashes differ only.
+func (i Inspector) DiffStore(store string, dbA dbm.DB, a int64, dbB dbm.DB, b int64, fn func(diff Diff) (stop bool)) error {
+	treeA, err := loadTree(dbA, store, a)
+	if err != nil {
+		return err
+	}
+	treeB, err := loadTree(dbB, store, b)
+	if err != nil {
+		return err
+	}
+
+	entry := func(key, value []byte) *Entry {
+		if value == nil {
+			return nil
+		}
+		e := i.Entry(store, kv.Pair{Key: key, Value: value})
+		return &e
+	}
+	_, err = diffNodes(treeA, treeA.root, treeB, treeB.root, func(key, valueA, valueB []byte) bool {
+		return fn(Diff{
+			Store: store,
+			Key:   encodeHex(key),
+			A:     entry(key, valueA),
+			B:     entry(key, valueB),
+		})
+	})
+	return err
+}
+
+// iavlTree reads the nodes of an IAVL tree of a substore in the application
+// db, as stored by the iavl of the SDK.
+type iavlTree struct {
+	db   dbm.DB
+	root []byte
+}
+
+// iavlNode is a node of an IAVL tree, either a leaf with the value or an inner
+// node with the hashes of the children, whose key is the least key of the
+// right child.
+type iavlNode struct {
+	height int8
+	key    []byte
+	value  []byte
+	left   []byte
+	right  []byte
+}
+
+func (n iavlNode) isLeaf() bool {
+	return n.height == 0
+}
+
+func loadTree(db dbm.DB, store string, version int64) (iavlTree, error) {
+	tree := iavlTree{db: dbm.NewPrefixDB(db, []byte("s/k:"+store+"/"))}
+
+	rootKey := make([]byte, 9)
+	rootKey[0] = 'r'
+	binary.BigEndian.PutUint64(rootKey[1:], uint64(version))
+	root, err := tree.db.Get(rootKey)
+	if err != nil {
+		return tree, err
+	}
+	if root == nil {
+		return tree, fmt.Errorf("no version %d of store %s", version, store)
+	}
+	// the root of an empty tree is empty
+	tree.root = root
+	return tree, nil
+}
+
+func (t iavlTree) node(hash []byte) (iavlNode, error) {
+	bz, err := t.db.Get(append([]byte{'n'}, hash...))
+	if err != nil {
+		return iavlNode{}, err
+	}
+	if bz == nil {
+		return iavlNode{}, fmt.Errorf("node %X not found", hash)
+	}
+	return decodeNode(bz)
+}
+
+// decodeNode decodes a node encoded as the height, the size and the version in
+// varint, the key, and either the value or the hashes of the children, in
+// bytes prefixed by their lengths in uvarint.
+func decodeNode(bz []byte) (iavlNode, error) {
+	var node iavlNode
+	var ints [3]int64
+	for i := range ints {
+		value, n := binary.Varint(bz)
+		if n <= 0 {
+			return node, errors.New("invalid iavl node")
+		}
+		ints[i] = value
+		bz = bz[n:]
+	}
+	node.height = int8(ints[0])
+
+	var fields [][]byte
+	for len(bz) != 0 {
+		size, n := binary.Uvarint(bz)
+		if n <= 0 || uint64(len(bz)-n) < size {
+			return node, errors.New("invalid iavl node")
+		}
+		fields = append(fields, bz[n:n+int(size)])
+		bz = bz[n+int(size):]
+	}
+
+	switch {
+	case node.isLeaf() && len(fields) == 2:
+		node.key, node.value = fields[0], fields[1]
+	case !node.isLeaf() && len(fields) == 3:
+		node.key, node.left, node.right = fields[0], fields[1], fields[2]
+	default:
+		return node, errors.New("invalid iavl node")
+	}
+	return node, nil
+}
+
+// diffNodes calls fn on the keys whose values differ between the subtrees of
+// the hashes, which hold the same range of keys. The subtrees are descended
+// together as long as they split the keys at the same key, and their leaves
+// are merged otherwise.
+func diffNodes(a iavlTree, hashA []byte, b iavlTree, hashB []byte, fn func(key, valueA, valueB []byte) (stop bool)) (bool, error) {
+	if bytes.Equal(hashA, hashB) {
+		return false, nil
+	}
+
+	if len(hashA) != 0 && len(hashB) != 0 {
+		nodeA, err := a.node(hashA)
+		if err != nil {
+			return false, err
+		}
+		nodeB, err := b.node(hashB)
+		if err != nil {
+			return false, err
+		}
+		if !nodeA.isLeaf() && !nodeB.isLeaf() && bytes.Equal(nodeA.key, nodeB.key) {
+			stop, err := diffNodes(a, nodeA.left, b, nodeB.left, fn)
+			if stop || err != nil {
+				return stop, err
+			}
+			return diffNodes(a, nodeA.right, b, nodeB.right, fn)
+		}
+	}
+
+	return diffLeaves(newLeafIterator(a, hashA), newLeafIterator(b, hashB), fn)
+}
+
+// diffLeaves merges the leaves of the iterators in the order of their keys.
+func diffLeaves(a, b *leafIterator, fn func(key, valueA, valueB []byte) (stop bool)) (bool, error) {
+	leafA, err := a.next()
+	if err != nil {
+		return false, err
+	}
+	leafB, err := b.next()
+	if err != nil {
+		return false, err
+	}
+
+	for leafA != nil || leafB != nil {
+		var stop bool
+		switch {
+		case leafB == nil || (leafA != nil && bytes.Compare(leafA.key, leafB.key) < 0):
+			stop = fn(leafA.key, leafA.value, nil)
+			leafA, err = a.next()
+		case leafA == nil || bytes.Compare(leafA.key, leafB.key) > 0:
+			stop = fn(leafB.key, nil, leafB.value)
+			leafB, err = b.next()
+		default:
+			if !bytes.Equal(leafA.value, leafB.value) {
+				stop = fn(leafA.key, leafA.value, leafB.value)
+			}
+			if leafA, err = a.next(); err == nil {
+				leafB, err = b.next()
+			}
+		}
+		if stop || err != nil {
+			return stop, err
+		}
+	}
+	return false, nil
+}
+
+// leafIterator iterates the leaves of a subtree in the order of their keys.
+type leafIterator struct {
+	tree  iavlTree
+	stack [][]byte
+}
+
+func newLeafIterator(tree iavlTree, hash []byte) *leafIterator {
+	it := &leafIterator{tree: tree}
+	if len(hash) != 0 {
+		it.stack = append(it.stack, hash)
+	}
+	return it
+}
+
+// next returns the next leaf, or nil at the end.
+func (it *leafIterator) next() (*iavlNode, error) {
+	for len(it.stack) != 0 {
+		hash := it.stack[len(it.stack)-1]
+		it.stack = it.stack[:len(it.stack)-1]
+
+		node, err := it.tree.node(hash)
+		if err != nil {
+			return nil, err
+		}
+		if node.isLeaf() {
+			return &node, nil
+		}
+		it.stack = append(it.stack, node.right, node.left)
+	}
+	return nil, nil
+}
@@ -0,0 +1,94 @@
+package inspect_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/store/rootmulti"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/kv"
+
+	"github.com/Finschia/finschia/app/inspect"
+)
+
+func TestDiffStore(t *testing.T) {
+	keys := sdk.NewKVStoreKeys("bank", "wasm")
+	newStore := func(db dbm.DB) *rootmulti.Store {
+		ms := rootmulti.NewStore(db, log.NewNopLogger())
+		for _, key := range keys {
+			ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+		}
+		require.NoError(t, ms.LoadLatestVersion())
+		return ms
+	}
+
+	// a node and another diverging at the second height
+	dbA, dbB := dbm.NewMemDB(), dbm.NewMemDB()
+	msA, msB := newStore(dbA), newStore(dbB)
+	for _, ms := range []*rootmulti.Store{msA, msB} {
+		for i := 0; i < 100; i++ {
+			ms.GetKVStore(keys["bank"]).Set([]byte(fmt.Sprintf("key%03d", i)), []byte(fmt.Sprintf("value%d", i)))
+		}
+		ms.Commit()
+	}
+	msA.GetKVStore(keys["bank"]).Set([]byte("key010"), []byte("changed"))
+	msA.GetKVStore(keys["bank"]).Delete([]byte("key020"))
+	msA.GetKVStore(keys["bank"]).Set([]byte("key100"), []byte("added"))
+	msA.Commit()
+	msB.Commit()
+
+	infoA, err := inspect.LoadCommitInfo(dbA, 2)
+	require.NoError(t, err)
+	infoB, err := inspect.LoadCommitInfo(dbB, 2)
+	require.NoError(t, err)
+	require.NotEqual(t, infoA.Hash(), infoB.Hash())
+	_, err = inspect.LoadCommitInfo(dbA, 3)
+	require.Error(t, err)
+
+	decoders := sdk.StoreDecoderRegistry{
+		"bank": func(kvA, kvB kv.Pair) string { return fmt.Sprintf("%s\n%s", kvA.Value, kvB.Value) },
+	}
+	inspector := inspect.NewInspector(nil, keys, decoders)
+	diff := func(storeName string, dbA dbm.DB, a int64, dbB dbm.DB, b int64) []string {
+		var diffs []string
+		require.NoError(t, inspector.DiffStore(storeName, dbA, a, dbB, b, func(diff inspect.Diff) bool {
+			s := diff.Key
+			for _, entry := range []*inspect.Entry{diff.A, diff.B} {
+				if entry == nil {
+					s += " -"
+				} else if entry.Decoded != "" {
+					s += " " + entry.Decoded
+				} else {
+					s += " " + entry.Value
+				}
+			}
+			diffs = append(diffs, s)
+			return false
+		}))
+		return diffs
+	}
+
+	expected := []string{
+		"6B6579303130 changed value10",
+		"6B6579303230 - value20",
+		"6B6579313030 added -",
+	}
+	// two nodes at the same height
+	require.Equal(t, expected, diff("bank", dbA, 2, dbB, 2))
+	// two heights of a node
+	require.Equal(t, expected, diff("bank", dbA, 2, dbA, 1))
+	require.Empty(t, diff("bank", dbA, 1, dbB, 2))
+	// the empty stores
+	require.Empty(t, diff("wasm", dbA, 2, dbB, 1))
+
+	// the leaves of a whole tree against an empty one
+	msB.GetKVStore(keys["wasm"]).Set([]byte("code"), []byte("wasm"))
+	msB.Commit()
+	require.Equal(t, []string{"636F6465 - 7761736D"}, diff("wasm", dbA, 2, dbB, 3))
+
+	require.Error(t, inspector.DiffStore("bank", dbA, 5, dbB, 2, func(inspect.Diff) bool { return false }))
+}
@@ -0,0 +1,178 @@
+// Package inspect reads the substores of the state of a node offline, and finds
+// the keys differing between two states of them, decoding their values by the
+// store decoders the modules register for simulations.
+package inspect
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/kv"
+)
+
+// Entry is a key-value pair of a substore, with its value decoded if the
+// module of the store has a decoder for it.
+type Entry struct {
+	Store   string `json:"store"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Decoded string `json:"decoded,omitempty"`
+}
+
+// Inspector reads the substores of a multistore.
+type Inspector struct {
+	ms       sdk.MultiStore
+	keys     map[string]*sdk.KVStoreKey
+	decoders sdk.StoreDecoderRegistry
+}
+
+// NewInspector returns an inspector of the substores of ms by the keys, whose
+// values are decoded by the decoders of the stores.
+func NewInspector(ms sdk.MultiStore, keys map[string]*sdk.KVStoreKey, decoders sdk.StoreDecoderRegistry) Inspector {
+	return Inspector{ms: ms, keys: keys, decoders: decoders}
+}
+
+// StoreNames returns the names of the substores in order.
+func (i Inspector) StoreNames() []string {
+	names := make([]string, 0, len(i.keys))
+	for name := range i.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasDecoder tells whether the values of the store are decoded.
+func (i Inspector) HasDecoder(store string) bool {
+	_, ok := i.decoders[store]
+	return ok
+}
+
+// Iterate calls fn on the entries of the store whose keys have the prefix, in
+// the order of their keys, until fn returns true.
+func (i Inspector) Iterate(store string, prefix []byte, fn func(entry Entry) (stop bool)) error {
+	return i.iterate(store, prefix, func(pair kv.Pair) bool {
+		return fn(i.Entry(store, pair))
+	})
+}
+
+// Account calls fn on the entries of the stores whose keys hold the address,
+// either in bytes or in bech32, which make up the state of the account across
+// the modules. All the stores are searched if none are given.
+func (i Inspector) Account(addr sdk.AccAddress, stores []string, fn func(entry Entry) (stop bool)) error {
+	if len(stores) == 0 {
+		stores = i.StoreNames()
+	}
+
+	bech32 := []byte(addr.String())
+	stop := false
+	for _, store := range stores {
+		err := i.iterate(store, nil, func(pair kv.Pair) bool {
+			if bytes.Contains(pair.Key, addr) || bytes.Contains(pair.Key, bech32) {
+				stop = fn(i.Entry(store, pair))
+			}
+			return stop
+		})
+		if err != nil {
+			return err
+		}
+		if stop {
+			break
+		}
+	}
+	return nil
+}
+
+func (i Inspector) iterate(name string, prefix []byte, fn func(pair kv.Pair) (stop bool)) error {
+	key, ok := i.keys[name]
+	if !ok {
+		return fmt.Errorf("unknown store: %s", name)
+	}
+
+	store := i.ms.GetKVStore(key)
+	var iterator sdk.Iterator
+	if len(prefix) == 0 {
+		iterator = store.Iterator(nil, nil)
+	} else {
+		iterator = sdk.KVStorePrefixIterator(store, prefix)
+	}
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		if fn(kv.Pair{Key: iterator.Key(), Value: iterator.Value()}) {
+			break
+		}
+	}
+	return nil
+}
+
+// Entry returns the entry of the pair of the store, with its value decoded.
+func (i Inspector) Entry(store string, pair kv.Pair) Entry {
+	return Entry{
+		Store:   store,
+		Key:     encodeHex(pair.Key),
+		Value:   encodeHex(pair.Value),
+		Decoded: i.Decode(store, pair),
+	}
+}
+
+// encodeHex encodes the bytes in upper case hex, as the keys are shown by the
+// SDK.
+func encodeHex(bz []byte) string {
+	return strings.ToUpper(hex.EncodeToString(bz))
+}
+
+// Decode returns the value of the pair of the store decoded, or an empty
+// string if the store has no decoder or the decoder fails on the pair.
+//
+// The decoders compare two pairs for the simulations, so that the value is
+// decoded out of the comparison of the pair with itself.
+func (i Inspector) Decode(store string, pair kv.Pair) (decoded string) {
+	decoder, ok := i.decoders[store]
+	if !ok {
+		return ""
+	}
+	defer func() {
+		// the decoders panic on the keys they do not know
+		if r := recover(); r != nil {
+			decoded = ""
+		}
+	}()
+
+	decoded = single(decoder(pair, pair))
+	if strings.Contains(decoded, "(PANIC=") {
+		// the value failed to format
+		return ""
+	}
+	return decoded
+}
+
+// single returns one side of the comparison of the decoders of a value with
+// itself, either "{value}\n{value}" or labeled as "{label}A: {value}\n{label}B:
+// {value}", or the whole comparison if it has neither of the forms.
+func single(comparison string) string {
+	for n := strings.Index(comparison, "\n"); n >= 0; {
+		a := strings.TrimSuffix(comparison[:n], "\n")
+		b := strings.TrimSuffix(comparison[n+1:], "\n")
+		if a == b {
+			return a
+		}
+		for _, labels := range [][2]string{{" A:", " B:"}, {"A:", "B:"}} {
+			unlabeledA := strings.Replace(a, labels[0], ":", 1)
+			unlabeledB := strings.Replace(b, labels[1], ":", 1)
+			if unlabeledA != a && strings.EqualFold(unlabeledA, unlabeledB) {
+				return unlabeledA
+			}
+		}
+
+		next := strings.Index(comparison[n+1:], "\n")
+		if next < 0 {
+			break
+		}
+		n += next + 1
+	}
+	return strings.TrimSuffix(comparison, "\n")
+}
@@ -0,0 +1,93 @@
+package inspect_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/store/rootmulti"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/kv"
+
+	"github.com/Finschia/finschia/app/inspect"
+)
+
+func newInspector(t *testing.T) (inspect.Inspector, sdk.AccAddress) {
+	keys := sdk.NewKVStoreKeys("acc", "bank", "wasm")
+	ms := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	for _, key := range keys {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	}
+	require.NoError(t, ms.LoadLatestVersion())
+
+	addr := sdk.AccAddress("addr________________")
+	other := sdk.AccAddress("other_______________")
+	ms.GetKVStore(keys["acc"]).Set(append([]byte{0x01}, addr...), []byte("account"))
+	ms.GetKVStore(keys["acc"]).Set(append([]byte{0x01}, other...), []byte("other account"))
+	ms.GetKVStore(keys["bank"]).Set(append(append([]byte{0x02}, addr...), "stake"...), []byte("10"))
+	ms.GetKVStore(keys["bank"]).Set(append([]byte{0x03}, "stake"...), []byte("100"))
+	ms.GetKVStore(keys["wasm"]).Set(append([]byte{0x04}, addr.String()...), []byte("contract"))
+	ms.Commit()
+
+	decoders := sdk.StoreDecoderRegistry{
+		"acc": func(kvA, kvB kv.Pair) string {
+			return fmt.Sprintf("%s\n%s", kvA.Value, kvB.Value)
+		},
+		"bank": func(kvA, kvB kv.Pair) string {
+			if kvA.Key[0] != 0x02 {
+				panic("unknown key")
+			}
+			return fmt.Sprintf("BalanceA: %s\nBalanceB: %s", kvA.Value, kvB.Value)
+		},
+	}
+	return inspect.NewInspector(ms.CacheMultiStore(), keys, decoders), addr
+}
+
+func TestIterate(t *testing.T) {
+	inspector, addr := newInspector(t)
+	require.Equal(t, []string{"acc", "bank", "wasm"}, inspector.StoreNames())
+	require.True(t, inspector.HasDecoder("acc"))
+	require.False(t, inspector.HasDecoder("wasm"))
+
+	var entries []inspect.Entry
+	collect := func(entry inspect.Entry) bool {
+		entries = append(entries, entry)
+		return false
+	}
+	require.NoError(t, inspector.Iterate("bank", nil, collect))
+	require.Equal(t, []inspect.Entry{
+		{Store: "bank", Key: fmt.Sprintf("02%X7374616B65", addr.Bytes()), Value: "3130", Decoded: "Balance: 10"},
+		{Store: "bank", Key: "037374616B65", Value: "313030"},
+	}, entries)
+
+	entries = nil
+	require.NoError(t, inspector.Iterate("acc", []byte{0x01}, func(entry inspect.Entry) bool {
+		entries = append(entries, entry)
+		return true
+	}))
+	require.Len(t, entries, 1)
+	require.Equal(t, "account", entries[0].Decoded)
+
+	require.Error(t, inspector.Iterate("unknown", nil, collect))
+}
+
+func TestAccount(t *testing.T) {
+	inspector, addr := newInspector(t)
+
+	var stores []string
+	require.NoError(t, inspector.Account(addr, nil, func(entry inspect.Entry) bool {
+		stores = append(stores, entry.Store)
+		return false
+	}))
+	require.Equal(t, []string{"acc", "bank", "wasm"}, stores)
+
+	stores = nil
+	require.NoError(t, inspector.Account(addr, []string{"bank"}, func(entry inspect.Entry) bool {
+		stores = append(stores, entry.Store)
+		return false
+	}))
+	require.Equal(t, []string{"bank"}, stores)
+}
@@ -0,0 +1,65 @@
+package invariants
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	bankplustypes "github.com/Finschia/finschia-sdk/x/bankplus/types"
+)
+
+// inactiveAddrsKeyPrefix is the prefix of the inactive addresses of bankplus
+// in the bank store, followed by the addresses.
+var inactiveAddrsKeyPrefix = []byte{0xa0}
+
+// InactiveAddressesInvariant checks that the inactive addresses stored by
+// bankplus are the inactive contracts of wasmplus, stored under their own keys
+// and cached by bankplus.
+func InactiveAddressesInvariant(bk InactiveAddrKeeper, wk InactiveContractKeeper, bankKey storetypes.StoreKey, cdc codec.BinaryCodec) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		// cache, we don't want to write changes
+		ctx, _ = ctx.CacheContext()
+
+		var r report
+		stored := map[string]bool{}
+		iterator := sdk.KVStorePrefixIterator(ctx.KVStore(bankKey), inactiveAddrsKeyPrefix)
+		defer iterator.Close()
+		for ; iterator.Valid(); iterator.Next() {
+			addr := sdk.AccAddress(iterator.Key()[len(inactiveAddrsKeyPrefix):])
+
+			var inactive bankplustypes.InactiveAddr
+			if err := cdc.Unmarshal(iterator.Value(), &inactive); err != nil {
+				r.add("inactive address %s; %s", addr, err)
+				continue
+			}
+			if value, err := sdk.AccAddressFromBech32(inactive.Address); err != nil || !bytes.Equal(value, addr) {
+				r.add("inactive address %s; stored as %s", addr, inactive.Address)
+			}
+			if !bk.IsInactiveAddr(addr) {
+				r.add("inactive address %s not cached", addr)
+			}
+			stored[addr.String()] = true
+		}
+
+		wk.IterateInactiveContracts(ctx, func(contractAddress sdk.AccAddress) (stop bool) {
+			if !stored[contractAddress.String()] {
+				r.add("inactive contract %s not an inactive address", contractAddress)
+			}
+			delete(stored, contractAddress.String())
+			return false
+		})
+
+		addrs := make([]string, 0, len(stored))
+		for addr := range stored {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		for _, addr := range addrs {
+			r.add("inactive address %s not an inactive contract", addr)
+		}
+
+		return r.format(ModuleBankPlus, RouteInactiveAddresses, "inactive addresses of bankplus against the inactive contracts")
+	}
+}
@@ -0,0 +1,172 @@
+package invariants
+
+import (
+	"sort"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	collectionkeeper "github.com/Finschia/finschia-sdk/x/collection/keeper"
+)
+
+// CollectionSupplyInvariant checks that the supply of every token class of the
+// collection contracts equals the sum of its balances along with the nfts
+// attached to others, and the amount minted minus the amount burnt.
+func CollectionSupplyInvariant(k collectionkeeper.Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		// cache, we don't want to write changes
+		ctx, _ = ctx.CacheContext()
+
+		state := k.ExportGenesis(ctx)
+
+		// contract id -> class id -> amount
+		amounts := map[string]map[string]sdk.Int{}
+		add := func(contractID, tokenID string, amount sdk.Int) {
+			if amounts[contractID] == nil {
+				amounts[contractID] = map[string]sdk.Int{}
+			}
+			classID := collection.SplitTokenID(tokenID)
+			if sum, ok := amounts[contractID][classID]; ok {
+				amount = sum.Add(amount)
+			}
+			amounts[contractID][classID] = amount
+		}
+		for _, contractBalances := range state.Balances {
+			for _, balance := range contractBalances.Balances {
+				for _, coin := range balance.Amount {
+					add(contractBalances.ContractId, coin.TokenId, coin.Amount)
+				}
+			}
+		}
+		// the nfts attached are not in the balances
+		for _, contractParents := range state.Parents {
+			for _, relation := range contractParents.Relations {
+				add(contractParents.ContractId, relation.Self, sdk.OneInt())
+			}
+		}
+
+		var r report
+		for _, contractClasses := range state.Classes {
+			contractID := contractClasses.ContractId
+			for i := range contractClasses.Classes {
+				classID := collection.TokenClassFromAny(&contractClasses.Classes[i]).GetId()
+				supply := k.GetSupply(ctx, contractID, classID)
+				amount, ok := amounts[contractID][classID]
+				if !ok {
+					amount = sdk.ZeroInt()
+				}
+				delete(amounts[contractID], classID)
+
+				if !supply.Equal(amount) {
+					r.add("supply of %s of %s; expected %s in balances, got %s", classID, contractID, supply, amount)
+				}
+				if minted, burnt := k.GetMinted(ctx, contractID, classID), k.GetBurnt(ctx, contractID, classID); !supply.Equal(minted.Sub(burnt)) {
+					r.add("supply of %s of %s; expected %s minted minus %s burnt, got %s", classID, contractID, minted, burnt, supply)
+				}
+			}
+		}
+
+		contractIDs := make([]string, 0, len(amounts))
+		for contractID := range amounts {
+			contractIDs = append(contractIDs, contractID)
+		}
+		sort.Strings(contractIDs)
+		for _, contractID := range contractIDs {
+			classIDs := make([]string, 0, len(amounts[contractID]))
+			for classID := range amounts[contractID] {
+				classIDs = append(classIDs, classID)
+			}
+			sort.Strings(classIDs)
+			for _, classID := range classIDs {
+				r.add("balances of unknown class %s of %s; %s", classID, contractID, amounts[contractID][classID])
+			}
+		}
+
+		return r.format(ModuleCollection, RouteSupply, "supplies of collection token classes against their balances")
+	}
+}
+
+// CollectionOwnershipInvariant checks that every nft of the collection
+// contracts is either owned by exactly one account with the balance of one,
+// or attached to another nft, and that no balances nor relations refer to the
+// nfts which do not exist.
+func CollectionOwnershipInvariant(k collectionkeeper.Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		// cache, we don't want to write changes
+		ctx, _ = ctx.CacheContext()
+
+		state := k.ExportGenesis(ctx)
+
+		// contract id -> token id -> owners and parents
+		type ownership struct {
+			exists  bool
+			owners  []string
+			parents int
+		}
+		nfts := map[string]map[string]*ownership{}
+		get := func(contractID, tokenID string) *ownership {
+			if nfts[contractID] == nil {
+				nfts[contractID] = map[string]*ownership{}
+			}
+			if nfts[contractID][tokenID] == nil {
+				nfts[contractID][tokenID] = &ownership{}
+			}
+			return nfts[contractID][tokenID]
+		}
+
+		var r report
+		for _, contractNFTs := range state.Nfts {
+			for _, nft := range contractNFTs.Nfts {
+				get(contractNFTs.ContractId, nft.TokenId).exists = true
+			}
+		}
+		for _, contractBalances := range state.Balances {
+			contractID := contractBalances.ContractId
+			for _, balance := range contractBalances.Balances {
+				for _, coin := range balance.Amount {
+					if collection.ValidateNFTID(coin.TokenId) != nil {
+						continue
+					}
+					if !coin.Amount.Equal(sdk.OneInt()) {
+						r.add("balance of nft %s of %s by %s; expected 1, got %s", coin.TokenId, contractID, balance.Address, coin.Amount)
+					}
+					nft := get(contractID, coin.TokenId)
+					nft.owners = append(nft.owners, balance.Address)
+				}
+			}
+		}
+		for _, contractParents := range state.Parents {
+			contractID := contractParents.ContractId
+			for _, relation := range contractParents.Relations {
+				get(contractID, relation.Self).parents++
+				if parent := get(contractID, relation.Other); !parent.exists {
+					r.add("parent %s of nft %s of %s not found", relation.Other, relation.Self, contractID)
+				}
+			}
+		}
+
+		contractIDs := make([]string, 0, len(nfts))
+		for contractID := range nfts {
+			contractIDs = append(contractIDs, contractID)
+		}
+		sort.Strings(contractIDs)
+		for _, contractID := range contractIDs {
+			tokenIDs := make([]string, 0, len(nfts[contractID]))
+			for tokenID := range nfts[contractID] {
+				tokenIDs = append(tokenIDs, tokenID)
+			}
+			sort.Strings(tokenIDs)
+
+			for _, tokenID := range tokenIDs {
+				nft := nfts[contractID][tokenID]
+				switch {
+				case !nft.exists && (len(nft.owners) != 0 || nft.parents != 0):
+					r.add("nft %s of %s not found; owned by %v with %d parents", tokenID, contractID, nft.owners, nft.parents)
+				case nft.exists && len(nft.owners)+nft.parents != 1:
+					r.add("nft %s of %s; expected a single owner or parent, got owners %v and %d parents", tokenID, contractID, nft.owners, nft.parents)
+				}
+			}
+		}
+
+		return r.format(ModuleCollection, RouteOwnership, "ownership of collection nfts")
+	}
+}
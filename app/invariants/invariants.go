@@ -0,0 +1,74 @@
+// Package invariants has the invariants of the modules of Finschia which the
+// modules do not register themselves, and the schedule asserting the
+// invariants each at its own period instead of all of them at the period of
+// crisis.
+//
+// NOTE: the treasury of foundation against the balance of its module account
+// is checked by foundation/module-accounts of the foundation module.
+package invariants
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	collectionkeeper "github.com/Finschia/finschia-sdk/x/collection/keeper"
+	tokenkeeper "github.com/Finschia/finschia-sdk/x/token/keeper"
+)
+
+// modules and routes of the invariants
+const (
+	ModuleToken      = "token"
+	ModuleCollection = "collection"
+	ModuleBankPlus   = "bankplus"
+
+	RouteSupply            = "supply"
+	RouteOwnership         = "ownership"
+	RouteInactiveAddresses = "inactive-addresses"
+)
+
+// InactiveAddrKeeper tells the inactive addresses cached by bankplus.
+type InactiveAddrKeeper interface {
+	IsInactiveAddr(address sdk.AccAddress) bool
+}
+
+// InactiveContractKeeper iterates the inactive contracts of wasmplus.
+type InactiveContractKeeper interface {
+	IterateInactiveContracts(ctx sdk.Context, fn func(contractAddress sdk.AccAddress) (stop bool))
+}
+
+// RegisterInvariants registers the invariants of token, collection and
+// bankplus, whose inactive addresses are stored under the bank store key.
+func RegisterInvariants(
+	ir sdk.InvariantRegistry,
+	tk tokenkeeper.Keeper,
+	ck collectionkeeper.Keeper,
+	bk InactiveAddrKeeper,
+	wk InactiveContractKeeper,
+	bankKey storetypes.StoreKey,
+	cdc codec.BinaryCodec,
+) {
+	ir.RegisterRoute(ModuleToken, RouteSupply, TokenSupplyInvariant(tk))
+	ir.RegisterRoute(ModuleCollection, RouteSupply, CollectionSupplyInvariant(ck))
+	ir.RegisterRoute(ModuleCollection, RouteOwnership, CollectionOwnershipInvariant(ck))
+	ir.RegisterRoute(ModuleBankPlus, RouteInactiveAddresses, InactiveAddressesInvariant(bk, wk, bankKey, cdc))
+}
+
+// report collects the findings of an invariant.
+type report struct {
+	msgs []string
+}
+
+func (r *report) add(format string, args ...interface{}) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+func (r *report) format(module, route, summary string) (string, bool) {
+	msg := summary + "\n"
+	if len(r.msgs) != 0 {
+		msg += strings.Join(r.msgs, "\n") + "\n"
+	}
+	return sdk.FormatInvariant(module, route, msg), len(r.msgs) != 0
+}
@@ -0,0 +1,117 @@
+package invariants_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	bankplustypes "github.com/Finschia/finschia-sdk/x/bankplus/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+	wasmplustypes "github.com/Finschia/wasmd/x/wasmplus/types"
+
+	linkapp "github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/helpers"
+	"github.com/Finschia/finschia/app/invariants"
+)
+
+func setup(t *testing.T) (*linkapp.LinkApp, sdk.Context) {
+	app := helpers.Setup(t, false, 0)
+	return app, app.BaseApp.NewContext(false, tmproto.Header{Height: app.LastBlockHeight() + 1})
+}
+
+func TestTokenSupplyInvariant(t *testing.T) {
+	app, ctx := setup(t)
+	owner, holder := sdk.AccAddress("owner_______________"), sdk.AccAddress("holder______________")
+
+	contractID := app.TokenKeeper.Issue(ctx, token.Contract{Name: "test", Symbol: "TT", Mintable: true}, owner, owner, sdk.NewInt(10))
+	require.NoError(t, app.TokenKeeper.Send(ctx, contractID, owner, holder, sdk.NewInt(3)))
+	require.NoError(t, app.TokenKeeper.Burn(ctx, contractID, owner, sdk.NewInt(1)))
+
+	invariant := invariants.TokenSupplyInvariant(app.TokenKeeper)
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	// the supply out of the balances
+	app.TokenKeeper.InitGenesis(ctx, &token.GenesisState{
+		ClassState: app.TokenKeeper.ExportGenesis(ctx).ClassState,
+		Supplies:   []token.ContractCoin{{ContractId: contractID, Amount: sdk.NewInt(100)}},
+	})
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "expected 100 in balances, got 9")
+	require.Contains(t, msg, "expected 10 minted minus 1 burnt, got 100")
+}
+
+func TestCollectionInvariants(t *testing.T) {
+	app, ctx := setup(t)
+	owner, holder := sdk.AccAddress("owner_______________"), sdk.AccAddress("holder______________")
+
+	contractID := app.CollectionKeeper.CreateContract(ctx, owner, collection.Contract{Name: "test"})
+	classID, err := app.CollectionKeeper.CreateTokenClass(ctx, contractID, &collection.NFTClass{Name: "nft"})
+	require.NoError(t, err)
+	nfts, err := app.CollectionKeeper.MintNFT(ctx, contractID, owner, []collection.MintNFTParam{
+		{TokenType: *classID, Name: "parent"},
+		{TokenType: *classID, Name: "child"},
+		{TokenType: *classID, Name: "other"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, app.CollectionKeeper.Attach(ctx, contractID, owner, nfts[1].TokenId, nfts[0].TokenId))
+	require.NoError(t, app.CollectionKeeper.SendCoins(ctx, contractID, owner, holder, collection.NewCoins(collection.NewNFTCoin(*classID, 3))))
+
+	supply := invariants.CollectionSupplyInvariant(app.CollectionKeeper)
+	ownership := invariants.CollectionOwnershipInvariant(app.CollectionKeeper)
+	_, broken := supply(ctx)
+	require.False(t, broken)
+	_, broken = ownership(ctx)
+	require.False(t, broken)
+
+	// the child attached held by the holder too
+	app.CollectionKeeper.InitGenesis(ctx, &collection.GenesisState{
+		Params: app.CollectionKeeper.GetParams(ctx),
+		Balances: []collection.ContractBalances{{
+			ContractId: contractID,
+			Balances: []collection.Balance{{
+				Address: holder.String(),
+				Amount:  collection.NewCoins(collection.NewCoin(nfts[1].TokenId, sdk.OneInt())),
+			}},
+		}},
+	})
+	msg, broken := supply(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "expected 3 in balances, got 4")
+	msg, broken = ownership(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, nfts[1].TokenId)
+}
+
+func TestInactiveAddressesInvariant(t *testing.T) {
+	app, ctx := setup(t)
+	contract := sdk.AccAddress("contract____________")
+
+	invariant := invariants.InactiveAddressesInvariant(app.BankKeeper, app.WasmKeeper, app.GetKey(banktypes.StoreKey), app.AppCodec())
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	// inactive in bankplus only
+	app.BankKeeper.AddToInactiveAddr(ctx, contract)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "not an inactive contract")
+
+	// and in wasmplus
+	ctx.KVStore(app.GetKey(wasmplustypes.StoreKey)).Set(wasmplustypes.GetInactiveContractKey(contract), contract)
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	// and not cached
+	app.BankKeeper.DeleteFromInactiveAddr(ctx, contract)
+	inactive := bankplustypes.InactiveAddr{Address: contract.String()}
+	ctx.KVStore(app.GetKey(banktypes.StoreKey)).Set(append([]byte{0xa0}, contract...), app.AppCodec().MustMarshal(&inactive))
+	msg, broken = invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "not cached")
+}
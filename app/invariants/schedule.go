@@ -0,0 +1,141 @@
+package invariants
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cast"
+
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	crisiskeeper "github.com/Finschia/finschia-sdk/x/crisis/keeper"
+	crisistypes "github.com/Finschia/finschia-sdk/x/crisis/types"
+)
+
+// OptPeriods is the option of app.toml of the periods of the invariants in
+// blocks, by their full routes ({module}/{route}) or by their modules.
+const OptPeriods = "invariants.periods"
+
+// Schedule tells the period of each invariant, so that the expensive ones are
+// asserted less often than the others. The invariants are asserted at the
+// heights divisible by their periods, and never with the period of zero.
+type Schedule struct {
+	period  uint
+	periods map[string]uint
+}
+
+// NewSchedule returns a schedule of the periods by the full routes or the
+// modules of the invariants, and of period for the other invariants.
+func NewSchedule(period uint, periods map[string]uint) Schedule {
+	return Schedule{period: period, periods: periods}
+}
+
+// ScheduleFromOptions returns the schedule of the periods of the app options,
+// and of period, the inv-check-period, for the other invariants.
+func ScheduleFromOptions(opts servertypes.AppOptions, period uint) (Schedule, error) {
+	periods := map[string]uint{}
+	if opt := opts.Get(OptPeriods); opt != nil {
+		values, err := cast.ToStringMapE(opt)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid %s: %w", OptPeriods, err)
+		}
+		for name, value := range values {
+			p, err := cast.ToUintE(value)
+			if err != nil {
+				return Schedule{}, fmt.Errorf("invalid period of %s in %s: %w", name, OptPeriods, err)
+			}
+			periods[name] = p
+		}
+	}
+
+	return NewSchedule(period, periods), nil
+}
+
+// Period returns the period of the invariant in blocks, given by its full
+// route, its module or the default period in that order.
+func (s Schedule) Period(route crisistypes.InvarRoute) uint {
+	if period, ok := s.periods[route.FullRoute()]; ok {
+		return period
+	}
+	if period, ok := s.periods[route.ModuleName]; ok {
+		return period
+	}
+	return s.period
+}
+
+// Due returns the invariants to assert at the height.
+func (s Schedule) Due(height int64, routes []crisistypes.InvarRoute) []crisistypes.InvarRoute {
+	var due []crisistypes.InvarRoute
+	for _, route := range routes {
+		if period := s.Period(route); period != 0 && height%int64(period) == 0 {
+			due = append(due, route)
+		}
+	}
+	return due
+}
+
+// AssertInvariants asserts the invariants of the crisis keeper due at the
+// height of ctx, and panics on a broken one as crisis does.
+func (s Schedule) AssertInvariants(ctx sdk.Context, k crisiskeeper.Keeper) {
+	routes := s.Due(ctx.BlockHeight(), k.Routes())
+	if len(routes) == 0 {
+		return
+	}
+
+	logger := k.Logger(ctx)
+	start := time.Now()
+	n := len(routes)
+	for i, ir := range routes {
+		logger.Info("asserting crisis invariants", "inv", fmt.Sprint(i+1, "/", n), "name", ir.FullRoute())
+		if res, stop := ir.Invar(ctx); stop {
+			panic(fmt.Errorf("invariant broken: %s\n"+
+				"\tCRITICAL please submit the following transaction:\n"+
+				"\t\t tx crisis invariant-broken %s %s", res, ir.ModuleName, ir.Route))
+		}
+	}
+
+	logger.Info("asserted the scheduled invariants", "count", n, "duration", time.Since(start), "height", ctx.BlockHeight())
+}
+
+// Result is the result of an invariant checked.
+type Result struct {
+	Route    string        `json:"route"`
+	Broken   bool          `json:"broken"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Check checks the invariants without panicking, in the order of their full
+// routes. An invariant panicking is reported broken with the panic.
+func Check(ctx sdk.Context, routes []crisistypes.InvarRoute) []Result {
+	routes = append([]crisistypes.InvarRoute{}, routes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].FullRoute() < routes[j].FullRoute()
+	})
+
+	results := make([]Result, 0, len(routes))
+	for _, route := range routes {
+		results = append(results, check(ctx, route))
+	}
+	return results
+}
+
+func check(ctx sdk.Context, route crisistypes.InvarRoute) (result Result) {
+	result.Route = route.FullRoute()
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result.Broken = true
+			result.Message = fmt.Sprintf("panic: %v", r)
+		}
+		result.Duration = time.Since(start)
+	}()
+
+	msg, broken := route.Invar(ctx)
+	if broken {
+		result.Broken = true
+		result.Message = msg
+	}
+	return result
+}
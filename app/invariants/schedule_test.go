@@ -0,0 +1,67 @@
+package invariants_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	crisistypes "github.com/Finschia/finschia-sdk/x/crisis/types"
+
+	"github.com/Finschia/finschia/app/invariants"
+	"github.com/Finschia/finschia/app/testutil"
+)
+
+func TestSchedule(t *testing.T) {
+	ok := func(sdk.Context) (string, bool) { return "", false }
+	routes := []crisistypes.InvarRoute{
+		crisistypes.NewInvarRoute("bank", "total-supply", ok),
+		crisistypes.NewInvarRoute("collection", "supply", ok),
+		crisistypes.NewInvarRoute("collection", "ownership", ok),
+		crisistypes.NewInvarRoute("token", "supply", ok),
+	}
+
+	schedule, err := invariants.ScheduleFromOptions(testutil.AppOptions{
+		invariants.OptPeriods: map[string]interface{}{
+			"collection":           int64(10),
+			"collection/ownership": int64(100),
+			"token":                int64(0),
+		},
+	}, 2)
+	require.NoError(t, err)
+
+	fullRoutes := func(height int64) []string {
+		var names []string
+		for _, route := range schedule.Due(height, routes) {
+			names = append(names, route.FullRoute())
+		}
+		return names
+	}
+	require.Equal(t, []string{"bank/total-supply"}, fullRoutes(2))
+	require.Equal(t, []string{"bank/total-supply", "collection/supply"}, fullRoutes(10))
+	require.Equal(t, []string{"bank/total-supply", "collection/supply", "collection/ownership"}, fullRoutes(100))
+	require.Empty(t, fullRoutes(3))
+
+	_, err = invariants.ScheduleFromOptions(testutil.AppOptions{invariants.OptPeriods: map[string]interface{}{"token": "weekly"}}, 1)
+	require.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	routes := []crisistypes.InvarRoute{
+		crisistypes.NewInvarRoute("b", "panicking", func(sdk.Context) (string, bool) { panic("oops") }),
+		crisistypes.NewInvarRoute("a", "broken", func(sdk.Context) (string, bool) { return "broken", true }),
+		crisistypes.NewInvarRoute("a", "ok", func(sdk.Context) (string, bool) { return "ok", false }),
+	}
+
+	results := invariants.Check(sdk.Context{}, routes)
+	require.Len(t, results, 3)
+	require.Equal(t, "a/broken", results[0].Route)
+	require.True(t, results[0].Broken)
+	require.Equal(t, "broken", results[0].Message)
+	require.Equal(t, "a/ok", results[1].Route)
+	require.False(t, results[1].Broken)
+	require.Empty(t, results[1].Message)
+	require.Equal(t, "b/panicking", results[2].Route)
+	require.True(t, results[2].Broken)
+	require.Equal(t, "panic: oops", results[2].Message)
+}
@@ -0,0 +1,56 @@
+package invariants
+
+import (
+	"sort"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	tokenkeeper "github.com/Finschia/finschia-sdk/x/token/keeper"
+)
+
+// TokenSupplyInvariant checks that the supply of every token contract equals
+// the sum of its balances, and the amount minted minus the amount burnt.
+func TokenSupplyInvariant(k tokenkeeper.Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		// cache, we don't want to write changes
+		ctx, _ = ctx.CacheContext()
+
+		state := k.ExportGenesis(ctx)
+		balances := map[string]sdk.Int{}
+		for _, contractBalances := range state.Balances {
+			sum := sdk.ZeroInt()
+			for _, balance := range contractBalances.Balances {
+				sum = sum.Add(balance.Amount)
+			}
+			balances[contractBalances.ContractId] = sum
+		}
+
+		var r report
+		for _, class := range state.Classes {
+			contractID := class.Id
+			supply := k.GetSupply(ctx, contractID)
+			balance, ok := balances[contractID]
+			if !ok {
+				balance = sdk.ZeroInt()
+			}
+			delete(balances, contractID)
+
+			if !supply.Equal(balance) {
+				r.add("supply of %s; expected %s in balances, got %s", contractID, supply, balance)
+			}
+			if minted, burnt := k.GetMinted(ctx, contractID), k.GetBurnt(ctx, contractID); !supply.Equal(minted.Sub(burnt)) {
+				r.add("supply of %s; expected %s minted minus %s burnt, got %s", contractID, minted, burnt, supply)
+			}
+		}
+
+		unknown := make([]string, 0, len(balances))
+		for contractID := range balances {
+			unknown = append(unknown, contractID)
+		}
+		sort.Strings(unknown)
+		for _, contractID := range unknown {
+			r.add("balances of unknown contract %s; %s", contractID, balances[contractID])
+		}
+
+		return r.format(ModuleToken, RouteSupply, "supplies of token contracts against their balances")
+	}
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/spf13/cast"
+
+	"github.com/Finschia/finschia-sdk/baseapp"
+	"github.com/Finschia/finschia-sdk/codec"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/app/history"
+	"github.com/Finschia/finschia/app/indexer"
+	appparams "github.com/Finschia/finschia/app/params"
+	"github.com/Finschia/finschia/app/streaming"
+)
+
+// OptServices is the app option running the streaming services, the indexer
+// and the history configured by the other options. It is set by the commands
+// running the node, which close the app once the node stops, so that the
+// other commands loading the app, e.g. export, neither open nor recover them.
+const OptServices = "run_services"
+
+// services are the streaming services of the app, including the ones feeding
+// the indexer and the history.
+type services struct {
+	streaming []streaming.Service
+	wg        *sync.WaitGroup
+	indexer   *indexer.Indexer
+	history   history.QueryServer
+
+	// streamBeforeCommit is set if the blocks must not be lost, either by
+	// OptStopNodeOnErr or by a file streaming service syncing the files
+	streamBeforeCommit bool
+}
+
+// loadServices constructs the services configured by the options and registers
+// them with the BaseApp, or none unless OptServices is set.
+func loadServices(bApp *baseapp.BaseApp, appOpts servertypes.AppOptions, keys map[string]*sdk.KVStoreKey, encodingConfig appparams.EncodingConfig, appCodec codec.Codec) (services, error) {
+	if !cast.ToBool(appOpts.Get(OptServices)) {
+		return services{}, nil
+	}
+
+	// configure state listening capabilities using AppOptions
+	streamingServices, wg, err := streaming.LoadStreamingServices(bApp, appOpts, keys)
+	if err != nil {
+		return services{}, err
+	}
+	svcs := services{
+		streaming:          streamingServices,
+		wg:                 wg,
+		streamBeforeCommit: cast.ToBool(appOpts.Get(streaming.OptStopNodeOnErr)),
+	}
+	for _, service := range streamingServices {
+		if _, ok := service.(*streaming.FileStreamingService); ok && cast.ToBool(appOpts.Get(streaming.OptFileFsync)) {
+			svcs.streamBeforeCommit = true
+		}
+	}
+	fail := func(err error) (services, error) {
+		for _, service := range svcs.streaming {
+			_ = service.Close()
+		}
+		return services{}, err
+	}
+
+	// the indexer is fed by a streaming service of the blocks
+	if cast.ToBool(appOpts.Get(indexer.OptEnable)) {
+		sqlIndexer, service, err := indexer.NewStreamingServiceFromOptions(appOpts, encodingConfig.TxConfig.TxDecoder(), appCodec)
+		if err != nil {
+			return fail(err)
+		}
+		bApp.SetStreamingService(service)
+		svcs.streaming = append(svcs.streaming, service)
+		if err := service.Stream(wg); err != nil {
+			return fail(err)
+		}
+		svcs.indexer = sqlIndexer
+	}
+
+	// the history of the transfers is written on commit
+	if cast.ToBool(appOpts.Get(history.OptEnable)) {
+		historyStore, service, err := history.NewServiceFromOptions(appOpts)
+		if err != nil {
+			return fail(err)
+		}
+		bApp.SetStreamingService(service)
+		svcs.streaming = append(svcs.streaming, service)
+		svcs.history = history.NewQueryServer(historyStore)
+	}
+
+	return svcs, nil
+}
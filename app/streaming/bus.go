@@ -0,0 +1,288 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/spf13/cast"
+
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+// DefaultBufferBlocks is the default number of blocks buffered for a sink
+const DefaultBufferBlocks = 100
+
+// DefaultSendTimeout is the default timeout of sending a block to a sink
+const DefaultSendTimeout = 10 * time.Second
+
+// optional app.toml options of the streaming services of a message bus
+const (
+	optBufferBlocksFormat = "streamers.%s.buffer_blocks"
+	optSendTimeoutFormat  = "streamers.%s.send_timeout"
+)
+
+const (
+	minRetryInterval = 100 * time.Millisecond
+	maxRetryInterval = 10 * time.Second
+)
+
+// Sink sends the blocks to a message bus. A block is the records of the block
+// closed by the commit record, which DecodeBlock decodes.
+//
+// The errors of Send are retried unless marked by Fatal.
+type Sink interface {
+	Send(ctx context.Context, height int64, block []byte) error
+	Close() error
+}
+
+// fatalError is an error of sending a block not to be retried
+type fatalError struct {
+	err error
+}
+
+func (e fatalError) Error() string {
+	return e.err.Error()
+}
+
+func (e fatalError) Unwrap() error {
+	return e.err
+}
+
+// Fatal marks the error of a Sink sending a block as fatal, e.g. the block
+// rejected by the message bus, so that the block is skipped rather than
+// retried.
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fatalError{err: err}
+}
+
+// IsFatal returns whether the error is marked by Fatal.
+func IsFatal(err error) bool {
+	var fatal fatalError
+	return errors.As(err, &fatal)
+}
+
+var _ Service = (*BusStreamingService)(nil)
+
+// BusStreamingService sends the blocks to a message bus through a Sink.
+//
+// The blocks are sent in the order of their heights by a goroutine, which
+// retries a block until it is sent, logging the errors. ListenCommit blocks
+// once the buffer of the blocks is full, so that a sink down for long stalls
+// the node rather than lose the blocks. A block failing by a fatal error is
+// skipped instead, which ListenCommit returns as the failure to stream it.
+// The blocks replayed on restart are sent again, so the consumers have to
+// skip the heights they have seen.
+type BusStreamingService struct {
+	*blockRecorder
+
+	sink    Sink
+	timeout time.Duration
+	logger  log.Logger
+
+	blocks   chan busBlock
+	done     chan struct{} // closed on Close
+	doneOnce sync.Once
+	// abandon is closed the timeout after Close, giving up the blocks not sent
+	abandon chan struct{}
+	// closeMtx guards blocks from being sent to once closed
+	closeMtx sync.RWMutex
+	closed   bool
+	started  bool
+
+	errMtx  sync.Mutex
+	lastErr error
+}
+
+type busBlock struct {
+	height int64
+	block  []byte
+}
+
+// NewBusStreamingService returns a streaming service sending the blocks to
+// the sink, buffering bufferBlocks blocks at most, with the timeout to send a
+// block.
+func NewBusStreamingService(sink Sink, bufferBlocks int, timeout time.Duration, keys []storetypes.StoreKey) (*BusStreamingService, error) {
+	if bufferBlocks < 0 {
+		return nil, errors.New("negative number of blocks to buffer")
+	}
+	if timeout <= 0 {
+		return nil, errors.New("non-positive timeout to send a block")
+	}
+
+	return &BusStreamingService{
+		blockRecorder: newBlockRecorder(keys),
+		sink:          sink,
+		timeout:       timeout,
+		logger:        log.NewNopLogger(),
+		blocks:        make(chan busBlock, bufferBlocks),
+		done:          make(chan struct{}),
+		abandon:       make(chan struct{}),
+	}, nil
+}
+
+// newBusStreamingServiceFromOptions returns the streaming service of the name
+// sending the blocks to the sink, under the options of the buffer and the
+// timeout of the service.
+func newBusStreamingServiceFromOptions(opts servertypes.AppOptions, name string, sink Sink, keys []storetypes.StoreKey) (Service, error) {
+	bufferBlocks := DefaultBufferBlocks
+	if opt := opts.Get(fmt.Sprintf(optBufferBlocksFormat, name)); opt != nil {
+		bufferBlocks = cast.ToInt(opt)
+	}
+	timeout := DefaultSendTimeout
+	if opt := opts.Get(fmt.Sprintf(optSendTimeoutFormat, name)); opt != nil {
+		timeout = cast.ToDuration(opt)
+	}
+
+	bss, err := NewBusStreamingService(sink, bufferBlocks, timeout, keys)
+	if err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+	return bss, nil
+}
+
+// SetLogger sets the logger of the errors retried, which must be called before
+// Stream.
+func (bss *BusStreamingService) SetLogger(logger log.Logger) {
+	bss.logger = logger
+}
+
+// ListenCommit satisfies the Service interface. It queues the block to send,
+// and returns the fatal error of a former block skipped if any.
+func (bss *BusStreamingService) ListenCommit() error {
+	bss.mtx.Lock()
+	height, block := bss.takeBlock()
+	if height != 0 {
+		block = append([]byte(nil), block...)
+	}
+	bss.mtx.Unlock()
+
+	bss.closeMtx.RLock()
+	defer bss.closeMtx.RUnlock()
+
+	if bss.closed {
+		return errors.New("streaming service closed")
+	}
+	if height != 0 {
+		select {
+		case bss.blocks <- busBlock{height: height, block: block}:
+		case <-bss.done:
+			return errors.New("streaming service closed")
+		}
+	}
+
+	return bss.takeErr()
+}
+
+// Stream satisfies the baseapp.StreamingService interface. It spins up the
+// goroutine sending the blocks, which closes the sink once the service is
+// closed and the buffered blocks are sent.
+func (bss *BusStreamingService) Stream(wg *sync.WaitGroup) error {
+	bss.closeMtx.Lock()
+	defer bss.closeMtx.Unlock()
+
+	if bss.closed {
+		return errors.New("streaming service closed")
+	}
+	if bss.started {
+		return errors.New("streaming service already started")
+	}
+	bss.started = true
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for b := range bss.blocks {
+			if !bss.send(b) {
+				// closed with the sink down, dropping the rest
+				break
+			}
+		}
+		bss.setErr(bss.sink.Close())
+	}()
+
+	return nil
+}
+
+// send sends the block, retrying it until the blocks are abandoned after the
+// service is closed, or skipping it on a fatal error. It returns false once
+// the blocks are abandoned.
+func (bss *BusStreamingService) send(b busBlock) bool {
+	interval := minRetryInterval
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), bss.timeout)
+		err := bss.sink.Send(ctx, b.height, b.block)
+		cancel()
+		if err == nil {
+			return true
+		}
+		if IsFatal(err) {
+			bss.setErr(fmt.Errorf("failed to send block %d, skipped: %w", b.height, err))
+			return true
+		}
+		bss.logger.Error("failed to send the block, retrying", "height", b.height, "err", err)
+
+		select {
+		case <-bss.abandon:
+			bss.logger.Error("abandoned the blocks not sent", "height", b.height)
+			return false
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
+
+// Close satisfies the io.Closer interface. The buffered blocks are sent in
+// the background, which the WaitGroup of Stream awaits, and abandoned if the
+// sink is still down after the timeout.
+func (bss *BusStreamingService) Close() error {
+	// unblock ListenCommit waiting for the buffer before taking the lock
+	bss.doneOnce.Do(func() {
+		close(bss.done)
+		time.AfterFunc(bss.timeout, func() { close(bss.abandon) })
+	})
+
+	bss.closeMtx.Lock()
+	defer bss.closeMtx.Unlock()
+
+	if bss.closed {
+		return nil
+	}
+	bss.closed = true
+	close(bss.blocks)
+
+	if !bss.started {
+		return bss.sink.Close()
+	}
+	return nil
+}
+
+func (bss *BusStreamingService) setErr(err error) {
+	if err == nil {
+		return
+	}
+
+	bss.errMtx.Lock()
+	defer bss.errMtx.Unlock()
+	bss.lastErr = err
+}
+
+func (bss *BusStreamingService) takeErr() error {
+	bss.errMtx.Lock()
+	defer bss.errMtx.Unlock()
+
+	err := bss.lastErr
+	bss.lastErr = nil
+	return err
+}
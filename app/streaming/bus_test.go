@@ -0,0 +1,465 @@
+package streaming
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+type testOptions map[string]interface{}
+
+func (o testOptions) Get(key string) interface{} {
+	return o[key]
+}
+
+// requireBlocks requires the blocks streamed by streamBlock from height 1.
+func requireBlocks(t *testing.T, blocks [][]byte) {
+	require.Len(t, blocks, 3)
+	for i, bz := range blocks {
+		block, err := DecodeBlock(bz)
+		require.NoError(t, err)
+		height := int64(i + 1)
+		require.Equal(t, height, block.Height)
+		require.Len(t, block.DeliverTxs, 1)
+		require.Equal(t, []storetypes.StoreKVPair{{
+			StoreKey: testKey.Name(),
+			Key:      []byte{byte(height)},
+			Value:    []byte("value"),
+		}}, block.ChangeSet)
+	}
+}
+
+func streamBlocks(t *testing.T, opts testOptions) {
+	services, wg, err := loadServices(opts)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+
+	// the failed attempts are retried, but not reported
+	for height := int64(1); height <= 3; height++ {
+		require.NoError(t, streamBlock(t, services[0], height))
+	}
+	require.NoError(t, services[0].Close())
+	wg.Wait()
+}
+
+// loadServices loads the services of the options exposing testKey.
+func loadServices(opts testOptions) ([]Service, *sync.WaitGroup, error) {
+	wg := new(sync.WaitGroup)
+	var services []Service
+	for _, name := range opts[OptStreamers].([]string) {
+		service, err := serviceConstructors[name](opts, []storetypes.StoreKey{testKey})
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := service.Stream(wg); err != nil {
+			return nil, nil, err
+		}
+		services = append(services, service)
+	}
+	return services, wg, nil
+}
+
+// fakeKafkaBroker is the single broker leading the partitions of a topic up
+// to the given one, which accepts the Produce requests, failing the first
+// ones as the partition lacked the in-sync replicas.
+type fakeKafkaBroker struct {
+	t         *testing.T
+	listener  net.Listener
+	topic     string
+	partition int32
+
+	mtx      sync.Mutex
+	failures int
+	maxSize  int // of the values if positive
+	records  map[string][]byte
+	keys     []string
+}
+
+func newFakeKafkaBroker(t *testing.T, topic string, partition int32, failures int) *fakeKafkaBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	b := &fakeKafkaBroker{
+		t:         t,
+		listener:  listener,
+		topic:     topic,
+		partition: partition,
+		failures:  failures,
+		records:   map[string][]byte{},
+	}
+	go b.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return b
+}
+
+func (b *fakeKafkaBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeKafkaBroker) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var size int32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(conn, frame); err != nil {
+			return
+		}
+		res, ok := b.respond(frame)
+		if !ok {
+			return
+		}
+		if _, err := conn.Write(res); err != nil {
+			return
+		}
+	}
+}
+
+// respond decodes the request of the frame, and returns the response frame.
+func (b *fakeKafkaBroker) respond(frame []byte) ([]byte, bool) {
+	if len(frame) < 10 {
+		b.t.Errorf("invalid request header")
+		return nil, false
+	}
+	key := int16(binary.BigEndian.Uint16(frame))
+	version := int16(binary.BigEndian.Uint16(frame[2:]))
+	correlationID := frame[4:8]
+	body := frame[10+int(int16(binary.BigEndian.Uint16(frame[8:]))):] // after the client id
+
+	req := kmsg.RequestForKey(key)
+	if req == nil {
+		b.t.Errorf("unexpected request %d", key)
+		return nil, false
+	}
+	req.SetVersion(version)
+	if req.IsFlexible() {
+		body = body[1:] // no tagged fields
+	}
+	if err := req.ReadFrom(body); err != nil {
+		b.t.Errorf("invalid request %d of version %d: %v", key, version, err)
+		return nil, false
+	}
+
+	var res kmsg.Response
+	switch req := req.(type) {
+	case *kmsg.ApiVersionsRequest:
+		res = b.apiVersions(req)
+	case *kmsg.MetadataRequest:
+		res = b.metadata(req)
+	case *kmsg.ProduceRequest:
+		res = b.produce(req)
+	default:
+		b.t.Errorf("unexpected request %d", key)
+		return nil, false
+	}
+
+	buf := append([]byte{0, 0, 0, 0}, correlationID...)
+	if res.IsFlexible() && key != kmsg.ApiVersions.Int16() {
+		buf = append(buf, 0) // no tagged fields
+	}
+	buf = res.AppendTo(buf)
+	binary.BigEndian.PutUint32(buf, uint32(len(buf)-4))
+
+	return buf, true
+}
+
+func (b *fakeKafkaBroker) apiVersions(req *kmsg.ApiVersionsRequest) kmsg.Response {
+	res := kmsg.NewPtrApiVersionsResponse()
+	res.SetVersion(req.Version)
+	for _, key := range []kmsg.Key{kmsg.Produce, kmsg.Metadata, kmsg.ApiVersions} {
+		apiKey := kmsg.NewApiVersionsResponseApiKey()
+		apiKey.ApiKey = key.Int16()
+		apiKey.MaxVersion = kmsg.RequestForKey(key.Int16()).MaxVersion()
+		res.ApiKeys = append(res.ApiKeys, apiKey)
+	}
+	return res
+}
+
+func (b *fakeKafkaBroker) metadata(req *kmsg.MetadataRequest) kmsg.Response {
+	host, port, err := net.SplitHostPort(b.listener.Addr().String())
+	require.NoError(b.t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(b.t, err)
+
+	res := kmsg.NewPtrMetadataResponse()
+	res.SetVersion(req.Version)
+	broker := kmsg.NewMetadataResponseBroker()
+	broker.Host, broker.Port = host, int32(portNum)
+	res.Brokers = append(res.Brokers, broker)
+
+	for _, reqTopic := range req.Topics {
+		topic := kmsg.NewMetadataResponseTopic()
+		topic.Topic = reqTopic.Topic
+		if reqTopic.Topic == nil || *reqTopic.Topic != b.topic {
+			topic.ErrorCode = kerr.UnknownTopicOrPartition.Code
+		} else {
+			for index := int32(0); index <= b.partition; index++ {
+				partition := kmsg.NewMetadataResponseTopicPartition()
+				partition.Partition = index
+				partition.Replicas = []int32{0}
+				partition.ISR = []int32{0}
+				topic.Partitions = append(topic.Partitions, partition)
+			}
+		}
+		res.Topics = append(res.Topics, topic)
+	}
+	return res
+}
+
+func (b *fakeKafkaBroker) produce(req *kmsg.ProduceRequest) kmsg.Response {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	res := kmsg.NewPtrProduceResponse()
+	res.SetVersion(req.Version)
+	for _, reqTopic := range req.Topics {
+		topic := kmsg.NewProduceResponseTopic()
+		topic.Topic = reqTopic.Topic
+		for _, reqPartition := range reqTopic.Partitions {
+			partition := kmsg.NewProduceResponseTopicPartition()
+			partition.Partition = reqPartition.Partition
+
+			var batch kmsg.RecordBatch
+			require.NoError(b.t, batch.ReadFrom(reqPartition.Records))
+			require.Equal(b.t, int32(1), batch.NumRecords)
+			var record kmsg.Record
+			require.NoError(b.t, record.ReadFrom(batch.Records))
+
+			switch {
+			case reqTopic.Topic != b.topic || reqPartition.Partition > b.partition:
+				partition.ErrorCode = kerr.UnknownTopicOrPartition.Code
+			case b.maxSize > 0 && len(record.Value) > b.maxSize:
+				partition.ErrorCode = kerr.MessageTooLarge.Code
+			case b.failures > 0:
+				b.failures--
+				partition.ErrorCode = kerr.NotEnoughReplicas.Code
+			default:
+				key := string(record.Key)
+				if _, ok := b.records[key]; !ok {
+					b.keys = append(b.keys, key)
+				}
+				b.records[key] = record.Value
+			}
+			topic.Partitions = append(topic.Partitions, partition)
+		}
+		res.Topics = append(res.Topics, topic)
+	}
+	return res
+}
+
+func (b *fakeKafkaBroker) blocks() [][]byte {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	blocks := make([][]byte, 0, len(b.keys))
+	for _, key := range b.keys {
+		blocks = append(blocks, b.records[key])
+	}
+	return blocks
+}
+
+func TestKafkaStreamingService(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "blocks", 2, 0)
+
+	streamBlocks(t, testOptions{
+		OptStreamers:      []string{KafkaServiceName},
+		OptKafkaBrokers:   []string{broker.listener.Addr().String()},
+		OptKafkaTopic:     "blocks",
+		OptKafkaPartition: 2,
+	})
+
+	require.Equal(t, []string{"1", "2", "3"}, broker.keys)
+	requireBlocks(t, broker.blocks())
+}
+
+func TestKafkaSinkRetry(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "blocks", 0, 2)
+
+	// the metadata is refreshed on every retry
+	sink, err := NewKafkaSink([]string{broker.listener.Addr().String()}, "blocks", 0, DefaultKafkaClientID, kgo.MetadataMinAge(10*time.Millisecond))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Send(context.Background(), 1, []byte("block")))
+	require.Equal(t, [][]byte{[]byte("block")}, broker.blocks())
+}
+
+func TestKafkaSinkMessageTooLarge(t *testing.T) {
+	broker := newFakeKafkaBroker(t, "blocks", 0, 0)
+	broker.maxSize = 4
+
+	sink, err := NewKafkaSink([]string{broker.listener.Addr().String()}, "blocks", 0, DefaultKafkaClientID)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Send(context.Background(), 1, []byte("block"))
+	require.ErrorIs(t, err, kerr.MessageTooLarge)
+	require.True(t, IsFatal(err))
+}
+
+// fakeSink fails the blocks by the errors of their heights.
+type fakeSink struct {
+	mtx    sync.Mutex
+	errs   map[int64][]error
+	blocks []int64
+}
+
+func (s *fakeSink) Send(_ context.Context, height int64, _ []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if errs := s.errs[height]; len(errs) > 0 {
+		s.errs[height] = errs[1:]
+		return errs[0]
+	}
+	s.blocks = append(s.blocks, height)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	return nil
+}
+
+func TestBusStreamingServiceFatalError(t *testing.T) {
+	sink := &fakeSink{errs: map[int64][]error{
+		1: {errors.New("unavailable")},
+		2: {Fatal(errors.New("rejected"))},
+	}}
+	bss, err := NewBusStreamingService(sink, 0, DefaultSendTimeout, []storetypes.StoreKey{testKey})
+	require.NoError(t, err)
+	wg := new(sync.WaitGroup)
+	require.NoError(t, bss.Stream(wg))
+
+	// the failed attempts of the first block are retried, but not reported
+	require.NoError(t, streamBlock(t, bss, 1))
+
+	// the unbuffered blocks are taken once the former ones are sent or
+	// skipped, so the skipped block is reported on the next block at the latest
+	var errs []error
+	for height := int64(2); height <= 3; height++ {
+		if err := streamBlock(t, bss, height); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "failed to send block 2, skipped: rejected")
+	require.True(t, IsFatal(errs[0]))
+	require.NoError(t, bss.Close())
+	wg.Wait()
+
+	require.Equal(t, []int64{1, 3}, sink.blocks)
+}
+
+type fakeSinkServer struct {
+	err error // of every block if set
+
+	mtx     sync.Mutex
+	blocks  [][]byte
+	heights []int64
+}
+
+func (s *fakeSinkServer) Send(_ context.Context, req *SendRequest) (*SendResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.blocks = append(s.blocks, req.Block)
+	s.heights = append(s.heights, req.Height)
+	return &SendResponse{}, nil
+}
+
+func TestGRPCStreamingService(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	sinkServer := &fakeSinkServer{}
+	RegisterSinkServer(server, sinkServer)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	streamBlocks(t, testOptions{
+		OptStreamers:   []string{GRPCServiceName},
+		OptGRPCAddress: listener.Addr().String(),
+	})
+
+	requireBlocks(t, sinkServer.blocks)
+	require.Equal(t, []int64{1, 2, 3}, sinkServer.heights)
+}
+
+func TestGRPCSinkInvalidArgument(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	RegisterSinkServer(server, &fakeSinkServer{err: status.Error(codes.InvalidArgument, "invalid block")})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	sink, err := NewGRPCSink(listener.Addr().String(), false)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Send(context.Background(), 1, []byte("block"))
+	require.ErrorContains(t, err, "invalid block")
+	require.True(t, IsFatal(err))
+}
+
+func TestBusStreamingServiceClosedWithSinkDown(t *testing.T) {
+	// nothing listens on the address
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	services, wg, err := loadServices(testOptions{
+		OptStreamers:                    []string{KafkaServiceName},
+		OptKafkaBrokers:                 []string{address},
+		OptKafkaTopic:                   "blocks",
+		"streamers.kafka.buffer_blocks": 1,
+		"streamers.kafka.send_timeout":  "100ms",
+	})
+	require.NoError(t, err)
+
+	// the first block is retried, and the second one fills the buffer
+	for height := int64(1); height <= 2; height++ {
+		_ = streamBlock(t, services[0], height)
+	}
+
+	// the third block waits for the buffer until closed
+	errCh := make(chan error)
+	go func() {
+		errCh <- streamBlock(t, services[0], 3)
+	}()
+
+	require.NoError(t, services[0].Close())
+	wg.Wait()
+	require.ErrorContains(t, <-errCh, "closed")
+}
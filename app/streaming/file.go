@@ -0,0 +1,322 @@
+package streaming
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cast"
+
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+// FileServiceName is the name of the file streaming service in OptStreamers
+const FileServiceName = "file"
+
+// app.toml options of the file streaming service
+const (
+	OptFileWriteDir     = "streamers.file.write_dir"
+	OptFilePrefix       = "streamers.file.prefix"
+	OptFileRotateBlocks = "streamers.file.rotate_blocks"
+	OptFileFsync        = "streamers.file.fsync"
+)
+
+// DefaultRotateBlocks is the default number of blocks a file holds
+const DefaultRotateBlocks = 1000
+
+// fileSuffix is the suffix of the files written by the file streaming service
+const fileSuffix = ".stream"
+
+var _ Service = (*FileStreamingService)(nil)
+
+// FileStreamingService writes the records of the blocks into the files of
+// the write directory. A file holds the blocks of the heights from the one in
+// its name, up to the number of blocks to rotate the files by.
+//
+// The records of a block are written at once on ListenCommit, followed by a
+// commit record, so that a block is either complete or discarded by the
+// reader. The incomplete records of a crash are truncated on the next start.
+type FileStreamingService struct {
+	*blockRecorder
+
+	writeDir     string
+	prefix       string
+	rotateBlocks int64
+	fsync        bool
+
+	// guarded by the mutex of the blockRecorder
+	lastHeight int64 // height of the last block written
+	file       *os.File
+	fileStart  int64 // first height of the open file
+	closed     bool
+}
+
+// NewFileStreamingServiceFromOptions is the ServiceConstructor of the file
+// streaming service.
+func NewFileStreamingServiceFromOptions(opts servertypes.AppOptions, keys []storetypes.StoreKey) (Service, error) {
+	rotateBlocks := int64(DefaultRotateBlocks)
+	if opt := opts.Get(OptFileRotateBlocks); opt != nil {
+		rotateBlocks = cast.ToInt64(opt)
+	}
+
+	return NewFileStreamingService(
+		cast.ToString(opts.Get(OptFileWriteDir)),
+		cast.ToString(opts.Get(OptFilePrefix)),
+		rotateBlocks,
+		cast.ToBool(opts.Get(OptFileFsync)),
+		keys,
+	)
+}
+
+// NewFileStreamingService returns a file streaming service writing into
+// writeDir the files named with the optional prefix. The files are rotated
+// every rotateBlocks blocks, or never if it is 0, and synced to the disk
+// on every block if fsync is set, which LinkApp does before the commit of the
+// block.
+func NewFileStreamingService(writeDir, prefix string, rotateBlocks int64, fsync bool, keys []storetypes.StoreKey) (*FileStreamingService, error) {
+	if writeDir == "" {
+		return nil, errors.New("write directory not specified")
+	}
+	if rotateBlocks < 0 {
+		return nil, fmt.Errorf("negative number of blocks to rotate the files by: %d", rotateBlocks)
+	}
+	if err := os.MkdirAll(writeDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	fss := &FileStreamingService{
+		blockRecorder: newBlockRecorder(keys),
+		writeDir:      writeDir,
+		prefix:        prefix,
+		rotateBlocks:  rotateBlocks,
+		fsync:         fsync,
+	}
+
+	if err := fss.recover(); err != nil {
+		return nil, err
+	}
+
+	return fss, nil
+}
+
+// ListenCommit satisfies the Service interface. It writes the records of the
+// block into the file of its height, and syncs the file if configured to.
+func (fss *FileStreamingService) ListenCommit() error {
+	fss.mtx.Lock()
+	defer fss.mtx.Unlock()
+
+	height, block := fss.takeBlock()
+	if fss.closed {
+		return errors.New("streaming service closed")
+	}
+	if height == 0 || height <= fss.lastHeight {
+		// no block, or a block replayed on start which has been written already
+		return nil
+	}
+
+	if err := fss.openFile(height); err != nil {
+		return err
+	}
+	if _, err := fss.file.Write(block); err != nil {
+		return err
+	}
+	if fss.fsync {
+		if err := fss.file.Sync(); err != nil {
+			return err
+		}
+	}
+	fss.lastHeight = height
+
+	return nil
+}
+
+// Stream satisfies the baseapp.StreamingService interface. The service writes
+// the blocks synchronously, so it has no loop to run.
+func (fss *FileStreamingService) Stream(_ *sync.WaitGroup) error {
+	return nil
+}
+
+// Close satisfies the io.Closer interface. It syncs and closes the open file.
+func (fss *FileStreamingService) Close() error {
+	fss.mtx.Lock()
+	defer fss.mtx.Unlock()
+
+	if fss.closed {
+		return nil
+	}
+	fss.closed = true
+
+	return fss.closeFile()
+}
+
+// openFile opens the file the block of the height is written into, rotating
+// the files if needed.
+func (fss *FileStreamingService) openFile(height int64) error {
+	start := fss.fileStart
+	switch {
+	case fss.rotateBlocks > 0:
+		start = (height-1)/fss.rotateBlocks*fss.rotateBlocks + 1
+	case fss.file == nil:
+		start = height
+	}
+	if fss.file != nil && start == fss.fileStart {
+		return nil
+	}
+
+	if err := fss.closeFile(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(fss.filePath(start), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	fss.file = file
+	fss.fileStart = start
+
+	return nil
+}
+
+func (fss *FileStreamingService) closeFile() error {
+	if fss.file == nil {
+		return nil
+	}
+
+	file := fss.file
+	fss.file = nil
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// recover finds the last block written into the files, and truncates the
+// incomplete records after it. The file is kept open to append the next
+// blocks to if the files are not rotated.
+func (fss *FileStreamingService) recover() error {
+	files, err := listFiles(fss.writeDir, fss.prefix)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	last := files[len(files)-1]
+	f, err := os.Open(last.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	offset, height, err := scanCommits(f)
+	if err != nil {
+		return fmt.Errorf("failed to recover %s: %w", last.path, err)
+	}
+	if err := os.Truncate(last.path, offset); err != nil {
+		return err
+	}
+	if height == 0 && len(files) > 1 {
+		// the last file has no complete block
+		f, err := os.Open(files[len(files)-2].path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if _, height, err = scanCommits(f); err != nil {
+			return err
+		}
+	}
+	fss.lastHeight = height
+
+	if fss.rotateBlocks == 0 {
+		file, err := os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		fss.file = file
+		fss.fileStart = last.start
+	}
+
+	return nil
+}
+
+func (fss *FileStreamingService) filePath(start int64) string {
+	return filepath.Join(fss.writeDir, fileName(fss.prefix, start))
+}
+
+// scanCommits returns the offset right after the last commit record of r
+// along with its height.
+func scanCommits(r io.Reader) (int64, int64, error) {
+	br := bufio.NewReader(r)
+
+	var offset, committed, height int64
+	for {
+		kind, payload, err := readRecord(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return committed, height, nil
+			}
+			return 0, 0, err
+		}
+
+		offset += recordSize(payload)
+		if kind == kindCommit {
+			if height, err = commitHeight(payload); err != nil {
+				return 0, 0, err
+			}
+			committed = offset
+		}
+	}
+}
+
+// streamFile is a file written by the file streaming service
+type streamFile struct {
+	path  string
+	start int64
+}
+
+// fileName returns the name of the file holding the blocks from start. The
+// height is padded so that the names sort by height.
+func fileName(prefix string, start int64) string {
+	name := fmt.Sprintf("blocks-%012d%s", start, fileSuffix)
+	if prefix != "" {
+		name = fmt.Sprintf("%s-%s", prefix, name)
+	}
+	return name
+}
+
+// listFiles returns the files of the prefix in dir, sorted by their first height.
+func listFiles(dir, prefix string) ([]streamFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	namePrefix := "blocks-"
+	if prefix != "" {
+		namePrefix = prefix + "-" + namePrefix
+	}
+
+	var files []streamFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, namePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+
+		var start int64
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, namePrefix), fileSuffix), "%d", &start); err != nil {
+			continue
+		}
+		files = append(files, streamFile{path: filepath.Join(dir, name), start: start})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].start < files[j].start })
+
+	return files, nil
+}
@@ -0,0 +1,135 @@
+package streaming
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+var testKey = sdk.NewKVStoreKey("test")
+
+func newTestService(t *testing.T, dir string, rotateBlocks int64) *FileStreamingService {
+	fss, err := NewFileStreamingService(dir, "test", rotateBlocks, true, []storetypes.StoreKey{testKey})
+	require.NoError(t, err)
+	return fss
+}
+
+// streamBlock streams a block of the height with a tx, which writes the key
+// of the height on commit. It returns the error of ListenCommit.
+func streamBlock(t *testing.T, fss Service, height int64) error {
+	ctx := sdk.Context{}
+	require.NoError(t, fss.ListenBeginBlock(ctx, ocabci.RequestBeginBlock{Header: tmproto.Header{Height: height}}, abci.ResponseBeginBlock{}))
+	require.NoError(t, fss.ListenDeliverTx(ctx, abci.RequestDeliverTx{Tx: []byte{byte(height)}}, abci.ResponseDeliverTx{Code: 1}))
+	require.NoError(t, fss.ListenEndBlock(ctx, abci.RequestEndBlock{Height: height}, abci.ResponseEndBlock{}))
+
+	// writes out of the commit are not recorded
+	listener := fss.Listeners()[testKey][0]
+	require.NoError(t, listener.OnWrite(testKey, []byte("ignored"), nil, true))
+
+	fss.ListenStartCommit()
+	require.NoError(t, listener.OnWrite(testKey, []byte{byte(height)}, []byte("value"), false))
+	return fss.ListenCommit()
+}
+
+func replay(t *testing.T, dir string, from int64) []Block {
+	var blocks []Block
+	require.NoError(t, Replay(dir, "test", from, func(block Block) error {
+		blocks = append(blocks, block)
+		return nil
+	}))
+	return blocks
+}
+
+func TestFileStreamingService(t *testing.T) {
+	dir := t.TempDir()
+	fss := newTestService(t, dir, 2)
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, streamBlock(t, fss, height))
+	}
+	require.NoError(t, fss.Close())
+
+	files, err := listFiles(dir, "test")
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for i, file := range files {
+		require.Equal(t, int64(2*i+1), file.start)
+	}
+
+	blocks := replay(t, dir, 0)
+	require.Len(t, blocks, 5)
+	for i, block := range blocks {
+		height := int64(i + 1)
+		require.Equal(t, height, block.Height)
+		require.Equal(t, height, block.RequestBeginBlock.Header.Height)
+		require.Equal(t, height, block.RequestEndBlock.Height)
+		require.Len(t, block.DeliverTxs, 1)
+		require.Equal(t, []byte{byte(height)}, block.DeliverTxs[0].Request.Tx)
+		require.Equal(t, uint32(1), block.DeliverTxs[0].Response.Code)
+		require.Equal(t, []storetypes.StoreKVPair{{
+			StoreKey: testKey.Name(),
+			Key:      []byte{byte(height)},
+			Value:    []byte("value"),
+		}}, block.ChangeSet)
+	}
+
+	blocks = replay(t, dir, 4)
+	require.Len(t, blocks, 2)
+	require.Equal(t, int64(4), blocks[0].Height)
+}
+
+func TestFileStreamingServiceRecover(t *testing.T) {
+	for name, rotateBlocks := range map[string]int64{
+		"rotated":     2,
+		"single file": 0,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			fss := newTestService(t, dir, rotateBlocks)
+			for height := int64(1); height <= 3; height++ {
+				require.NoError(t, streamBlock(t, fss, height))
+			}
+			require.NoError(t, fss.Close())
+
+			// a crash in the middle of writing a block
+			files, err := listFiles(dir, "test")
+			require.NoError(t, err)
+			last := files[len(files)-1].path
+			f, err := os.OpenFile(last, os.O_WRONLY|os.O_APPEND, 0o600)
+			require.NoError(t, err)
+			_, err = f.Write([]byte{byte(kindRequestBeginBlock), 10, 1, 2})
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			require.Len(t, replay(t, dir, 0), 3)
+
+			// the block of height 3 is replayed on restart
+			fss = newTestService(t, dir, rotateBlocks)
+			require.Equal(t, int64(3), fss.lastHeight)
+			for height := int64(3); height <= 4; height++ {
+				require.NoError(t, streamBlock(t, fss, height))
+			}
+			require.NoError(t, fss.Close())
+
+			blocks := replay(t, dir, 0)
+			require.Len(t, blocks, 4)
+			for i, block := range blocks {
+				require.Equal(t, int64(i+1), block.Height)
+			}
+		})
+	}
+}
+
+func TestExposedStoreKeys(t *testing.T) {
+	keys := sdk.NewKVStoreKeys("a", "b", "c")
+
+	require.Equal(t, []storetypes.StoreKey{keys["a"], keys["b"], keys["c"]}, exposedStoreKeys([]string{"*"}, keys))
+	require.Equal(t, []storetypes.StoreKey{keys["a"], keys["c"]}, exposedStoreKeys([]string{"c", "unknown", "a"}, keys))
+	require.Empty(t, exposedStoreKeys(nil, keys))
+}
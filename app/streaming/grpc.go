@@ -0,0 +1,81 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+// GRPCServiceName is the name of the gRPC streaming service in OptStreamers
+const GRPCServiceName = "grpc"
+
+// app.toml options of the gRPC streaming service
+const (
+	OptGRPCAddress = "streamers.grpc.address"
+	OptGRPCTLS     = "streamers.grpc.tls"
+)
+
+// NewGRPCStreamingServiceFromOptions is the ServiceConstructor of the gRPC
+// streaming service.
+func NewGRPCStreamingServiceFromOptions(opts servertypes.AppOptions, keys []storetypes.StoreKey) (Service, error) {
+	sink, err := NewGRPCSink(cast.ToString(opts.Get(OptGRPCAddress)), cast.ToBool(opts.Get(OptGRPCTLS)))
+	if err != nil {
+		return nil, err
+	}
+
+	return newBusStreamingServiceFromOptions(opts, GRPCServiceName, sink, keys)
+}
+
+var _ Sink = (*GRPCSink)(nil)
+
+// GRPCSink sends the blocks to a SinkServer by the unary calls of
+// finschia.streaming.v1.Sink/Send.
+type GRPCSink struct {
+	conn   *grpc.ClientConn
+	client SinkClient
+}
+
+// NewGRPCSink returns a GRPCSink connecting to the address, with TLS of the
+// system certificates if tls is set. It connects in the background.
+func NewGRPCSink(address string, tls bool) (*GRPCSink, error) {
+	if address == "" {
+		return nil, errors.New("gRPC address not specified")
+	}
+
+	dialOpt := grpc.WithInsecure()
+	if tls {
+		dialOpt = grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, ""))
+	}
+	conn, err := grpc.Dial(address, dialOpt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", address, err)
+	}
+
+	return &GRPCSink{conn: conn, client: NewSinkClient(conn)}, nil
+}
+
+// Send satisfies the Sink interface. The errors of the codes rejecting the
+// block or the client for good are fatal.
+func (s *GRPCSink) Send(ctx context.Context, height int64, block []byte) error {
+	_, err := s.client.Send(ctx, &SendRequest{Block: block, Height: height})
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented,
+		codes.PermissionDenied, codes.Unauthenticated:
+		return Fatal(err)
+	}
+	return err
+}
+
+// Close satisfies the Sink interface
+func (s *GRPCSink) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,125 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cast"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+// KafkaServiceName is the name of the Kafka streaming service in OptStreamers
+const KafkaServiceName = "kafka"
+
+// app.toml options of the Kafka streaming service
+const (
+	OptKafkaBrokers   = "streamers.kafka.brokers"
+	OptKafkaTopic     = "streamers.kafka.topic"
+	OptKafkaPartition = "streamers.kafka.partition"
+	OptKafkaClientID  = "streamers.kafka.client_id"
+)
+
+// DefaultKafkaClientID is the default client id of the Kafka streaming service
+const DefaultKafkaClientID = "fnsad"
+
+// NewKafkaStreamingServiceFromOptions is the ServiceConstructor of the Kafka
+// streaming service.
+func NewKafkaStreamingServiceFromOptions(opts servertypes.AppOptions, keys []storetypes.StoreKey) (Service, error) {
+	clientID := DefaultKafkaClientID
+	if opt := opts.Get(OptKafkaClientID); opt != nil && cast.ToString(opt) != "" {
+		clientID = cast.ToString(opt)
+	}
+
+	sink, err := NewKafkaSink(
+		cast.ToStringSlice(opts.Get(OptKafkaBrokers)),
+		cast.ToString(opts.Get(OptKafkaTopic)),
+		cast.ToInt32(opts.Get(OptKafkaPartition)),
+		clientID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBusStreamingServiceFromOptions(opts, KafkaServiceName, sink, keys)
+}
+
+var _ Sink = (*KafkaSink)(nil)
+
+// KafkaSink produces the blocks to a partition of a Kafka topic, keyed by
+// their heights in decimal.
+//
+// The leader of the partition is discovered from the seed brokers, and the
+// records are produced uncompressed, waiting for the leader to acknowledge,
+// so the size of the blocks is limited by the message.max.bytes of the topic.
+// The errors Kafka does not retry, e.g. of a block too large, are fatal.
+type KafkaSink struct {
+	client    *kgo.Client
+	topic     string
+	partition int32
+}
+
+// NewKafkaSink returns a KafkaSink producing to the partition of the topic
+// through the seed brokers, with the extra options of the client, e.g. of TLS
+// or SASL. It connects on the first block.
+func NewKafkaSink(brokers []string, topic string, partition int32, clientID string, opts ...kgo.Opt) (*KafkaSink, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("kafka brokers not specified")
+	}
+	if topic == "" {
+		return nil, errors.New("kafka topic not specified")
+	}
+	if partition < 0 {
+		return nil, fmt.Errorf("negative kafka partition: %d", partition)
+	}
+
+	client, err := kgo.NewClient(append([]kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.ClientID(clientID),
+		kgo.DefaultProduceTopic(topic),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+		kgo.RequiredAcks(kgo.LeaderAck()),
+		kgo.DisableIdempotentWrite(),
+		kgo.ProducerBatchCompression(kgo.NoCompression()),
+	}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	return &KafkaSink{
+		client:    client,
+		topic:     topic,
+		partition: partition,
+	}, nil
+}
+
+// Send satisfies the Sink interface
+func (s *KafkaSink) Send(ctx context.Context, height int64, block []byte) error {
+	record := &kgo.Record{
+		Key:       []byte(strconv.FormatInt(height, 10)),
+		Value:     block,
+		Partition: s.partition,
+	}
+	err := s.client.ProduceSync(ctx, record).FirstErr()
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("failed to produce to %s-%d: %w", s.topic, s.partition, err)
+	var kafkaErr *kerr.Error
+	if errors.As(err, &kafkaErr) && !kafkaErr.Retriable {
+		return Fatal(err)
+	}
+	return err
+}
+
+// Close satisfies the Sink interface
+func (s *KafkaSink) Close() error {
+	s.client.Close()
+	return nil
+}
@@ -0,0 +1,155 @@
+package streaming
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+// Block is a block replayed from the files of the file streaming service.
+type Block struct {
+	Height             int64
+	RequestBeginBlock  ocabci.RequestBeginBlock
+	ResponseBeginBlock abci.ResponseBeginBlock
+	DeliverTxs         []DeliverTx
+	RequestEndBlock    abci.RequestEndBlock
+	ResponseEndBlock   abci.ResponseEndBlock
+
+	// ChangeSet is the state changes of the block in the exposed stores
+	ChangeSet []storetypes.StoreKVPair
+}
+
+// DeliverTx is a tx of a block along with its result.
+type DeliverTx struct {
+	Request  abci.RequestDeliverTx
+	Response abci.ResponseDeliverTx
+}
+
+// Replay calls fn with the blocks from the height of from, in the order of
+// their heights, which are read from the files written into dir by the file
+// streaming service with the prefix. The incomplete block at the tail of the
+// files is skipped, as well as the blocks written twice on restart.
+func Replay(dir, prefix string, from int64, fn func(Block) error) error {
+	files, err := listFiles(dir, prefix)
+	if err != nil {
+		return err
+	}
+
+	var last int64
+	for i, file := range files {
+		// skip the files ending before from
+		if i+1 < len(files) && files[i+1].start <= from {
+			continue
+		}
+
+		err := replayFile(file.path, func(block Block) error {
+			if block.Height < from || block.Height <= last {
+				return nil
+			}
+			last = block.Height
+			return fn(block)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", file.path, err)
+		}
+	}
+
+	return nil
+}
+
+// DecodeBlock decodes a block sent by the streaming services of a message bus,
+// which is the records of the block closed by the commit record.
+func DecodeBlock(bz []byte) (Block, error) {
+	var blocks []Block
+	err := readBlocks(bufio.NewReader(bytes.NewReader(bz)), func(block Block) error {
+		blocks = append(blocks, block)
+		return nil
+	})
+	if err != nil {
+		return Block{}, err
+	}
+	if len(blocks) != 1 {
+		return Block{}, fmt.Errorf("expected a block, got %d", len(blocks))
+	}
+
+	return blocks[0], nil
+}
+
+func replayFile(path string, fn func(Block) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return readBlocks(bufio.NewReader(f), fn)
+}
+
+// readBlocks calls fn with the blocks read from r, skipping the incomplete
+// block at its tail.
+func readBlocks(r *bufio.Reader, fn func(Block) error) error {
+	block := Block{}
+	for {
+		kind, payload, err := readRecord(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		if kind == kindCommit {
+			if block.Height, err = commitHeight(payload); err != nil {
+				return err
+			}
+			if err := fn(block); err != nil {
+				return err
+			}
+			block = Block{}
+			continue
+		}
+
+		if err := block.decode(kind, payload); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *Block) decode(kind recordKind, payload []byte) error {
+	var msg codec.ProtoMarshaler
+	switch kind {
+	case kindRequestBeginBlock:
+		msg = &b.RequestBeginBlock
+	case kindResponseBeginBlock:
+		msg = &b.ResponseBeginBlock
+	case kindRequestDeliverTx:
+		b.DeliverTxs = append(b.DeliverTxs, DeliverTx{})
+		msg = &b.DeliverTxs[len(b.DeliverTxs)-1].Request
+	case kindResponseDeliverTx:
+		if len(b.DeliverTxs) == 0 {
+			return errors.New("DeliverTx response without request")
+		}
+		msg = &b.DeliverTxs[len(b.DeliverTxs)-1].Response
+	case kindRequestEndBlock:
+		msg = &b.RequestEndBlock
+	case kindResponseEndBlock:
+		msg = &b.ResponseEndBlock
+	case kindStoreKVPair:
+		b.ChangeSet = append(b.ChangeSet, storetypes.StoreKVPair{})
+		msg = &b.ChangeSet[len(b.ChangeSet)-1]
+	default:
+		return fmt.Errorf("unknown record kind %d", kind)
+	}
+
+	return msg.Unmarshal(payload)
+}
@@ -0,0 +1,108 @@
+package streaming
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Finschia/finschia-sdk/codec"
+)
+
+// recordKind tells what a record of the stream holds
+type recordKind byte
+
+// kinds of the records. The records of a block are written in the order of
+// BeginBlock, DeliverTx for each tx, EndBlock, the state changes and Commit.
+const (
+	kindRequestBeginBlock recordKind = iota + 1
+	kindResponseBeginBlock
+	kindRequestDeliverTx
+	kindResponseDeliverTx
+	kindRequestEndBlock
+	kindResponseEndBlock
+	kindStoreKVPair
+	// kindCommit closes the records of a block, holding its height. The
+	// records of a block without it are discarded.
+	kindCommit
+)
+
+// maxRecordSize limits the size of a record to read, which protects the reader
+// from a corrupted length.
+const maxRecordSize = 1 << 30
+
+// appendRecord appends the record of the message to buf.
+//
+// A record consists of its kind in a byte, the length of the payload in an
+// unsigned varint and the protobuf encoded payload.
+func appendRecord(buf []byte, kind recordKind, msg codec.ProtoMarshaler) ([]byte, error) {
+	bz, err := msg.Marshal()
+	if err != nil {
+		return buf, err
+	}
+
+	return appendRawRecord(buf, kind, bz), nil
+}
+
+func appendRawRecord(buf []byte, kind recordKind, payload []byte) []byte {
+	buf = append(buf, byte(kind))
+	buf = appendUvarint(buf, uint64(len(payload)))
+	return append(buf, payload...)
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var bz [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(bz[:], x)
+	return append(buf, bz[:n]...)
+}
+
+// appendCommitRecord appends the record closing the block of the height to buf.
+func appendCommitRecord(buf []byte, height int64) []byte {
+	return appendRawRecord(buf, kindCommit, appendUvarint(nil, uint64(height)))
+}
+
+// readRecord reads a record from r. It returns io.EOF at the end of r, and
+// io.ErrUnexpectedEOF if the record is truncated.
+func readRecord(r *bufio.Reader) (recordKind, []byte, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	if size > maxRecordSize {
+		return 0, nil, fmt.Errorf("record of %d bytes exceeds the limit", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+
+	return recordKind(kind), payload, nil
+}
+
+// recordSize returns the size of the record of the payload.
+func recordSize(payload []byte) int64 {
+	return int64(1 + len(appendUvarint(nil, uint64(len(payload)))) + len(payload))
+}
+
+// commitHeight decodes the height of a commit record.
+func commitHeight(payload []byte) (int64, error) {
+	height, n := binary.Uvarint(payload)
+	if n <= 0 {
+		return 0, errors.New("invalid commit record")
+	}
+
+	return int64(height), nil
+}
@@ -0,0 +1,128 @@
+package streaming
+
+import (
+	"sync"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+// blockRecorder records the ABCI messages of a block and its state changes
+// flushed on commit, which the services embedding it take on ListenCommit.
+type blockRecorder struct {
+	listeners map[storetypes.StoreKey][]storetypes.WriteListener
+
+	mtx       sync.Mutex
+	capturing bool
+	height    int64  // height of the current block
+	buf       []byte // records of the current block
+}
+
+func newBlockRecorder(keys []storetypes.StoreKey) *blockRecorder {
+	rec := &blockRecorder{
+		listeners: make(map[storetypes.StoreKey][]storetypes.WriteListener, len(keys)),
+	}
+	for _, key := range keys {
+		rec.listeners[key] = []storetypes.WriteListener{rec}
+	}
+
+	return rec
+}
+
+// Listeners satisfies the baseapp.StreamingService interface
+func (rec *blockRecorder) Listeners() map[storetypes.StoreKey][]storetypes.WriteListener {
+	return rec.listeners
+}
+
+// OnWrite satisfies the WriteListener interface. Only the writes flushed from
+// the deliver state on commit are recorded.
+//
+// NOTE: the mempool is locked during the commit, but the writes of concurrent
+// tx simulations would be recorded.
+func (rec *blockRecorder) OnWrite(storeKey storetypes.StoreKey, key []byte, value []byte, delete bool) error {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+
+	if !rec.capturing {
+		return nil
+	}
+
+	var err error
+	rec.buf, err = appendRecord(rec.buf, kindStoreKVPair, &storetypes.StoreKVPair{
+		StoreKey: storeKey.Name(),
+		Delete:   delete,
+		Key:      key,
+		Value:    value,
+	})
+	return err
+}
+
+// ListenBeginBlock satisfies the baseapp.ABCIListener interface
+func (rec *blockRecorder) ListenBeginBlock(_ sdk.Context, req ocabci.RequestBeginBlock, res abci.ResponseBeginBlock) error {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+
+	// the records of a block not committed are discarded
+	rec.height = req.Header.Height
+	rec.buf = rec.buf[:0]
+	return rec.appendRecords(kindRequestBeginBlock, &req, kindResponseBeginBlock, &res)
+}
+
+// ListenDeliverTx satisfies the baseapp.ABCIListener interface
+func (rec *blockRecorder) ListenDeliverTx(_ sdk.Context, req abci.RequestDeliverTx, res abci.ResponseDeliverTx) error {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+
+	return rec.appendRecords(kindRequestDeliverTx, &req, kindResponseDeliverTx, &res)
+}
+
+// ListenEndBlock satisfies the baseapp.ABCIListener interface
+func (rec *blockRecorder) ListenEndBlock(_ sdk.Context, req abci.RequestEndBlock, res abci.ResponseEndBlock) error {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+
+	return rec.appendRecords(kindRequestEndBlock, &req, kindResponseEndBlock, &res)
+}
+
+func (rec *blockRecorder) appendRecords(reqKind recordKind, req codec.ProtoMarshaler, resKind recordKind, res codec.ProtoMarshaler) error {
+	var err error
+	if rec.buf, err = appendRecord(rec.buf, reqKind, req); err != nil {
+		return err
+	}
+	rec.buf, err = appendRecord(rec.buf, resKind, res)
+	return err
+}
+
+// ListenStartCommit satisfies the Service interface
+func (rec *blockRecorder) ListenStartCommit() {
+	rec.mtx.Lock()
+	defer rec.mtx.Unlock()
+
+	rec.capturing = true
+}
+
+// takeBlock stops capturing the state changes, and returns the height of the
+// block along with its records closed by the commit record. The height is 0
+// if no block has begun, e.g. on the commit of the genesis.
+//
+// The records are valid until the next block begins, and the caller must hold
+// the mutex.
+func (rec *blockRecorder) takeBlock() (int64, []byte) {
+	rec.capturing = false
+
+	height := rec.height
+	if height == 0 {
+		rec.buf = rec.buf[:0]
+		return 0, nil
+	}
+	block := appendCommitRecord(rec.buf, height)
+	rec.height = 0
+	rec.buf = block[:0]
+
+	return height, block
+}
@@ -0,0 +1,153 @@
+// Package streaming streams the ABCI messages and the state changes of the
+// blocks of LinkApp, replacing the streaming services of the SDK which stream
+// the writes of every branch of the state as they happen.
+//
+// A Service collects the state changes of a block when the deliver state is
+// flushed into the root store on commit, so that they are exactly the change
+// set of the block, and streams them once the block is committed, or right
+// before the commit if OptStopNodeOnErr or the fsync of the file service is
+// set, so that the block is not lost.
+//
+// The services are selected by OptStreamers among the registered ones: "file"
+// writes the blocks into files read back by Replay, while "grpc" and "kafka"
+// send them to a message bus, whose consumers decode them by DecodeBlock.
+package streaming
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/spf13/cast"
+
+	"github.com/Finschia/finschia-sdk/baseapp"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+// app.toml options of the streaming services
+const (
+	// OptStreamers lists the names of the streaming services to run
+	OptStreamers = "store.streamers"
+
+	// OptStopNodeOnErr streams the blocks before their commit, stopping the
+	// node by a panic with the height uncommitted if a streaming service fails
+	// to stream the block, which is otherwise logged and lost
+	OptStopNodeOnErr = "store.stop_node_on_err"
+
+	// optKeysFormat is the format of the option listing the store keys exposed
+	// to a streaming service, where "*" exposes all of them
+	optKeysFormat = "streamers.%s.keys"
+)
+
+// Service is a baseapp.StreamingService notified of the commits of the blocks.
+type Service interface {
+	baseapp.StreamingService
+
+	// ListenStartCommit is called before the state changes of the block are
+	// flushed into the root store, which the service collects from its
+	// listeners until ListenCommit.
+	ListenStartCommit()
+
+	// ListenCommit is called once the state changes of the block have been
+	// flushed, after the commit as the ListenCommit of the listeners of the
+	// SDK, or before it if the block must not be lost.
+	ListenCommit() error
+}
+
+// ServiceConstructor constructs a streaming service exposing the given store keys.
+type ServiceConstructor func(opts servertypes.AppOptions, keys []storetypes.StoreKey) (Service, error)
+
+// serviceConstructors are the constructors of the streaming services by name
+var serviceConstructors = map[string]ServiceConstructor{
+	FileServiceName:  NewFileStreamingServiceFromOptions,
+	GRPCServiceName:  NewGRPCStreamingServiceFromOptions,
+	KafkaServiceName: NewKafkaStreamingServiceFromOptions,
+}
+
+// RegisterServiceConstructor registers the constructor of the streaming
+// service of the name, which is selected by OptStreamers.
+func RegisterServiceConstructor(name string, constructor ServiceConstructor) {
+	serviceConstructors[strings.ToLower(name)] = constructor
+}
+
+// LoadStreamingServices constructs the streaming services selected by the
+// options and registers them with the BaseApp. The returned WaitGroup awaits
+// the services once they are closed.
+func LoadStreamingServices(bApp *baseapp.BaseApp, appOpts servertypes.AppOptions, keys map[string]*sdk.KVStoreKey) ([]Service, *sync.WaitGroup, error) {
+	wg := new(sync.WaitGroup)
+	streamers := cast.ToStringSlice(appOpts.Get(OptStreamers))
+	services := make([]Service, 0, len(streamers))
+
+	fail := func(err error) ([]Service, *sync.WaitGroup, error) {
+		// close the services already spun up
+		for _, service := range services {
+			_ = service.Close()
+		}
+		return nil, nil, err
+	}
+
+	for _, name := range streamers {
+		exposeKeys := exposedStoreKeys(cast.ToStringSlice(appOpts.Get(fmt.Sprintf(optKeysFormat, name))), keys)
+		if len(exposeKeys) == 0 {
+			// not exposing anything
+			continue
+		}
+
+		constructor, ok := serviceConstructors[strings.ToLower(name)]
+		if !ok {
+			return fail(fmt.Errorf("unrecognized streaming service name %s", name))
+		}
+		service, err := constructor(appOpts, exposeKeys)
+		if err != nil {
+			return fail(fmt.Errorf("failed to construct streaming service %s: %w", name, err))
+		}
+
+		if l, ok := service.(interface{ SetLogger(log.Logger) }); ok {
+			l.SetLogger(bApp.Logger().With("module", "streaming", "streamer", name))
+		}
+		bApp.SetStreamingService(service)
+		if err := service.Stream(wg); err != nil {
+			_ = service.Close()
+			return fail(err)
+		}
+		services = append(services, service)
+	}
+
+	return services, wg, nil
+}
+
+// exposedStoreKeys returns the store keys of the names, sorted by name, where
+// "*" stands for all of them.
+func exposedStoreKeys(names []string, keys map[string]*sdk.KVStoreKey) []storetypes.StoreKey {
+	exposeAll := false
+	for _, name := range names {
+		if name == "*" {
+			exposeAll = true
+		}
+	}
+
+	var exposed []string
+	if exposeAll {
+		for name := range keys {
+			exposed = append(exposed, name)
+		}
+	} else {
+		for _, name := range names {
+			if _, ok := keys[name]; ok {
+				exposed = append(exposed, name)
+			}
+		}
+	}
+	sort.Strings(exposed)
+
+	storeKeys := make([]storetypes.StoreKey, 0, len(exposed))
+	for _, name := range exposed {
+		storeKeys = append(storeKeys, keys[name])
+	}
+
+	return storeKeys
+}
@@ -0,0 +1,290 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	cryptotypes "github.com/Finschia/finschia-sdk/crypto/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+	foundationkeeper "github.com/Finschia/finschia-sdk/x/foundation/keeper"
+	minttypes "github.com/Finschia/finschia-sdk/x/mint/types"
+	slashingtypes "github.com/Finschia/finschia-sdk/x/slashing/types"
+	stakingkeeper "github.com/Finschia/finschia-sdk/x/staking/keeper"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+)
+
+// InPlaceTestnetArgs defines how the state of an existing chain is rewritten
+// into the one of a private single validator network.
+type InPlaceTestnetArgs struct {
+	// ValidatorConsPubKey is the consensus public key of the local validator.
+	ValidatorConsPubKey cryptotypes.PubKey
+	// ValidatorOperator is the account operating the local validator. It also
+	// becomes the sole member of the foundation.
+	ValidatorOperator sdk.AccAddress
+	// ValidatorPower is the consensus power of the local validator.
+	ValidatorPower int64
+	// VotingPeriod is the voting period of gov and foundation proposals, and
+	// the maximum deposit period of gov proposals.
+	VotingPeriod time.Duration
+	// AccountsToFund are the accounts minted the coins they lack to hold
+	// FundAmount.
+	AccountsToFund []sdk.AccAddress
+	FundAmount     sdk.Coins
+}
+
+// InitInPlaceTestnet rewrites the state loaded by the app into the one of a
+// private network run by a single local validator. It replaces the validator
+// set, the foundation members and decision policy, shortens the voting periods
+// and mints test funds. Running it again on its result with the same args
+// changes nothing but the rewards accrued in between.
+//
+// The changes are written to the uncommitted state, so that they are committed
+// along with the next block and the app hash of the last block is preserved.
+func (app *LinkApp) InitInPlaceTestnet(args InPlaceTestnetArgs) error {
+	ctx := app.NewUncachedContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+
+	if err := app.replaceValidatorSet(ctx, args); err != nil {
+		return err
+	}
+	if err := app.replaceFoundationMembers(ctx, args); err != nil {
+		return err
+	}
+
+	/* Handle gov state. */
+	depositParams := app.GovKeeper.GetDepositParams(ctx)
+	depositParams.MaxDepositPeriod = args.VotingPeriod
+	app.GovKeeper.SetDepositParams(ctx, depositParams)
+
+	votingParams := app.GovKeeper.GetVotingParams(ctx)
+	votingParams.VotingPeriod = args.VotingPeriod
+	app.GovKeeper.SetVotingParams(ctx, votingParams)
+
+	/* Handle bank state. */
+	for _, addr := range args.AccountsToFund {
+		if err := app.fundAccount(ctx, addr, args.FundAmount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceValidatorSet removes all the validators and bonds a new one.
+//
+// The delegations, unbonding delegations and redelegations are settled at once,
+// as if they had matured: the delegators get their tokens and rewards back, and
+// the commissions of the validators are withdrawn, so that the invariants of
+// staking and distribution hold on the resulting state. The local validator is
+// then created by its operator, who is minted the tokens it lacks to bond, so
+// that running it again on its result mints nothing.
+func (app *LinkApp) replaceValidatorSet(ctx sdk.Context, args InPlaceTestnetArgs) error {
+	/* Handle staking state. */
+	if err := app.settleUnbondingDelegations(ctx); err != nil {
+		return err
+	}
+	app.StakingKeeper.IterateRedelegations(ctx, func(_ int64, red stakingtypes.Redelegation) (stop bool) {
+		app.StakingKeeper.RemoveRedelegation(ctx, red)
+		return false
+	})
+	store := ctx.KVStore(app.keys[stakingtypes.StoreKey])
+	deletePrefix(store, stakingtypes.UnbondingQueueKey)
+	deletePrefix(store, stakingtypes.RedelegationQueueKey)
+
+	for _, validator := range app.StakingKeeper.GetAllValidators(ctx) {
+		if err := app.removeValidator(ctx, validator); err != nil {
+			return fmt.Errorf("failed to remove validator %s: %w", validator.OperatorAddress, err)
+		}
+	}
+	app.StakingKeeper.SetLastTotalPower(ctx, sdk.ZeroInt())
+
+	// the validator is created unbonded, so that the next end block bonds it and
+	// hands its power over to ostracon.
+	valAddr := sdk.ValAddress(args.ValidatorOperator)
+	bondDenom := app.StakingKeeper.BondDenom(ctx)
+	tokens := sdk.NewCoin(bondDenom, app.StakingKeeper.TokensFromConsensusPower(ctx, args.ValidatorPower))
+	if err := app.fundAccount(ctx, args.ValidatorOperator, sdk.NewCoins(tokens)); err != nil {
+		return err
+	}
+	msg, err := stakingtypes.NewMsgCreateValidator(
+		valAddr,
+		args.ValidatorConsPubKey,
+		tokens,
+		stakingtypes.NewDescription("local", "", "", "", ""),
+		stakingtypes.NewCommissionRates(sdk.ZeroDec(), sdk.OneDec(), sdk.OneDec()),
+		sdk.OneInt(),
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := stakingkeeper.NewMsgServerImpl(app.StakingKeeper).CreateValidator(sdk.WrapSDKContext(ctx), msg); err != nil {
+		return fmt.Errorf("failed to create the local validator: %w", err)
+	}
+
+	/* Handle slashing state. */
+	consAddr := sdk.ConsAddress(args.ValidatorConsPubKey.Address())
+	app.SlashingKeeper.SetValidatorSigningInfo(ctx, consAddr, slashingtypes.NewValidatorSigningInfo(consAddr, ctx.BlockHeight(), 0, time.Unix(0, 0), false, 0))
+
+	return nil
+}
+
+// settleUnbondingDelegations pays the balances of all the unbonding delegations
+// out of the not bonded pool, and removes them.
+func (app *LinkApp) settleUnbondingDelegations(ctx sdk.Context) error {
+	var ubds []stakingtypes.UnbondingDelegation
+	app.StakingKeeper.IterateUnbondingDelegations(ctx, func(_ int64, ubd stakingtypes.UnbondingDelegation) (stop bool) {
+		ubds = append(ubds, ubd)
+		return false
+	})
+
+	bondDenom := app.StakingKeeper.BondDenom(ctx)
+	for _, ubd := range ubds {
+		delAddr, err := sdk.AccAddressFromBech32(ubd.DelegatorAddress)
+		if err != nil {
+			return err
+		}
+		balance := sdk.ZeroInt()
+		for _, entry := range ubd.Entries {
+			balance = balance.Add(entry.Balance)
+		}
+		if balance.IsPositive() {
+			coins := sdk.NewCoins(sdk.NewCoin(bondDenom, balance))
+			if err := app.BankKeeper.UndelegateCoinsFromModuleToAccount(ctx, stakingtypes.NotBondedPoolName, delAddr, coins); err != nil {
+				return err
+			}
+		}
+		app.StakingKeeper.RemoveUnbondingDelegation(ctx, ubd)
+	}
+	return nil
+}
+
+// removeValidator unbonds all the delegations of the validator at once, paying
+// the tokens back to the delegators, and removes it. Its rewards and commission
+// are withdrawn by the hooks of distribution.
+func (app *LinkApp) removeValidator(ctx sdk.Context, validator stakingtypes.Validator) error {
+	valAddr := validator.GetOperator()
+	pool := stakingtypes.NotBondedPoolName
+	if validator.IsBonded() {
+		pool = stakingtypes.BondedPoolName
+	}
+
+	bondDenom := app.StakingKeeper.BondDenom(ctx)
+	for _, delegation := range app.StakingKeeper.GetValidatorDelegations(ctx, valAddr) {
+		delAddr := delegation.GetDelegatorAddr()
+		amount, err := app.StakingKeeper.Unbond(ctx, delAddr, valAddr, delegation.Shares)
+		if err != nil {
+			return err
+		}
+		if amount.IsPositive() {
+			coins := sdk.NewCoins(sdk.NewCoin(bondDenom, amount))
+			if err := app.BankKeeper.UndelegateCoinsFromModuleToAccount(ctx, pool, delAddr, coins); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Unbond removes the validators which were already unbonded
+	validator, found := app.StakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		return nil
+	}
+
+	// the tokens left without shares, if any, are burnt
+	if validator.Tokens.IsPositive() {
+		if err := app.BankKeeper.BurnCoins(ctx, pool, sdk.NewCoins(sdk.NewCoin(bondDenom, validator.Tokens))); err != nil {
+			return err
+		}
+		validator.Tokens = sdk.ZeroInt()
+	}
+
+	app.StakingKeeper.DeleteValidatorByPowerIndex(ctx, validator)
+	app.StakingKeeper.DeleteLastValidatorPower(ctx, valAddr)
+	if validator.IsUnbonding() {
+		app.StakingKeeper.DeleteValidatorQueue(ctx, validator)
+	}
+	validator.Status = stakingtypes.Unbonded
+	app.StakingKeeper.SetValidator(ctx, validator)
+	app.StakingKeeper.RemoveValidator(ctx, valAddr)
+	return nil
+}
+
+// fundAccount mints to addr the coins it lacks to hold amount.
+func (app *LinkApp) fundAccount(ctx sdk.Context, addr sdk.AccAddress, amount sdk.Coins) error {
+	balances := app.BankKeeper.GetAllBalances(ctx, addr)
+	var lacking sdk.Coins
+	for _, coin := range amount {
+		if balance := balances.AmountOf(coin.Denom); balance.LT(coin.Amount) {
+			lacking = lacking.Add(sdk.NewCoin(coin.Denom, coin.Amount.Sub(balance)))
+		}
+	}
+	if lacking.IsZero() {
+		return nil
+	}
+
+	if err := app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, lacking); err != nil {
+		return err
+	}
+	return app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, addr, lacking)
+}
+
+func deletePrefix(store sdk.KVStore, prefix []byte) {
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// replaceFoundationMembers makes the validator operator the sole member of the
+// foundation, able to pass proposals by itself.
+func (app *LinkApp) replaceFoundationMembers(ctx sdk.Context, args InPlaceTestnetArgs) error {
+	/* Handle foundation state. */
+	operator := args.ValidatorOperator.String()
+	updates := []foundation.MemberRequest{{Address: operator, Metadata: "local"}}
+	for _, member := range app.FoundationKeeper.ExportGenesis(ctx).Members {
+		if member.Address != operator {
+			updates = append(updates, foundation.MemberRequest{Address: member.Address, Remove: true})
+		}
+	}
+
+	policy := &foundation.ThresholdDecisionPolicy{
+		Threshold: sdk.OneDec(),
+		Windows: &foundation.DecisionPolicyWindows{
+			VotingPeriod: args.VotingPeriod,
+		},
+	}
+
+	// members cannot be updated under an outsourcing decision policy, so the
+	// new policy is put in place first. It gets validated by the update of the
+	// members.
+	gs := app.FoundationKeeper.ExportGenesis(ctx)
+	info := gs.Foundation
+	if err := info.SetDecisionPolicy(policy); err != nil {
+		return err
+	}
+	if err := app.FoundationKeeper.InitGenesis(ctx, &foundation.GenesisState{
+		Params:             gs.Params,
+		Foundation:         info,
+		PreviousProposalId: gs.PreviousProposalId,
+		Pool:               gs.Pool,
+	}); err != nil {
+		return err
+	}
+
+	msgServer := foundationkeeper.NewMsgServer(app.FoundationKeeper)
+	if _, err := msgServer.UpdateMembers(sdk.WrapSDKContext(ctx), &foundation.MsgUpdateMembers{
+		Authority:     app.FoundationKeeper.GetAuthority(),
+		MemberUpdates: updates,
+	}); err != nil {
+		return fmt.Errorf("failed to update foundation members: %w", err)
+	}
+
+	return nil
+}
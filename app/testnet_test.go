@@ -0,0 +1,199 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ocabci "github.com/Finschia/ostracon/abci/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	cryptocodec "github.com/Finschia/finschia-sdk/crypto/codec"
+	"github.com/Finschia/finschia-sdk/crypto/keys/ed25519"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	distrtypes "github.com/Finschia/finschia-sdk/x/distribution/types"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+	minttypes "github.com/Finschia/finschia-sdk/x/mint/types"
+	stakingkeeper "github.com/Finschia/finschia-sdk/x/staking/keeper"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+)
+
+func TestInitInPlaceTestnet(t *testing.T) {
+	app := newExportTestApp(t)
+	lastCommitID := app.LastCommitID()
+
+	consPubKey := ed25519.GenPrivKey().PubKey()
+	operator := sdk.AccAddress("operator____________")
+	funded := sdk.AccAddress("funded______________")
+	fundAmount := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1000000))
+
+	err := app.InitInPlaceTestnet(InPlaceTestnetArgs{
+		ValidatorConsPubKey: consPubKey,
+		ValidatorOperator:   operator,
+		ValidatorPower:      100,
+		VotingPeriod:        time.Minute,
+		AccountsToFund:      []sdk.AccAddress{operator, funded},
+		FundAmount:          fundAmount,
+	})
+	require.NoError(t, err)
+
+	// the state of the last block is preserved
+	require.Equal(t, lastCommitID, app.LastCommitID())
+
+	// the next block hands the new validator over to ostracon
+	header := tmproto.Header{Height: app.LastBlockHeight() + 1, Time: time.Now()}
+	app.BeginBlock(ocabci.RequestBeginBlock{Header: header})
+	res := app.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	app.Commit()
+
+	expectedPubKey, err := cryptocodec.ToOcProtoPublicKey(consPubKey)
+	require.NoError(t, err)
+	require.Equal(t, []abci.ValidatorUpdate{{PubKey: expectedPubKey, Power: 100}}, res.ValidatorUpdates)
+
+	ctx := app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+
+	validators := app.StakingKeeper.GetAllValidators(ctx)
+	require.Len(t, validators, 1)
+	require.Equal(t, sdk.ValAddress(operator).String(), validators[0].OperatorAddress)
+	require.Equal(t, stakingtypes.Bonded, validators[0].Status)
+	_, found := app.StakingKeeper.GetDelegation(ctx, operator, sdk.ValAddress(operator))
+	require.True(t, found)
+
+	require.Equal(t, fundAmount, app.BankKeeper.GetAllBalances(ctx, operator))
+	require.Equal(t, fundAmount, app.BankKeeper.GetAllBalances(ctx, funded))
+
+	members := app.FoundationKeeper.ExportGenesis(ctx).Members
+	require.Len(t, members, 1)
+	require.Equal(t, operator.String(), members[0].Address)
+	policy := app.FoundationKeeper.ExportGenesis(ctx).Foundation.GetDecisionPolicy()
+	require.Equal(t, time.Minute, policy.GetVotingPeriod())
+	require.IsType(t, &foundation.ThresholdDecisionPolicy{}, policy)
+
+	require.Equal(t, time.Minute, app.GovKeeper.GetVotingParams(ctx).VotingPeriod)
+	require.Equal(t, time.Minute, app.GovKeeper.GetDepositParams(ctx).MaxDepositPeriod)
+}
+
+func TestInitInPlaceTestnetReplacesState(t *testing.T) {
+	app := newExportTestApp(t)
+	bondDenom := sdk.DefaultBondDenom
+	nextBlock := func() abci.ResponseEndBlock {
+		header := tmproto.Header{Height: app.LastBlockHeight() + 1, Time: time.Now()}
+		app.BeginBlock(ocabci.RequestBeginBlock{Header: header})
+		res := app.EndBlock(abci.RequestEndBlock{Height: header.Height})
+		app.Commit()
+		return res
+	}
+	checkInvariants := func() {
+		results, err := app.CheckInvariants(nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, results)
+		for _, result := range results {
+			require.False(t, result.Broken, result.Message)
+		}
+	}
+
+	// a chain of two validators with delegations, an unbonding delegation, a
+	// redelegation, rewards and commissions
+	operatorA := sdk.AccAddress("operatorA___________")
+	require.NoError(t, app.InitInPlaceTestnet(InPlaceTestnetArgs{
+		ValidatorConsPubKey: ed25519.GenPrivKey().PubKey(),
+		ValidatorOperator:   operatorA,
+		ValidatorPower:      100,
+		VotingPeriod:        time.Minute,
+	}))
+	nextBlock()
+
+	ctx := app.NewUncachedContext(false, tmproto.Header{Height: app.LastBlockHeight() + 1, Time: time.Now()})
+	goCtx := sdk.WrapSDKContext(ctx)
+	operatorB := sdk.AccAddress("operatorB___________")
+	delegator := sdk.AccAddress("delegator___________")
+	funds := sdk.NewCoins(sdk.NewCoin(bondDenom, sdk.TokensFromConsensusPower(1000, sdk.DefaultPowerReduction)))
+	for _, addr := range []sdk.AccAddress{operatorB, delegator} {
+		require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, funds))
+		require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, addr, funds))
+	}
+	stakingServer := stakingkeeper.NewMsgServerImpl(app.StakingKeeper)
+	createValidator, err := stakingtypes.NewMsgCreateValidator(
+		sdk.ValAddress(operatorB), ed25519.GenPrivKey().PubKey(),
+		sdk.NewCoin(bondDenom, sdk.TokensFromConsensusPower(50, sdk.DefaultPowerReduction)),
+		stakingtypes.NewDescription("b", "", "", "", ""),
+		stakingtypes.NewCommissionRates(sdk.NewDecWithPrec(1, 1), sdk.OneDec(), sdk.OneDec()),
+		sdk.OneInt(),
+	)
+	require.NoError(t, err)
+	_, err = stakingServer.CreateValidator(goCtx, createValidator)
+	require.NoError(t, err)
+	stake := sdk.NewCoin(bondDenom, sdk.TokensFromConsensusPower(10, sdk.DefaultPowerReduction))
+	for _, operator := range []sdk.AccAddress{operatorA, operatorB} {
+		_, err = stakingServer.Delegate(goCtx, stakingtypes.NewMsgDelegate(delegator, sdk.ValAddress(operator), stake))
+		require.NoError(t, err)
+	}
+	part := sdk.NewCoin(bondDenom, stake.Amount.QuoRaw(2))
+	_, err = stakingServer.Undelegate(goCtx, stakingtypes.NewMsgUndelegate(delegator, sdk.ValAddress(operatorB), part))
+	require.NoError(t, err)
+	_, err = stakingServer.BeginRedelegate(goCtx, stakingtypes.NewMsgBeginRedelegate(delegator, sdk.ValAddress(operatorA), sdk.ValAddress(operatorB), part))
+	require.NoError(t, err)
+	nextBlock()
+
+	ctx = app.NewUncachedContext(false, tmproto.Header{Height: app.LastBlockHeight() + 1, Time: time.Now()})
+	rewards := sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 1000))
+	for _, operator := range []sdk.AccAddress{operatorA, operatorB} {
+		require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, rewards))
+		require.NoError(t, app.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, distrtypes.ModuleName, rewards))
+		validator, found := app.StakingKeeper.GetValidator(ctx, sdk.ValAddress(operator))
+		require.True(t, found)
+		app.DistrKeeper.AllocateTokensToValidator(ctx, validator, sdk.NewDecCoinsFromCoins(rewards...))
+	}
+	nextBlock()
+	checkInvariants()
+
+	ctx = app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+	require.Len(t, app.StakingKeeper.GetAllValidators(ctx), 2)
+	require.NotEmpty(t, app.StakingKeeper.GetAllUnbondingDelegations(ctx, delegator))
+	require.NotEmpty(t, app.StakingKeeper.GetAllRedelegations(ctx, delegator, nil, nil))
+
+	// fork it into a network of a new validator
+	consPubKey := ed25519.GenPrivKey().PubKey()
+	operator := sdk.AccAddress("operator____________")
+	funded := sdk.AccAddress("funded______________")
+	args := InPlaceTestnetArgs{
+		ValidatorConsPubKey: consPubKey,
+		ValidatorOperator:   operator,
+		ValidatorPower:      100,
+		VotingPeriod:        time.Minute,
+		AccountsToFund:      []sdk.AccAddress{operator, funded},
+		FundAmount:          sdk.NewCoins(sdk.NewInt64Coin(bondDenom, 1000000)),
+	}
+	require.NoError(t, app.InitInPlaceTestnet(args))
+	res := nextBlock()
+	expectedPubKey, err := cryptocodec.ToOcProtoPublicKey(consPubKey)
+	require.NoError(t, err)
+	require.Equal(t, []abci.ValidatorUpdate{{PubKey: expectedPubKey, Power: 100}}, res.ValidatorUpdates)
+	checkInvariants()
+
+	ctx = app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+	validators := app.StakingKeeper.GetAllValidators(ctx)
+	require.Len(t, validators, 1)
+	require.Equal(t, sdk.ValAddress(operator).String(), validators[0].OperatorAddress)
+	require.Empty(t, app.StakingKeeper.GetAllDelegatorDelegations(ctx, delegator))
+	require.Empty(t, app.StakingKeeper.GetAllUnbondingDelegations(ctx, delegator))
+	require.Empty(t, app.StakingKeeper.GetAllRedelegations(ctx, delegator, nil, nil))
+	// the delegator got its tokens back, along with its rewards
+	require.True(t, app.BankKeeper.GetBalance(ctx, delegator, bondDenom).Amount.GT(funds.AmountOf(bondDenom)))
+
+	// running it again mints nothing
+	supply := app.BankKeeper.GetSupply(ctx, bondDenom)
+	operatorBalance := app.BankKeeper.GetAllBalances(ctx, operator)
+	fundedBalance := app.BankKeeper.GetAllBalances(ctx, funded)
+	require.NoError(t, app.InitInPlaceTestnet(args))
+	nextBlock()
+	checkInvariants()
+
+	ctx = app.NewContext(true, tmproto.Header{Height: app.LastBlockHeight()})
+	require.Equal(t, supply, app.BankKeeper.GetSupply(ctx, bondDenom))
+	require.Equal(t, operatorBalance, app.BankKeeper.GetAllBalances(ctx, operator))
+	require.Equal(t, fundedBalance, app.BankKeeper.GetAllBalances(ctx, funded))
+	require.Len(t, app.StakingKeeper.GetAllValidators(ctx), 1)
+}
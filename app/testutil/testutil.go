@@ -0,0 +1,27 @@
+// Package testutil provides the helpers shared by the tests of the app.
+package testutil
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+// AppOptions are the app options of a map, in place of the ones read by
+// viper.
+type AppOptions map[string]interface{}
+
+func (o AppOptions) Get(key string) interface{} {
+	return o[key]
+}
+
+// TypedEvent returns the ABCI event of the typed event.
+func TypedEvent(t *testing.T, event proto.Message) abci.Event {
+	ev, err := sdk.TypedEventToEvent(event)
+	require.NoError(t, err)
+	return abci.Event(ev)
+}
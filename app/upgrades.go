@@ -0,0 +1,37 @@
+package app
+
+import (
+	"fmt"
+
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/module"
+	upgradetypes "github.com/Finschia/finschia-sdk/x/upgrade/types"
+)
+
+// UpgradeName is the name of the upgrade adding the blocklist module
+const UpgradeName = "v2-Daisy"
+
+// setUpgradeHandlers registers the handler of the upgrade and, if the node
+// halted for it, the store loader adding its stores. It must be called before
+// loading the latest version.
+func (app *LinkApp) setUpgradeHandlers() {
+	app.UpgradeKeeper.SetUpgradeHandler(UpgradeName, func(ctx sdk.Context, plan upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		// blocklist is not in fromVM, so RunMigrations inits its genesis with
+		// the default params, which block every module account
+		return app.mm.RunMigrations(ctx, app.configurator, fromVM)
+	})
+
+	upgradeInfo, err := app.UpgradeKeeper.ReadUpgradeInfoFromDisk()
+	if err != nil {
+		panic(fmt.Errorf("failed to read upgrade info from disk: %w", err))
+	}
+	if upgradeInfo.Name != UpgradeName || app.UpgradeKeeper.IsSkipHeight(upgradeInfo.Height) {
+		return
+	}
+
+	// blocklist keeps its params in its subspace of the params store, so it
+	// adds no store of its own
+	storeUpgrades := storetypes.StoreUpgrades{}
+	app.SetStoreLoader(upgradetypes.UpgradeStoreLoader(upgradeInfo.Height, &storeUpgrades))
+}
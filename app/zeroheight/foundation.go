@@ -0,0 +1,140 @@
+package zeroheight
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+	foundationkeeper "github.com/Finschia/finschia-sdk/x/foundation/keeper"
+	foundationmodule "github.com/Finschia/finschia-sdk/x/foundation/module"
+)
+
+var _ Module = FoundationAppModule{}
+
+// FoundationAppModule is the foundation module normalizing its genesis for a
+// zero height genesis.
+type FoundationAppModule struct {
+	foundationmodule.AppModule
+
+	config foundation.Config
+}
+
+// NewFoundationAppModule creates a new foundation AppModule over the keeper
+// created with the config.
+func NewFoundationAppModule(cdc codec.Codec, keeper foundationkeeper.Keeper, config foundation.Config) FoundationAppModule {
+	return FoundationAppModule{
+		AppModule: foundationmodule.NewAppModule(cdc, keeper),
+		config:    config,
+	}
+}
+
+// ExportZeroHeightGenesis satisfies the Module interface
+func (am FoundationAppModule) ExportZeroHeightGenesis(ctx sdk.Context, cdc codec.JSONCodec) (json.RawMessage, error) {
+	bz := am.ExportGenesis(ctx, cdc)
+
+	var genesis foundation.GenesisState
+	if err := cdc.UnmarshalJSON(bz, &genesis); err != nil {
+		return nil, err
+	}
+	if !normalizeFoundationGenesis(ctx, &genesis, am.config) {
+		return bz, nil
+	}
+
+	return cdc.MarshalJSON(&genesis)
+}
+
+// normalizeFoundationGenesis normalizes the genesis for a zero height genesis,
+// against the block time of ctx if known, and reports whether it changed.
+//
+// The proposals which can never be executed are dropped, along with their
+// votes. Those are the aborted and withdrawn proposals, kept in the state only
+// until the end of their voting period, the proposals submitted to a former
+// version of the foundation, and those whose execution period has ended.
+//
+// The censorships without an authority and the grants over the messages not
+// censored are dropped, as the import of the genesis would fail on them.
+//
+// The times of the members, proposals and votes later than the block time are
+// set to it, as the zero height chain cannot start before them.
+func normalizeFoundationGenesis(ctx sdk.Context, genesis *foundation.GenesisState, config foundation.Config) bool {
+	logger := ctx.Logger()
+	now := ctx.BlockTime()
+	changed := false
+
+	dropped := map[uint64]bool{}
+	proposals := make([]foundation.Proposal, 0, len(genesis.Proposals))
+	for _, proposal := range genesis.Proposals {
+		if proposal.Status == foundation.PROPOSAL_STATUS_ABORTED ||
+			proposal.Status == foundation.PROPOSAL_STATUS_WITHDRAWN ||
+			proposal.FoundationVersion != genesis.Foundation.Version ||
+			(!now.IsZero() && !proposal.VotingPeriodEnd.Add(config.MaxExecutionPeriod).After(now)) {
+			dropped[proposal.Id] = true
+			continue
+		}
+		proposals = append(proposals, proposal)
+	}
+	if len(dropped) != 0 {
+		votes := make([]foundation.Vote, 0, len(genesis.Votes))
+		for _, vote := range genesis.Votes {
+			if !dropped[vote.ProposalId] {
+				votes = append(votes, vote)
+			}
+		}
+		genesis.Proposals = proposals
+		genesis.Votes = votes
+		changed = true
+
+		logger.Info("dropped foundation proposals which can never be executed", "count", len(dropped))
+	}
+
+	censored := map[string]bool{}
+	censorships := make([]foundation.Censorship, 0, len(genesis.Censorships))
+	for _, censorship := range genesis.Censorships {
+		if censorship.Authority == foundation.CensorshipAuthorityUnspecified {
+			logger.Info("dropped foundation censorship without authority", "msg_type_url", censorship.MsgTypeUrl)
+			continue
+		}
+		censored[censorship.MsgTypeUrl] = true
+		censorships = append(censorships, censorship)
+	}
+	if len(censorships) != len(genesis.Censorships) {
+		genesis.Censorships = censorships
+		changed = true
+	}
+
+	grants := make([]foundation.GrantAuthorization, 0, len(genesis.Authorizations))
+	for _, grant := range genesis.Authorizations {
+		if auth := grant.GetAuthorization(); auth == nil || !censored[auth.MsgTypeURL()] {
+			logger.Info("dropped foundation grant over a message not censored", "grantee", grant.Grantee)
+			continue
+		}
+		grants = append(grants, grant)
+	}
+	if len(grants) != len(genesis.Authorizations) {
+		genesis.Authorizations = grants
+		changed = true
+	}
+
+	if now.IsZero() {
+		return changed
+	}
+	clamp := func(t *time.Time) {
+		if t.After(now) {
+			*t = now
+			changed = true
+		}
+	}
+	for i := range genesis.Members {
+		clamp(&genesis.Members[i].AddedAt)
+	}
+	for i := range genesis.Proposals {
+		clamp(&genesis.Proposals[i].SubmitTime)
+	}
+	for i := range genesis.Votes {
+		clamp(&genesis.Votes[i].SubmitTime)
+	}
+
+	return changed
+}
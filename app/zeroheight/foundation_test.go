@@ -0,0 +1,138 @@
+package zeroheight
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/ostracon/libs/log"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+)
+
+func TestNormalizeFoundationGenesis(t *testing.T) {
+	config := foundation.DefaultConfig()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	grantee := sdk.AccAddress("grantee").String()
+
+	grant := func(t *testing.T) foundation.GrantAuthorization {
+		grant := foundation.GrantAuthorization{Grantee: grantee}
+		require.NoError(t, grant.SetAuthorization(&foundation.ReceiveFromTreasuryAuthorization{}))
+		return grant
+	}
+
+	testCases := map[string]struct {
+		now       time.Time
+		malleate  func(t *testing.T, genesis *foundation.GenesisState)
+		changed   bool
+		proposals []uint64
+		grants    int
+	}{
+		"nothing to normalize": {
+			now:       now,
+			proposals: []uint64{1},
+			grants:    1,
+		},
+		"withdrawn proposal": {
+			now: now,
+			malleate: func(t *testing.T, genesis *foundation.GenesisState) {
+				genesis.Proposals[0].Status = foundation.PROPOSAL_STATUS_WITHDRAWN
+			},
+			changed: true,
+			grants:  1,
+		},
+		"proposal of a former foundation": {
+			now: now,
+			malleate: func(t *testing.T, genesis *foundation.GenesisState) {
+				genesis.Foundation.Version++
+			},
+			changed: true,
+			grants:  1,
+		},
+		"execution period ended": {
+			now:     now.Add(time.Hour + config.MaxExecutionPeriod),
+			changed: true,
+			grants:  1,
+		},
+		"execution period ended at unknown time": {
+			malleate: func(t *testing.T, genesis *foundation.GenesisState) {
+				genesis.Proposals[0].VotingPeriodEnd = time.Time{}
+			},
+			proposals: []uint64{1},
+			grants:    1,
+		},
+		"censorship without authority": {
+			now: now,
+			malleate: func(t *testing.T, genesis *foundation.GenesisState) {
+				genesis.Censorships[0].Authority = foundation.CensorshipAuthorityUnspecified
+			},
+			changed:   true,
+			proposals: []uint64{1},
+		},
+		"grant over a message not censored": {
+			now: now,
+			malleate: func(t *testing.T, genesis *foundation.GenesisState) {
+				genesis.Censorships = nil
+			},
+			changed:   true,
+			proposals: []uint64{1},
+		},
+		"times later than the block time": {
+			now:       now.Add(-time.Second),
+			changed:   true,
+			proposals: []uint64{1},
+			grants:    1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			genesis := foundation.GenesisState{
+				Foundation: foundation.FoundationInfo{Version: 1},
+				Members:    []foundation.Member{{Address: grantee, AddedAt: now.Add(-time.Hour)}},
+				Proposals: []foundation.Proposal{{
+					Id:                1,
+					SubmitTime:        now,
+					FoundationVersion: 1,
+					Status:            foundation.PROPOSAL_STATUS_SUBMITTED,
+					VotingPeriodEnd:   now.Add(time.Hour),
+				}},
+				Votes: []foundation.Vote{{ProposalId: 1, Voter: grantee, SubmitTime: now}},
+				Censorships: []foundation.Censorship{{
+					MsgTypeUrl: sdk.MsgTypeURL((*foundation.MsgWithdrawFromTreasury)(nil)),
+					Authority:  foundation.CensorshipAuthorityFoundation,
+				}},
+				Authorizations: []foundation.GrantAuthorization{grant(t)},
+			}
+			if tc.malleate != nil {
+				tc.malleate(t, &genesis)
+			}
+
+			ctx := sdk.Context{}.WithLogger(log.NewNopLogger()).WithBlockTime(tc.now)
+			require.Equal(t, tc.changed, normalizeFoundationGenesis(ctx, &genesis, config))
+
+			var proposals []uint64
+			for _, proposal := range genesis.Proposals {
+				proposals = append(proposals, proposal.Id)
+			}
+			require.Equal(t, tc.proposals, proposals)
+			require.Len(t, genesis.Votes, len(tc.proposals))
+			require.Len(t, genesis.Authorizations, tc.grants)
+
+			if tc.now.IsZero() {
+				return
+			}
+			for _, member := range genesis.Members {
+				require.False(t, member.AddedAt.After(tc.now))
+			}
+			for _, proposal := range genesis.Proposals {
+				require.False(t, proposal.SubmitTime.After(tc.now))
+			}
+			for _, vote := range genesis.Votes {
+				require.False(t, vote.SubmitTime.After(tc.now))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package zeroheight
+
+import (
+	"encoding/json"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	wasmkeeper "github.com/Finschia/wasmd/x/wasm/keeper"
+	"github.com/Finschia/wasmd/x/wasm/simulation"
+	wasmtypes "github.com/Finschia/wasmd/x/wasm/types"
+	"github.com/Finschia/wasmd/x/wasmplus"
+	wasmpluskeeper "github.com/Finschia/wasmd/x/wasmplus/keeper"
+	wasmplustypes "github.com/Finschia/wasmd/x/wasmplus/types"
+)
+
+var _ Module = WasmPlusAppModule{}
+
+// WasmPlusAppModule is the wasmplus module normalizing its genesis for a zero
+// height genesis.
+type WasmPlusAppModule struct {
+	wasmplus.AppModule
+}
+
+// NewWasmPlusAppModule creates a new wasmplus AppModule.
+func NewWasmPlusAppModule(
+	cdc codec.Codec,
+	keeper *wasmpluskeeper.Keeper,
+	vs wasmkeeper.ValidatorSetSource,
+	ak wasmtypes.AccountKeeper,
+	bk simulation.BankKeeper,
+) WasmPlusAppModule {
+	return WasmPlusAppModule{
+		AppModule: wasmplus.NewAppModule(cdc, keeper, vs, ak, bk),
+	}
+}
+
+// ExportZeroHeightGenesis satisfies the Module interface. The inactive contract
+// addresses whose contract does not exist are dropped, as the import of the
+// genesis would fail on them.
+func (am WasmPlusAppModule) ExportZeroHeightGenesis(ctx sdk.Context, cdc codec.JSONCodec) (json.RawMessage, error) {
+	bz := am.ExportGenesis(ctx, cdc)
+
+	var genesis wasmplustypes.GenesisState
+	if err := cdc.UnmarshalJSON(bz, &genesis); err != nil {
+		return nil, err
+	}
+
+	contracts := make(map[string]bool, len(genesis.Contracts))
+	for _, contract := range genesis.Contracts {
+		contracts[contract.ContractAddress] = true
+	}
+
+	inactive := make([]string, 0, len(genesis.InactiveContractAddresses))
+	for _, addr := range genesis.InactiveContractAddresses {
+		if !contracts[addr] {
+			ctx.Logger().Info("dropped inactive contract which does not exist", "contract", addr)
+			continue
+		}
+		inactive = append(inactive, addr)
+	}
+	if len(inactive) == len(genesis.InactiveContractAddresses) {
+		return bz, nil
+	}
+	genesis.InactiveContractAddresses = inactive
+
+	return cdc.MarshalJSON(&genesis)
+}
@@ -0,0 +1,22 @@
+// Package zeroheight provides the app modules normalizing their exported
+// genesis for a zero height genesis, beyond the preparation of the state of
+// distribution, staking and slashing done by the app.
+package zeroheight
+
+import (
+	"encoding/json"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+// Module is an AppModule exporting its genesis normalized for a zero height
+// genesis. The block time of ctx is the time of the last block, or zero if
+// unknown.
+//
+// NOTE: token and collection keep no block heights nor times, so the modules
+// not implementing it, token and collection among them, are exported as they
+// are.
+type Module interface {
+	ExportZeroHeightGenesis(ctx sdk.Context, cdc codec.JSONCodec) (json.RawMessage, error)
+}
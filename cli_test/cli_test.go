@@ -53,6 +53,44 @@ func TestFnsadKeysAddMultisig(t *testing.T) {
 	require.NotEqual(t, f.KeysShow("msig3").Address, f.KeysShow("msig4").Address)
 }
 
+func TestFnsadKeysAddRecoverHDPath(t *testing.T) {
+	t.Parallel()
+	f := InitFixtures(t)
+	defer f.Cleanup()
+
+	// start fnsad server
+	n := f.FnsadStart(minGasPrice.String())
+	defer n.Stop()
+
+	mnemonic := "equip will roof matter pink blind book anxiety banner elbow sun young"
+
+	// the account and the index select the path of the key
+	f.KeysAddRecoverHDPath("default", mnemonic, 0, 0)
+	f.KeysAddRecoverHDPath("index", mnemonic, 0, 1)
+	require.NotEqual(t, f.KeyAddress("default"), f.KeyAddress("index"))
+
+	// an account derived under the legacy coin type
+	f.KeysAddRecoverHDPath("legacy", mnemonic, 0, 1, "--coin-type=118")
+	legacyAddr := f.KeyAddress("legacy")
+	require.NotEqual(t, f.KeyAddress("index"), legacyAddr)
+	f.KeysDelete("legacy")
+
+	sendTokens := sdk.TokensFromConsensusPower(10, sdk.DefaultPowerReduction)
+	_, err := f.TxSend(keyFoo, legacyAddr, sdk.NewCoin(denom, sendTokens), "-y")
+	require.NoError(t, err)
+	require.NoError(t, n.WaitForNextBlock())
+
+	// migrate-hd --dry-run only prints the paths holding funds
+	out, err := f.KeysMigrateHD("legacy", mnemonic, "--dry-run", fmt.Sprintf("--node=%s", f.RPCAddr))
+	require.NoError(t, err)
+	require.Contains(t, out.String(), legacyAddr.String())
+
+	// migrate-hd imports the keys of the paths holding funds
+	_, err = f.KeysMigrateHD("legacy", mnemonic, "-y", fmt.Sprintf("--node=%s", f.RPCAddr))
+	require.NoError(t, err)
+	require.Equal(t, legacyAddr, f.KeyAddress("legacy-118-0-1"))
+}
+
 func TestFnsadMinimumFees(t *testing.T) {
 	t.Parallel()
 	f := InitFixtures(t)
@@ -69,7 +107,7 @@ func TestFnsadMinimumFees(t *testing.T) {
 	)
 
 	n := f.FnsadStart(fees)
-	defer n.Cleanup()
+	defer n.Stop()
 
 	barAddr := f.KeyAddress(keyBar)
 
@@ -106,7 +144,7 @@ func TestFnsadGasPrices(t *testing.T) {
 	require.NoError(t, err)
 
 	n := f.FnsadStart(sdk.NewDecCoinFromDec(feeDenom, minGasPrice).String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	barAddr := f.KeyAddress(keyBar)
 
@@ -143,7 +181,7 @@ func TestFnsadFeesDeduction(t *testing.T) {
 	require.NoError(t, err)
 
 	n := f.FnsadStart(sdk.NewDecCoinFromDec(feeDenom, minGasPrice).String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	// Save key addresses for later use
 	fooAddr := f.KeyAddress(keyFoo)
@@ -199,7 +237,7 @@ func TestFnsadSend(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	// Save key addresses for later use
 	fooAddr := f.KeyAddress(keyFoo)
@@ -279,7 +317,7 @@ func TestFnsadGasAuto(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	barAddr := f.KeyAddress(keyBar)
@@ -339,7 +377,7 @@ func TestFnsadCreateValidator(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	barAddr := f.KeyAddress(keyBar)
 	barVal := sdk.ValAddress(barAddr)
@@ -430,7 +468,7 @@ func TestFnsadQuerySupply(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	totalSupplyOf := f.QueryTotalSupplyOf(fooDenom)
 
@@ -444,7 +482,7 @@ func TestFnsadSubmitProposal(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	f.QueryGovParamDeposit()
 	f.QueryGovParamVoting()
@@ -597,7 +635,7 @@ func TestFnsadSubmitParamChangeProposal(t *testing.T) {
 	defer f.Cleanup()
 
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	fooBal := f.QueryBalances(fooAddr)
@@ -675,7 +713,7 @@ func TestFnsadSubmitCommunityPoolSpendProposal(t *testing.T) {
 	require.NoError(t, genDoc.SaveAs(genFile))
 
 	n := f.FnsadStart("")
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	fooBal := f.QueryBalances(fooAddr)
@@ -734,7 +772,7 @@ func TestFnsadQueryTxPagination(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	barAddr := f.KeyAddress(keyBar)
@@ -788,7 +826,7 @@ func TestFnsadValidateSignatures(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart("")
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	barAddr := f.KeyAddress(keyBar)
@@ -835,7 +873,7 @@ func TestFnsadSendGenerateSignAndBroadcast(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	barAddr := f.KeyAddress(keyBar)
@@ -899,6 +937,53 @@ func TestFnsadSendGenerateSignAndBroadcast(t *testing.T) {
 	require.Equal(t, startTokens.Sub(sendTokens), fooBal.GetBalances().AmountOf(denom))
 }
 
+func TestFnsadTxOffline(t *testing.T) {
+	t.Parallel()
+	f := InitFixtures(t)
+	defer f.Cleanup()
+
+	// start fnsad server
+	n := f.FnsadStart(minGasPrice.String())
+	defer n.Stop()
+
+	fooAddr := f.KeyAddress(keyFoo)
+	barAddr := f.KeyAddress(keyBar)
+	fooAcc := f.QueryAccount(fooAddr)
+
+	sendTokens := sdk.TokensFromConsensusPower(10, sdk.DefaultPowerReduction)
+	templateFile := WriteToNewTempFile(t, fmt.Sprintf(`{
+  "messages": [{"@type": "/cosmos.bank.v1beta1.MsgSend", "from_address": "%s", "to_address": "%s", "amount": [{"denom": "%s", "amount": "%s"}]}],
+  "fee": {"gas_limit": 200000}
+}`, fooAddr, barAddr, denom, sendTokens))
+	defer os.Remove(templateFile.Name())
+
+	for _, signMode := range []string{flags.SignModeDirect, flags.SignModeLegacyAminoJSON} {
+		fooAcc = f.QueryAccount(fooAddr)
+
+		// build and sign without the node
+		out, err := f.TxOfflineBuild(keyFoo, templateFile.Name(), fooAcc.AccountNumber, fooAcc.Sequence, "--sign-mode="+signMode)
+		require.NoError(t, err)
+		requestFile := WriteToNewTempFile(t, out.String())
+		defer os.Remove(requestFile.Name())
+
+		out, err = f.TxOfflineVerify(requestFile.Name())
+		require.NoError(t, err)
+		require.Contains(t, out.String(), "Signer: "+fooAddr.String())
+
+		out, err = f.TxOfflineSign(keyFoo, requestFile.Name())
+		require.NoError(t, err)
+		signedTxFile := WriteToNewTempFile(t, out.String())
+		defer os.Remove(signedTxFile.Name())
+
+		_, err = f.TxBroadcast(signedTxFile.Name())
+		require.NoError(t, err)
+		require.NoError(t, n.WaitForNextBlock())
+	}
+
+	barBal := f.QueryBalances(barAddr)
+	require.Equal(t, sendTokens.MulRaw(2), barBal.GetBalances().AmountOf(denom))
+}
+
 func TestFnsadMultisignInsufficientCosigners(t *testing.T) {
 	t.Parallel()
 	f := InitFixtures(t)
@@ -906,7 +991,7 @@ func TestFnsadMultisignInsufficientCosigners(t *testing.T) {
 
 	// start fnsad server with minimum fees
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooBarBazAddr := f.KeyAddress(keyFooBarBaz)
 	barAddr := f.KeyAddress(keyBar)
@@ -960,7 +1045,7 @@ func TestFnsadEncode(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	cdc, _ := app.MakeCodecs()
 
@@ -997,7 +1082,7 @@ func TestFnsadMultisignSortSignatures(t *testing.T) {
 
 	// start fnsad server with minimum fees
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooBarBazAddr := f.KeyAddress(keyFooBarBaz)
 	barAddr := f.KeyAddress(keyBar)
@@ -1058,7 +1143,7 @@ func TestFnsadMultisign(t *testing.T) {
 
 	// start fnsad server with minimum fees
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooBarBazAddr := f.KeyAddress(keyFooBarBaz)
 	bazAddr := f.KeyAddress(keyBaz)
@@ -1112,6 +1197,63 @@ func TestFnsadMultisign(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestFnsadTxMultisig(t *testing.T) {
+	t.Parallel()
+	f := InitFixtures(t)
+	defer f.Cleanup()
+
+	// start fnsad server with minimum fees
+	n := f.FnsadStart(minGasPrice.String())
+	defer n.Stop()
+
+	fooBarBazAddr := f.KeyAddress(keyFooBarBaz)
+	bazAddr := f.KeyAddress(keyBaz)
+
+	_, err := f.TxSend(keyFoo, fooBarBazAddr, sdk.NewInt64Coin(denom, 10), "-y")
+	require.NoError(t, err)
+	require.NoError(t, n.WaitForNextBlock())
+
+	out, err := f.TxSend(fooBarBazAddr.String(), bazAddr, sdk.NewInt64Coin(denom, 10), "--generate-only")
+	require.NoError(t, err)
+	unsignedTxFile := WriteToNewTempFile(t, out.String())
+	defer os.Remove(unsignedTxFile.Name())
+
+	sessionFile := filepath.Join(t.TempDir(), "session.json")
+	_, err = f.TxMultisigCreate(unsignedTxFile.Name(), sessionFile)
+	require.NoError(t, err)
+
+	// foo signs the session, and bar adds its signature file
+	_, err = f.TxMultisigSign(keyFoo, sessionFile)
+	require.NoError(t, err)
+	_, err = f.TxMultisigSign(keyFoo, sessionFile)
+	require.Error(t, err)
+
+	out, err = f.TxMultisigStatus(sessionFile)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "1/2 signed")
+	_, err = f.TxMultisigBroadcast(sessionFile)
+	require.Error(t, err)
+
+	out, err = f.TxSign(keyBar, unsignedTxFile.Name(), "--multisig", fooBarBazAddr.String(), "--sign-mode=amino-json", "-y")
+	require.NoError(t, err)
+	barSignatureFile := WriteToNewTempFile(t, out.String())
+	defer os.Remove(barSignatureFile.Name())
+	_, err = f.TxMultisigAdd(sessionFile, []string{barSignatureFile.Name()})
+	require.NoError(t, err)
+
+	out, err = f.TxMultisigStatus(sessionFile)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Ready to broadcast")
+
+	_, err = f.TxMultisigBroadcast(sessionFile)
+	require.NoError(t, err)
+	require.NoError(t, n.WaitForNextBlock())
+
+	// the multisig sent all its tokens to baz
+	fooBarBazBal := f.QueryBalances(fooBarBazAddr)
+	require.True(t, fooBarBazBal.GetBalances().AmountOf(denom).IsZero())
+}
+
 func TestFnsadCollectGentxs(t *testing.T) {
 	t.Parallel()
 	var customMaxBytes, customMaxGas int64 = 99999999, 1234567
@@ -1161,7 +1303,7 @@ func TestValidateGenesis(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	f.ValidateGenesis(filepath.Join(f.Home, "config", "genesis.json"))
 }
@@ -1173,7 +1315,7 @@ func TestFnsadIncrementSequenceDecorator(t *testing.T) {
 
 	// start fnsad server
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 	barAddr := f.KeyAddress(keyBar)
@@ -1236,7 +1378,7 @@ func TestFnsadWasmContract(t *testing.T) {
 
 	// start fnsad server with minimum fees
 	n := f.FnsadStart(minGasPrice.String())
-	defer n.Cleanup()
+	defer n.Stop()
 
 	fooAddr := f.KeyAddress(keyFoo)
 
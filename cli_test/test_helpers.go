@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -63,6 +64,7 @@ import (
 	wasmtypes "github.com/Finschia/wasmd/x/wasm/types"
 
 	"github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/helpers"
 	fnsacmd "github.com/Finschia/finschia/cmd/fnsad/cmd"
 	fnsatypes "github.com/Finschia/finschia/types"
 )
@@ -344,6 +346,22 @@ func getCliCtx(f *Fixtures) client.Context {
 		WithClient(httpClient)
 }
 
+// execTestCLICmdWithInput is testcli.ExecTestCLICmd reading the given input from
+// the stdin of the command.
+func execTestCLICmdWithInput(clientCtx client.Context, cmd *cobra.Command, extraArgs []string, input string) (testutil.BufferWriter, error) {
+	cmd.SetArgs(extraArgs)
+
+	in, out := testutil.ApplyMockIO(cmd)
+	in.Reset(input)
+	clientCtx = clientCtx.WithInput(in).WithOutput(out)
+
+	ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
 // ___________________________________________________________________________________
 // fnsa
 
@@ -397,11 +415,28 @@ func (f *Fixtures) CollectGenTxs(flags ...string) {
 	require.NoError(f.T, err)
 }
 
-func (f *Fixtures) FnsadStart(minGasPrices string) *testnet.Network {
-	cfg := newTestnetConfig(f.T, f.GenesisState(), f.ChainID, minGasPrices)
-	n := testnet.NewWithoutInit(f.T, cfg, f.Home, []*testnet.Validator{newValidator(f, cfg, srvconfig.DefaultConfig(), server.NewDefaultContext())})
-	err := n.WaitForNextBlock()
+// FnsadStart starts the node of the fixtures in the current process, pointing
+// the fixtures to its RPC and gRPC, which are on ephemeral ports.
+func (f *Fixtures) FnsadStart(minGasPrices string) *helpers.Network {
+	logger, err := log.ParseLogLevel("info", log.NewOCLogger(log.NewSyncWriter(os.Stdout)), ostcfg.DefaultLogLevel)
+	require.NoError(f.T, err)
+
+	cfg := helpers.DefaultNetworkConfig()
+	cfg.MinGasPrices = minGasPrices
+	cfg.TimeoutCommit = 2 * time.Second
+	cfg.Logger = logger
+	n, err := helpers.NewNetworkFromHomes([]string{f.Home}, cfg)
+	require.NoError(f.T, err)
+
+	val := n.Validators[0]
+	rpcURL, err := url.Parse(val.RPCAddress)
+	require.NoError(f.T, err)
+	f.RPCAddr, f.Port = val.RPCAddress, rpcURL.Port()
+	_, grpcPort, err := net.SplitHostPort(val.GRPCAddress)
 	require.NoError(f.T, err)
+	f.GRPCAddr, f.GRPCPort = val.GRPCAddress, grpcPort
+
+	require.NoError(f.T, n.WaitForNextBlock())
 	return n
 }
 
@@ -451,14 +486,22 @@ func (f *Fixtures) KeysAddRecover(name, mnemonic string, flags ...string) (testu
 	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
 }
 
-// KeysAddRecoverHDPath prepares fnsad keys add --recover --account --index
+// KeysAddRecoverHDPath prepares fnsad keys add --recover --account --index,
+// entering the mnemonic at the prompt of the command
 func (f *Fixtures) KeysAddRecoverHDPath(name, mnemonic string, account uint32, index uint32, flags ...string) {
 	args := fmt.Sprintf("add --keyring-backend=test --recover %s --account=%d --index=%d", name, account, index)
 	cmd := clientkeys.Commands(f.Home)
-	_, err := testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+	_, err := execTestCLICmdWithInput(getCliCtx(f), cmd, addFlags(args, flags...), mnemonic+"\n")
 	require.NoError(f.T, err)
 }
 
+// KeysMigrateHD is fnsad keys migrate-hd
+func (f *Fixtures) KeysMigrateHD(name, mnemonic string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("migrate-hd --keyring-backend=test %s", name)
+	cmd := fnsacmd.KeysCommands(f.Home)
+	return execTestCLICmdWithInput(getCliCtx(f), cmd, addFlags(args, flags...), mnemonic+"\n")
+}
+
 // KeysShow is fnsad keys show
 func (f *Fixtures) KeysShow(name string, flags ...string) keyring.KeyOutput {
 	args := fmt.Sprintf("show --keyring-backend=test --keyring-dir=%s --output json %s", f.Home, name)
@@ -521,6 +564,63 @@ func (f *Fixtures) TxEncode(fileName string, flags ...string) (testutil.BufferWr
 	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
 }
 
+// TxOfflineBuild is fnsad tx offline build
+func (f *Fixtures) TxOfflineBuild(signer, templateFile string, accountNumber, sequence uint64, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("build --keyring-backend=test --from=%s --account-number=%d --sequence=%d %v",
+		signer, accountNumber, sequence, templateFile)
+	cmd := fnsacmd.OfflineCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxOfflineVerify is fnsad tx offline verify
+func (f *Fixtures) TxOfflineVerify(requestFile string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("verify %v", requestFile)
+	cmd := fnsacmd.OfflineCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxOfflineSign is fnsad tx offline sign
+func (f *Fixtures) TxOfflineSign(signer, requestFile string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("sign --keyring-backend=test --from=%s %v", signer, requestFile)
+	cmd := fnsacmd.OfflineCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxMultisigCreate is fnsad tx multisig create
+func (f *Fixtures) TxMultisigCreate(unsignedTxFile, sessionFile string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("create --keyring-backend=test %v %v --node=%s", unsignedTxFile, sessionFile, f.RPCAddr)
+	cmd := fnsacmd.MultisigCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxMultisigSign is fnsad tx multisig sign
+func (f *Fixtures) TxMultisigSign(signer, sessionFile string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("sign --keyring-backend=test --from=%s %v", signer, sessionFile)
+	cmd := fnsacmd.MultisigCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxMultisigAdd is fnsad tx multisig add
+func (f *Fixtures) TxMultisigAdd(sessionFile string, signatureFiles []string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("add %v %s", sessionFile, strings.Join(signatureFiles, " "))
+	cmd := fnsacmd.MultisigCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxMultisigStatus is fnsad tx multisig status
+func (f *Fixtures) TxMultisigStatus(sessionFile string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("status %v", sessionFile)
+	cmd := fnsacmd.MultisigCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
+// TxMultisigBroadcast is fnsad tx multisig broadcast
+func (f *Fixtures) TxMultisigBroadcast(sessionFile string, flags ...string) (testutil.BufferWriter, error) {
+	args := fmt.Sprintf("broadcast %v --node=%s", sessionFile, f.RPCAddr)
+	cmd := fnsacmd.MultisigCmd()
+	return testcli.ExecTestCLICmd(getCliCtx(f), cmd, addFlags(args, flags...))
+}
+
 // TxMultisign is fnsad tx multisign
 func (f *Fixtures) TxMultisign(fileName, name string, signaturesFiles []string,
 	flags ...string) (testutil.BufferWriter, error) {
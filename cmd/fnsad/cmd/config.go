@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	ostcli "github.com/Finschia/ostracon/libs/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client/config"
+	"github.com/Finschia/finschia-sdk/server"
+
+	"github.com/Finschia/finschia/app/indexevents"
+)
+
+const (
+	flagPreset   = "preset"
+	flagResolved = "resolved"
+)
+
+// configCmd returns the config command of the SDK, with the index-events
+// command listing the events to index.
+func configCmd() *cobra.Command {
+	cmd := config.Cmd()
+	cmd.AddCommand(indexEventsCmd())
+	return cmd
+}
+
+func indexEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index-events",
+		Short: "List the event types the modules can emit, to index by index-events of app.toml",
+		Long: `List the event types the modules of the app can emit with their attribute keys,
+or the keys of the attributes to index resolved from a preset or app.toml.
+
+The keys of index-events of app.toml and the presets are either {eventType}.{attributeKey},
+{eventType}.* for all the attributes of the event type, {module}/* for all the events
+of the module, or * for all the events. The preset of index_events.preset of app.toml,
+out of the built-in presets (minimal, exchange and explorer) and the ones defined by
+index_events.presets, is added to index-events.
+
+Example:
+$ fnsad config index-events
+$ fnsad config index-events --preset exchange
+$ fnsad config index-events --resolved
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			output, _ := cmd.Flags().GetString(ostcli.OutputFlag) // nolint: errcheck
+			preset, _ := cmd.Flags().GetString(flagPreset)        // nolint: errcheck
+			resolved, _ := cmd.Flags().GetBool(flagResolved)      // nolint: errcheck
+
+			if preset != "" || resolved {
+				var keys []string
+				var err error
+				if preset != "" {
+					var patterns []string
+					if patterns, err = indexevents.LookupPreset(serverCtx.Viper, preset); err == nil {
+						keys, err = indexevents.Resolve(patterns)
+					}
+				} else {
+					keys, err = indexevents.FromOptions(serverCtx.Viper)
+				}
+				if err != nil {
+					return err
+				}
+				if keys == nil {
+					// all the events are indexed
+					keys = []string{indexevents.Wildcard}
+				}
+				if output == "json" {
+					return printJSON(cmd, keys)
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), strings.Join(keys, "\n"))
+				return err
+			}
+
+			catalog, err := indexevents.Catalog()
+			if err != nil {
+				return err
+			}
+			if output == "json" {
+				return printJSON(cmd, catalog)
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "MODULE\tEVENT TYPE\tATTRIBUTES")
+			for _, event := range catalog {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", event.Module, event.Type, strings.Join(event.Attributes, ","))
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().String(flagPreset, "", "List the keys of the preset instead")
+	cmd.Flags().Bool(flagResolved, false, "List the keys resolved from index-events and index_events.preset of app.toml instead")
+	cmd.Flags().StringP(ostcli.OutputFlag, "o", "text", "Output format (text|json)")
+	return cmd
+}
+
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+	return err
+}
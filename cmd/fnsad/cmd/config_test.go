@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia/app/indexevents"
+)
+
+func TestIndexEventsCmd(t *testing.T) {
+	run := func(args ...string) string {
+		cmd := indexEventsCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+
+	out := run()
+	require.Contains(t, out, "MODULE")
+	require.Regexp(t, `\ntoken +lbm\.token\.v1\.EventSent +contract_id,operator,from,to,amount\n`, out)
+
+	var catalog []indexevents.Event
+	require.NoError(t, json.Unmarshal([]byte(run("--output", "json")), &catalog))
+	require.NotEmpty(t, catalog)
+
+	out = run("--preset", indexevents.PresetExchange)
+	require.Contains(t, out, "coin_spent.spender\n")
+	require.NotContains(t, out, "MODULE")
+	require.Equal(t, "*\n", run("--preset", indexevents.PresetExplorer))
+
+	// nothing configured indexes all the events
+	require.Equal(t, "*\n", run("--resolved"))
+
+	cmd := indexEventsCmd()
+	cmd.SetArgs([]string{"--preset", "nosuchpreset"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	require.Error(t, cmd.Execute())
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client/debug"
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	fnsatypes "github.com/Finschia/finschia/types"
+)
+
+// debugCmd returns the debug command of the SDK, with the addr command
+// converting between the mainnet and the testnet addresses, and the commands
+// inspecting the data of a node offline.
+func debugCmd(defaultNodeHome string) *cobra.Command {
+	cmd := debug.Cmd()
+	replaceCommand(cmd, addrCmd())
+	cmd.AddCommand(
+		invariantsCmd(defaultNodeHome),
+		stateCmd(defaultNodeHome),
+		appHashDiffCmd(defaultNodeHome),
+	)
+	return cmd
+}
+
+func addrCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "addr [address]",
+		Short: "Convert an address between hex, and the bech32 forms of the mainnet and the testnet",
+		Long: `Convert an address between hex encoding, and the bech32 forms of the account,
+validator and consensus addresses of the mainnet (link) and the testnet (tlink),
+regardless of --testnet.
+
+Example:
+$ fnsad debug addr link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982
+$ fnsad debug addr tlinkvaloper19wgf6ymq2ur6r59st95e04e49m69z4alvwpuft
+			`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := parseAnyAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			// the bytes, as the addr of the SDK does, since the bech32 form
+			// of an AccAddress depends on the sealed prefix
+			cmd.Println("Address:", []byte(bz))
+			cmd.Printf("Address (hex): %X\n", bz)
+			for _, testnet := range []bool{false, true} {
+				network := "Mainnet"
+				if testnet {
+					network = "Testnet"
+				}
+				for _, kind := range []fnsatypes.AddressKind{fnsatypes.AddressKindAcc, fnsatypes.AddressKindVal, fnsatypes.AddressKindCons} {
+					addr := fnsatypes.Address{Bytes: bz, Kind: kind, Testnet: testnet}
+					cmd.Printf("Bech32 %s %s: %s\n", network, addrKindLabels[kind], addr)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// parseAnyAddress parses an address in hex, or in bech32 of any kind of the
+// mainnet or the testnet.
+func parseAnyAddress(s string) (sdk.AccAddress, error) {
+	// try hex, then bech32
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		addr, err2 := fnsatypes.ParseAddress(s)
+		if err2 != nil {
+			return nil, fmt.Errorf("expected hex or bech32. Got errors: hex: %v, bech32: %v", err, err2)
+		}
+		return addr.Bytes, nil
+	}
+	if err := sdk.VerifyAddressFormat(bz); err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+var addrKindLabels = map[fnsatypes.AddressKind]string{
+	fnsatypes.AddressKindAcc:  "Acc",
+	fnsatypes.AddressKindVal:  "Val",
+	fnsatypes.AddressKindCons: "Cons",
+}
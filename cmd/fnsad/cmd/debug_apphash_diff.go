@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	ostcli "github.com/Finschia/ostracon/libs/cli"
+	"github.com/spf13/cobra"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/server"
+	"github.com/Finschia/finschia-sdk/store/rootmulti"
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+
+	"github.com/Finschia/finschia/app/inspect"
+)
+
+const (
+	flagOtherHome   = "other-home"
+	flagOtherHeight = "other-height"
+)
+
+// storeDiff is the hashes of a store in two states, and the keys differing
+// between them if the hashes do.
+type storeDiff struct {
+	Name  string         `json:"name"`
+	HashA string         `json:"hash_a"`
+	HashB string         `json:"hash_b"`
+	Diffs []inspect.Diff `json:"diffs,omitempty"`
+}
+
+// appHashDiff is the difference of the app hashes of two states.
+type appHashDiff struct {
+	HeightA  int64       `json:"height_a"`
+	HeightB  int64       `json:"height_b"`
+	AppHashA string      `json:"app_hash_a"`
+	AppHashB string      `json:"app_hash_b"`
+	Stores   []storeDiff `json:"stores"`
+}
+
+// appHashDiffCmd finds the keys differing between the states of two stopped
+// nodes, or two heights of one, whose app hashes diverge.
+func appHashDiffCmd(defaultNodeHome string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apphash-diff",
+		Short: "Find the keys differing between the states of two nodes or two heights",
+		Long: `Find the keys differing between the states of two stopped nodes, or two heights
+of a node, whose app hashes diverge. The application data is opened read-only.
+
+The hashes of the stores are compared by the commit info of the heights, and
+the IAVL trees of the stores whose hashes differ are descended into the
+subtrees whose hashes differ, down to the keys whose values differ. The values
+are decoded by the store decoders of the modules where they have ones.
+
+The state of --home at --height is A, and the one of --other-home at
+--other-height is B, which default to --home and --height respectively.
+
+Example:
+	fnsad debug apphash-diff --home node0 --other-home node1 --height 1000
+	fnsad debug apphash-diff --height 999 --other-height 1000 --limit 10
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			homeA, _ := cmd.Flags().GetString(flags.FlagHome)     // nolint: errcheck
+			heightA, _ := cmd.Flags().GetInt64(server.FlagHeight) // nolint: errcheck
+			homeB, _ := cmd.Flags().GetString(flagOtherHome)      // nolint: errcheck
+			heightB, _ := cmd.Flags().GetInt64(flagOtherHeight)   // nolint: errcheck
+			limit, _ := cmd.Flags().GetInt(flagLimit)             // nolint: errcheck
+			output, _ := cmd.Flags().GetString(ostcli.OutputFlag) // nolint: errcheck
+			if homeB == "" {
+				homeB = homeA
+			}
+			if !cmd.Flags().Changed(flagOtherHeight) {
+				heightB = heightA
+			}
+
+			dbA, err := openReadOnlyDB(homeA)
+			if err != nil {
+				return err
+			}
+			defer dbA.Close()
+			dbB := dbA
+			if homeB != homeA {
+				if dbB, err = openReadOnlyDB(homeB); err != nil {
+					return err
+				}
+				defer dbB.Close()
+			}
+			if heightA == -1 {
+				heightA = rootmulti.GetLatestVersion(dbA)
+			}
+			if heightB == -1 {
+				heightB = rootmulti.GetLatestVersion(dbB)
+			}
+			if homeA == homeB && heightA == heightB {
+				return fmt.Errorf("nothing to compare with height %d of %s; give --other-home or --other-height", heightA, homeA)
+			}
+
+			// the app is only for the keys of the stores and their decoders
+			linkApp, closeApp, err := newOfflineApp(serverCtx.Logger, dbm.NewMemDB())
+			if err != nil {
+				return err
+			}
+			defer closeApp()
+			inspector := inspect.NewInspector(nil, linkApp.KVStoreKeys(), linkApp.SimulationManager().StoreDecoders)
+
+			diff, err := diffAppHashes(inspector, dbA, heightA, dbB, heightB, limit)
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				return printJSON(cmd, diff)
+			}
+			return writeAppHashDiff(cmd.OutOrStdout(), diff)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagHome, defaultNodeHome, "The application home directory of the state A")
+	cmd.Flags().Int64(server.FlagHeight, -1, "The height of the state A (-1 means latest height)")
+	cmd.Flags().String(flagOtherHome, "", "The application home directory of the state B (--home if empty)")
+	cmd.Flags().Int64(flagOtherHeight, -1, "The height of the state B (--height if not given, -1 means latest height)")
+	cmd.Flags().Int(flagLimit, 100, "Maximum number of the keys differing to find per store (0 means no limit)")
+	cmd.Flags().StringP(ostcli.OutputFlag, "o", "text", "Output format (text|json)")
+
+	return cmd
+}
+
+// diffAppHashes compares the hashes of the stores of the inspector between the
+// height a of dbA and the height b of dbB, and finds up to limit keys differing
+// in each of the stores whose hashes differ.
+func diffAppHashes(inspector inspect.Inspector, dbA dbm.DB, a int64, dbB dbm.DB, b int64, limit int) (appHashDiff, error) {
+	diff := appHashDiff{HeightA: a, HeightB: b}
+
+	infoA, err := inspect.LoadCommitInfo(dbA, a)
+	if err != nil {
+		return diff, err
+	}
+	infoB, err := inspect.LoadCommitInfo(dbB, b)
+	if err != nil {
+		return diff, err
+	}
+	diff.AppHashA = fmt.Sprintf("%X", infoA.Hash())
+	diff.AppHashB = fmt.Sprintf("%X", infoB.Hash())
+
+	for _, name := range inspector.StoreNames() {
+		hashA, hashB := storeHash(infoA, name), storeHash(infoB, name)
+		store := storeDiff{Name: name, HashA: fmt.Sprintf("%X", hashA), HashB: fmt.Sprintf("%X", hashB)}
+		if !bytes.Equal(hashA, hashB) {
+			err := inspector.DiffStore(name, dbA, a, dbB, b, func(d inspect.Diff) bool {
+				store.Diffs = append(store.Diffs, d)
+				return limit > 0 && len(store.Diffs) >= limit
+			})
+			if err != nil {
+				return diff, err
+			}
+		}
+		diff.Stores = append(diff.Stores, store)
+	}
+	return diff, nil
+}
+
+func storeHash(info *storetypes.CommitInfo, name string) []byte {
+	for _, store := range info.StoreInfos {
+		if store.Name == name {
+			return store.GetHash()
+		}
+	}
+	return nil
+}
+
+// writeAppHashDiff writes the hashes of the stores as a table marking the ones
+// differing, followed by the keys differing with the values of both states.
+func writeAppHashDiff(w io.Writer, diff appHashDiff) error {
+	fmt.Fprintf(w, "A: height %d, app hash %s\n", diff.HeightA, diff.AppHashA)
+	fmt.Fprintf(w, "B: height %d, app hash %s\n", diff.HeightB, diff.AppHashB)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STORE\tHASH A\tHASH B\tDIFF")
+	for _, store := range diff.Stores {
+		mark := ""
+		if store.HashA != store.HashB {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", store.Name, store.HashA, store.HashB, mark)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, store := range diff.Stores {
+		for _, d := range store.Diffs {
+			fmt.Fprintf(w, "\n%s/%s\n", d.Store, d.Key)
+			for _, side := range []struct {
+				label string
+				entry *inspect.Entry
+			}{{"A", d.A}, {"B", d.B}} {
+				value := "(none)"
+				if side.entry != nil {
+					value = side.entry.Decoded
+					if value == "" {
+						value = side.entry.Value
+					}
+				}
+				if _, err := fmt.Fprintf(w, "%s:\n\t%s\n", side.label, strings.ReplaceAll(value, "\n", "\n\t")); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia/app/inspect"
+)
+
+func TestWriteAppHashDiff(t *testing.T) {
+	diff := appHashDiff{
+		HeightA:  10,
+		HeightB:  10,
+		AppHashA: "AA",
+		AppHashB: "BB",
+		Stores: []storeDiff{
+			{Name: "acc", HashA: "01", HashB: "01"},
+			{Name: "bank", HashA: "02", HashB: "03", Diffs: []inspect.Diff{
+				{Store: "bank", Key: "0201", A: &inspect.Entry{Value: "3130"}},
+				{Store: "bank", Key: "0202", A: &inspect.Entry{Value: "3130", Decoded: "10"}, B: &inspect.Entry{Value: "3230", Decoded: "20"}},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAppHashDiff(&buf, diff))
+	require.Equal(t, `A: height 10, app hash AA
+B: height 10, app hash BB
+STORE  HASH A  HASH B  DIFF
+acc    01      01      
+bank   02      03      *
+
+bank/0201
+A:
+	3130
+B:
+	(none)
+
+bank/0202
+A:
+	10
+B:
+	20
+`, buf.String())
+}
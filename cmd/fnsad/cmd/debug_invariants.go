@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	ostcli "github.com/Finschia/ostracon/libs/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/server"
+
+	"github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/inspect"
+	"github.com/Finschia/finschia/app/invariants"
+)
+
+const flagRoutes = "routes"
+
+// invariantsCmd checks the invariants registered to crisis against the data of
+// a node offline, at the latest height or a past one kept by the pruning.
+func invariantsCmd(defaultNodeHome string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invariants",
+		Short: "Check the invariants against the data of a node offline",
+		Long: `Check the invariants registered to crisis against the application data of a
+node offline, regardless of their periods, and report the broken ones without
+halting. The node must be stopped, and its data is opened read-only. It fails if
+any of the invariants is broken.
+
+Use --routes to check some of them, by their full routes ({module}/{route}) or
+their modules.
+
+Example:
+	fnsad debug invariants
+	fnsad debug invariants --height 1000 --routes token,collection/ownership
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			routes, _ := cmd.Flags().GetStringSlice(flagRoutes)   // nolint: errcheck
+			output, _ := cmd.Flags().GetString(ostcli.OutputFlag) // nolint: errcheck
+
+			var results []invariants.Result
+			err := withInspectApp(cmd, func(linkApp *app.LinkApp, _ inspect.Inspector) (err error) {
+				if results, err = linkApp.CheckInvariants(routes); err != nil {
+					return err
+				}
+				if output == "json" {
+					return printJSON(cmd, results)
+				}
+				return writeInvariantResults(cmd.OutOrStdout(), linkApp.LastBlockHeight(), results)
+			})
+			if err != nil {
+				return err
+			}
+
+			broken := 0
+			for _, result := range results {
+				if result.Broken {
+					broken++
+				}
+			}
+			if broken != 0 {
+				return fmt.Errorf("%d of %d invariants broken", broken, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flags.FlagHome, defaultNodeHome, "The application home directory")
+	cmd.Flags().Int64(server.FlagHeight, -1, "Check the state of a particular height (-1 means latest height)")
+	cmd.Flags().StringSlice(flagRoutes, []string{}, "Comma-separated list of the invariants to check by their routes or modules (all invariants if empty)")
+	cmd.Flags().StringP(ostcli.OutputFlag, "o", "text", "Output format (text|json)")
+
+	return cmd
+}
+
+// writeInvariantResults writes the results as a table, followed by the
+// messages of the broken invariants.
+func writeInvariantResults(w io.Writer, height int64, results []invariants.Result) error {
+	if _, err := fmt.Fprintf(w, "height: %d\n", height); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUTE\tSTATUS\tDURATION")
+	for _, result := range results {
+		status := "ok"
+		if result.Broken {
+			status = "BROKEN"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Route, status, result.Duration)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if result.Broken {
+			if _, err := fmt.Fprintf(w, "\n%s:\n%s\n", result.Route, result.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia/app/invariants"
+)
+
+func TestWriteInvariantResults(t *testing.T) {
+	results := []invariants.Result{
+		{Route: "bank/total-supply", Duration: 3 * time.Millisecond},
+		{Route: "token/supply", Broken: true, Message: "token: supply invariant\nbalances of unknown contract 9be17165; 10\n", Duration: time.Second},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeInvariantResults(&buf, 42, results))
+	require.Equal(t, `height: 42
+ROUTE              STATUS  DURATION
+bank/total-supply  ok      3ms
+token/supply       BROKEN  1s
+
+token/supply:
+token: supply invariant
+balances of unknown contract 9be17165; 10
+
+`, buf.String())
+}
+
+func TestInvariantsCmd(t *testing.T) {
+	home := newTestHome(t)
+
+	cmd := invariantsCmd(home)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--routes", "bank,token", "--output", "json"})
+	require.NoError(t, cmd.ExecuteContext(context.Background()))
+
+	var results []invariants.Result
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &results))
+	require.NotEmpty(t, results)
+	for _, result := range results {
+		require.False(t, result.Broken, result.Message)
+	}
+
+	cmd = invariantsCmd(home)
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--routes", "unknown"})
+	require.Error(t, cmd.ExecuteContext(context.Background()))
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	ostcli "github.com/Finschia/ostracon/libs/cli"
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/baseapp"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/server"
+	"github.com/Finschia/finschia-sdk/store/rootmulti"
+
+	"github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/inspect"
+)
+
+const (
+	flagPrefix = "prefix"
+	flagLimit  = "limit"
+	flagStores = "stores"
+)
+
+// stateCmd inspects the state of a stopped node at a height, opening its data
+// read-only.
+func stateCmd(defaultNodeHome string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect the state of a stopped node offline",
+		Long: `Inspect the state of a stopped node offline, at the latest height or a past one
+kept by the pruning. The application data is opened read-only, and the values
+of the stores are decoded by the store decoders of the modules where they have
+ones.`,
+	}
+
+	cmd.PersistentFlags().String(flags.FlagHome, defaultNodeHome, "The application home directory")
+	cmd.PersistentFlags().Int64(server.FlagHeight, -1, "Inspect the state of a particular height (-1 means latest height)")
+	cmd.PersistentFlags().StringP(ostcli.OutputFlag, "o", "text", "Output format (text|json)")
+
+	cmd.AddCommand(
+		stateStoresCmd(),
+		stateDumpCmd(),
+		stateAccountCmd(),
+	)
+	return cmd
+}
+
+func stateStoresCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stores",
+		Short: "List the stores with their hashes at the height",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withInspectApp(cmd, func(linkApp *app.LinkApp, inspector inspect.Inspector) error {
+				type store struct {
+					Name    string `json:"name"`
+					Hash    string `json:"hash"`
+					Decoder bool   `json:"decoder"`
+				}
+				keys := linkApp.KVStoreKeys()
+				var stores []store
+				for _, name := range inspector.StoreNames() {
+					commitID := linkApp.CommitMultiStore().GetCommitKVStore(keys[name]).LastCommitID()
+					stores = append(stores, store{
+						Name:    name,
+						Hash:    fmt.Sprintf("%X", commitID.Hash),
+						Decoder: inspector.HasDecoder(name),
+					})
+				}
+
+				if output, _ := cmd.Flags().GetString(ostcli.OutputFlag); output == "json" { // nolint: errcheck
+					return printJSON(cmd, stores)
+				}
+				w := cmd.OutOrStdout()
+				fmt.Fprintf(w, "height: %d\n", linkApp.LastBlockHeight())
+				tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "STORE\tHASH\tDECODER")
+				for _, s := range stores {
+					fmt.Fprintf(tw, "%s\t%s\t%t\n", s.Name, s.Hash, s.Decoder)
+				}
+				return tw.Flush()
+			})
+		},
+	}
+}
+
+func stateDumpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump [store]",
+		Short: "Dump the entries of a store at the height",
+		Long: `Dump the entries of a store at the height in the order of their keys, with the
+values decoded where the store has a decoder. The keys and the undecoded values
+are in hex.
+
+Example:
+	fnsad debug state dump bank --prefix 02 --limit 10
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefixString, _ := cmd.Flags().GetString(flagPrefix) // nolint: errcheck
+			limit, _ := cmd.Flags().GetInt(flagLimit)            // nolint: errcheck
+
+			prefix, err := hex.DecodeString(prefixString)
+			if err != nil {
+				return fmt.Errorf("invalid prefix: %w", err)
+			}
+
+			return withInspectApp(cmd, func(_ *app.LinkApp, inspector inspect.Inspector) error {
+				var entries []inspect.Entry
+				err := inspector.Iterate(args[0], prefix, func(entry inspect.Entry) bool {
+					entries = append(entries, entry)
+					return limit > 0 && len(entries) >= limit
+				})
+				if err != nil {
+					return err
+				}
+				return printEntries(cmd, entries)
+			})
+		},
+	}
+
+	cmd.Flags().String(flagPrefix, "", "Dump the entries whose keys have the prefix in hex")
+	cmd.Flags().Int(flagLimit, 0, "Maximum number of the entries to dump (0 means no limit)")
+	return cmd
+}
+
+func stateAccountCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account [address]",
+		Short: "Dump the state of an account across the modules at the height",
+		Long: `Dump the state of an account across the modules at the height, which is the
+entries of the stores whose keys hold the address, either in bytes or in bech32.
+The address may be in hex or bech32 of the mainnet or the testnet. All the
+stores are searched, which takes a while on a large state, unless --stores is
+given.
+
+Example:
+	fnsad debug state account link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982 --stores acc,bank,staking
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stores, _ := cmd.Flags().GetStringSlice(flagStores) // nolint: errcheck
+
+			addr, err := parseAnyAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			return withInspectApp(cmd, func(_ *app.LinkApp, inspector inspect.Inspector) error {
+				var entries []inspect.Entry
+				err := inspector.Account(addr, stores, func(entry inspect.Entry) bool {
+					entries = append(entries, entry)
+					return false
+				})
+				if err != nil {
+					return err
+				}
+				return printEntries(cmd, entries)
+			})
+		},
+	}
+
+	cmd.Flags().StringSlice(flagStores, []string{}, "Comma-separated list of the stores to search (all stores if empty)")
+	return cmd
+}
+
+// withInspectApp calls fn with the app of the home of the flags loaded at the
+// height of the flags read-only, and an inspector of its state.
+func withInspectApp(cmd *cobra.Command, fn func(linkApp *app.LinkApp, inspector inspect.Inspector) error) error {
+	serverCtx := server.GetServerContextFromCmd(cmd)
+	homeDir, _ := cmd.Flags().GetString(flags.FlagHome)  // nolint: errcheck
+	height, _ := cmd.Flags().GetInt64(server.FlagHeight) // nolint: errcheck
+
+	db, err := openReadOnlyDB(homeDir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	linkApp, closeApp, err := newInspectApp(serverCtx.Logger, db, homeDir, height)
+	if err != nil {
+		return err
+	}
+	defer closeApp()
+	return fn(linkApp, newInspector(linkApp))
+}
+
+// openReadOnlyDB opens the application data of the home read-only.
+func openReadOnlyDB(homeDir string) (dbm.DB, error) {
+	return dbm.NewGoLevelDBWithOpts("application", filepath.Join(homeDir, "data"), &opt.Options{ReadOnly: true})
+}
+
+// newInspectApp creates a LinkApp of the home on db loaded at the given height
+// (-1 means latest height). See newOfflineApp.
+func newInspectApp(logger log.Logger, db dbm.DB, homeDir string, height int64) (*app.LinkApp, func(), error) {
+	if height == -1 {
+		height = rootmulti.GetLatestVersion(db)
+	}
+	if height <= 0 {
+		return nil, nil, fmt.Errorf("no state at height %d in %s", height, homeDir)
+	}
+
+	linkApp, closeApp, err := newOfflineApp(logger, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := linkApp.LoadHeight(height); err != nil {
+		closeApp()
+		return nil, nil, err
+	}
+	return linkApp, closeApp, nil
+}
+
+// newOfflineApp creates a LinkApp on db which writes neither db nor the files of
+// the node, to be closed by the returned function:
+//   - it has none of the app options of app.toml, so that it starts none of the
+//     services writing the data of the node.
+//   - its home, where wasm keeps its cache, is a temporary directory.
+//   - the IAVL fast nodes are disabled, as loading a tree without them would
+//     migrate it.
+func newOfflineApp(logger log.Logger, db dbm.DB) (*app.LinkApp, func(), error) {
+	homeDir, err := os.MkdirTemp("", "fnsad-debug-")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	appOpts := inspectAppOptions{flags.FlagHome: homeDir}
+	linkApp := app.NewLinkApp(logger, db, nil, false, map[int64]bool{}, homeDir, 0, app.MakeEncodingConfig(), appOpts, nil,
+		baseapp.SetIAVLDisableFastNode(true),
+	)
+	return linkApp, func() { os.RemoveAll(homeDir) }, nil
+}
+
+// newInspector returns an inspector of the state loaded by the app.
+func newInspector(linkApp *app.LinkApp) inspect.Inspector {
+	return inspect.NewInspector(linkApp.CommitMultiStore().CacheMultiStore(), linkApp.KVStoreKeys(), linkApp.SimulationManager().StoreDecoders)
+}
+
+type inspectAppOptions map[string]interface{}
+
+func (o inspectAppOptions) Get(key string) interface{} { return o[key] }
+
+// printEntries prints the entries in the output format of the flags.
+func printEntries(cmd *cobra.Command, entries []inspect.Entry) error {
+	if output, _ := cmd.Flags().GetString(ostcli.OutputFlag); output == "json" { // nolint: errcheck
+		if entries == nil {
+			entries = []inspect.Entry{}
+		}
+		return printJSON(cmd, entries)
+	}
+	return writeEntries(cmd.OutOrStdout(), entries)
+}
+
+// writeEntries writes the entries by their stores and keys, followed by their
+// values indented, decoded or in hex.
+func writeEntries(w io.Writer, entries []inspect.Entry) error {
+	for _, entry := range entries {
+		value := entry.Decoded
+		if value == "" {
+			value = entry.Value
+		}
+		value = "\t" + strings.ReplaceAll(value, "\n", "\n\t")
+		if _, err := fmt.Fprintf(w, "%s/%s\n%s\n", entry.Store, entry.Key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
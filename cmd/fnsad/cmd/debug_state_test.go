@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia-sdk/baseapp"
+
+	"github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/helpers"
+	"github.com/Finschia/finschia/app/inspect"
+)
+
+// newTestHome returns a home with the application data of a chain committed at
+// height 1 from the default genesis, without IAVL fast nodes.
+func newTestHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	db, err := dbm.NewGoLevelDB("application", filepath.Join(home, "data"))
+	require.NoError(t, err)
+	encCfg := app.MakeEncodingConfig()
+	linkApp := app.NewLinkApp(log.NewNopLogger(), db, nil, true, map[int64]bool{}, t.TempDir(), 0, encCfg, helpers.EmptyAppOptions{}, nil,
+		baseapp.SetIAVLDisableFastNode(true),
+	)
+	genesis, err := json.Marshal(app.NewDefaultGenesisState(encCfg.Marshaler))
+	require.NoError(t, err)
+	linkApp.InitChain(abci.RequestInitChain{ConsensusParams: helpers.DefaultConsensusParams, AppStateBytes: genesis})
+	linkApp.Commit()
+	require.NoError(t, db.Close())
+	return home
+}
+
+func TestNewInspectApp(t *testing.T) {
+	home := newTestHome(t)
+
+	db, err := openReadOnlyDB(home)
+	require.NoError(t, err)
+	defer db.Close()
+
+	linkApp, closeApp, err := newInspectApp(log.NewNopLogger(), db, home, -1)
+	require.NoError(t, err)
+	defer closeApp()
+	require.Equal(t, int64(1), linkApp.LastBlockHeight())
+
+	entries := 0
+	err = newInspector(linkApp).Iterate("params", nil, func(inspect.Entry) bool {
+		entries++
+		return false
+	})
+	require.NoError(t, err)
+	require.NotZero(t, entries)
+
+	// nothing but the application data is in the home
+	files, err := os.ReadDir(home)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "data", files[0].Name())
+
+	_, _, err = newInspectApp(log.NewNopLogger(), db, home, 2)
+	require.Error(t, err)
+}
+
+func TestWriteEntries(t *testing.T) {
+	entries := []inspect.Entry{
+		{Store: "acc", Key: "01AB", Value: "0A01", Decoded: "address: link1\nsequence: 0"},
+		{Store: "bank", Key: "02AB", Value: "3130"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeEntries(&buf, entries))
+	require.Equal(t, "acc/01AB\n\taddress: link1\n\tsequence: 0\nbank/02AB\n\t3130\n", buf.String())
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+const debugAddrHex = "2B909D13605707A1D0B0596997D7352EF45157BF"
+
+func TestAddrCmd(t *testing.T) {
+	testCases := map[string]struct {
+		addr  string
+		valid bool
+	}{
+		"hex": {
+			addr:  debugAddrHex,
+			valid: true,
+		},
+		"mainnet account": {
+			addr:  "link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982",
+			valid: true,
+		},
+		"testnet validator": {
+			addr:  "tlinkvaloper19wgf6ymq2ur6r59st95e04e49m69z4alvwpuft",
+			valid: true,
+		},
+		"unknown prefix": {
+			addr: "cosmos19wgf6ymq2ur6r59st95e04e49m69z4al0hhjy3",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cmd := addrCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&out)
+			cmd.SetArgs([]string{tc.addr})
+
+			err := cmd.Execute()
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, out.String(), addrBytesLine(t))
+			require.Contains(t, out.String(), "Address (hex): "+debugAddrHex+"\n")
+			require.Contains(t, out.String(), "Bech32 Mainnet Acc: link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982\n")
+			require.Contains(t, out.String(), "Bech32 Testnet Acc: tlink19wgf6ymq2ur6r59st95e04e49m69z4al37f470\n")
+			require.Contains(t, out.String(), "Bech32 Testnet Val: tlinkvaloper19wgf6ymq2ur6r59st95e04e49m69z4alvwpuft\n")
+		})
+	}
+}
+
+func TestAddrCmdNonDefaultPrefix(t *testing.T) {
+	config := sdk.GetConfig()
+	accPrefix, accPubPrefix := config.GetBech32AccountAddrPrefix(), config.GetBech32AccountPubPrefix()
+	config.SetBech32PrefixForAccount("cosmos", "cosmospub")
+	t.Cleanup(func() { config.SetBech32PrefixForAccount(accPrefix, accPubPrefix) })
+
+	cmd := addrCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982"})
+	require.NoError(t, cmd.Execute())
+
+	require.Contains(t, out.String(), addrBytesLine(t))
+	require.Contains(t, out.String(), "Bech32 Mainnet Acc: link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982\n")
+	require.Contains(t, out.String(), "Bech32 Testnet Acc: tlink19wgf6ymq2ur6r59st95e04e49m69z4al37f470\n")
+	require.NotContains(t, out.String(), "cosmos")
+}
+
+// addrBytesLine returns the line of the address in bytes, as printed by the
+// addr command of the SDK.
+func addrBytesLine(t *testing.T) string {
+	bz, err := hex.DecodeString(debugAddrHex)
+	require.NoError(t, err)
+	return fmt.Sprintf("Address: %v\n", bz)
+}
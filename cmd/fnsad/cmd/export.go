@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	ostjson "github.com/Finschia/ostracon/libs/json"
+	octypes "github.com/Finschia/ostracon/types"
+	"github.com/spf13/cobra"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/server"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+const (
+	flagModules         = "modules"
+	flagExcludeModules  = "exclude-modules"
+	flagOutputDocument  = "output-document"
+	flagTraceStore      = "trace-store"
+	appStateGenesisName = "app_state"
+)
+
+// exportCmd dumps app state to JSON. It replaces the export command of the sdk
+// server, adding module filtering and writing the app state module by module,
+// so that large states do not have to fit in memory at once.
+func exportCmd(defaultNodeHome string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export state to JSON",
+		Long: `Export state to JSON.
+
+The state of the modules is exported one by one and streamed to the output, so
+that it never has to be held in memory as a whole. Use --modules or
+--exclude-modules to export a subset of the modules.
+
+Example:
+	fnsad export --modules bank,wasm,collection --output-document genesis.json
+	fnsad export --height 1000 --exclude-modules wasm
+`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			config := serverCtx.Config
+
+			homeDir, _ := cmd.Flags().GetString(flags.FlagHome)
+			config.SetRoot(homeDir)
+
+			if _, err := os.Stat(config.GenesisFile()); os.IsNotExist(err) {
+				return err
+			}
+
+			height, _ := cmd.Flags().GetInt64(server.FlagHeight)                           // nolint: errcheck
+			forZeroHeight, _ := cmd.Flags().GetBool(server.FlagForZeroHeight)              // nolint: errcheck
+			jailAllowedAddrs, _ := cmd.Flags().GetStringSlice(server.FlagJailAllowedAddrs) // nolint: errcheck
+			modules, _ := cmd.Flags().GetStringSlice(flagModules)                          // nolint: errcheck
+			excludeModules, _ := cmd.Flags().GetStringSlice(flagExcludeModules)            // nolint: errcheck
+			outputDocument, _ := cmd.Flags().GetString(flagOutputDocument)                 // nolint: errcheck
+			traceWriterFile, _ := cmd.Flags().GetString(flagTraceStore)                    // nolint: errcheck
+
+			db, err := sdk.NewLevelDB("application", filepath.Join(config.RootDir, "data"))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			traceWriter, err := openTraceWriter(traceWriterFile)
+			if err != nil {
+				return err
+			}
+			if traceWriter != nil {
+				defer traceWriter.Close()
+			}
+
+			linkApp, err := newExportApp(serverCtx.Logger, db, traceWriter, height, serverCtx.Viper)
+			if err != nil {
+				return err
+			}
+
+			doc, err := octypes.GenesisDocFromFile(config.GenesisFile())
+			if err != nil {
+				return err
+			}
+
+			export := func(w io.Writer) error {
+				return writeGenesisDoc(w, doc, func(w io.Writer) (servertypes.ExportedApp, error) {
+					exported, err := linkApp.StreamAppStateAndValidators(w, forZeroHeight, jailAllowedAddrs, modules, excludeModules)
+					if err != nil {
+						return servertypes.ExportedApp{}, fmt.Errorf("error exporting state: %v", err)
+					}
+					return exported, nil
+				})
+			}
+			if outputDocument == "" {
+				return export(cmd.OutOrStdout())
+			}
+			return writeFileAtomically(outputDocument, export)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagHome, defaultNodeHome, "The application home directory")
+	cmd.Flags().Int64(server.FlagHeight, -1, "Export state from a particular height (-1 means latest height)")
+	cmd.Flags().Bool(server.FlagForZeroHeight, false, "Export state to start at height zero (perform preproccessing)")
+	cmd.Flags().StringSlice(server.FlagJailAllowedAddrs, []string{}, "Comma-separated list of operator addresses of jailed validators to unjail")
+	cmd.Flags().StringSlice(flagModules, []string{}, "Comma-separated list of modules to export (all modules if empty)")
+	cmd.Flags().StringSlice(flagExcludeModules, []string{}, "Comma-separated list of modules not to export")
+	cmd.Flags().String(flagOutputDocument, "", "Write the exported genesis to the given file instead of stdout")
+	cmd.Flags().String(flagTraceStore, "", "Enable KVStore tracing to an output file")
+
+	return cmd
+}
+
+// writeGenesisDoc writes doc to w as compact JSON with sorted keys, replacing its
+// app state by the one streamed by exportAppState, and its validators, initial
+// height and consensus params by the exported ones.
+func writeGenesisDoc(w io.Writer, doc *octypes.GenesisDoc, exportAppState func(io.Writer) (servertypes.ExportedApp, error)) error {
+	// the fields of the document sorted before the app state are not touched by
+	// the export, so they can be written before the app state is streamed.
+	before, err := genesisDocFields(doc)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(before) {
+		if key >= appStateGenesisName {
+			break
+		}
+		if err := writeGenesisDocField(w, key, before[key]); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%q:", appStateGenesisName); err != nil {
+		return err
+	}
+	exported, err := exportAppState(w)
+	if err != nil {
+		return err
+	}
+
+	doc.Validators = exported.Validators
+	doc.InitialHeight = exported.Height
+	doc.ConsensusParams = &tmproto.ConsensusParams{
+		Block: tmproto.BlockParams{
+			MaxBytes:   exported.ConsensusParams.Block.MaxBytes,
+			MaxGas:     exported.ConsensusParams.Block.MaxGas,
+			TimeIotaMs: doc.ConsensusParams.Block.TimeIotaMs,
+		},
+		Evidence: tmproto.EvidenceParams{
+			MaxAgeNumBlocks: exported.ConsensusParams.Evidence.MaxAgeNumBlocks,
+			MaxAgeDuration:  exported.ConsensusParams.Evidence.MaxAgeDuration,
+			MaxBytes:        exported.ConsensusParams.Evidence.MaxBytes,
+		},
+		Validator: tmproto.ValidatorParams{
+			PubKeyTypes: exported.ConsensusParams.Validator.PubKeyTypes,
+		},
+	}
+
+	after, err := genesisDocFields(doc)
+	if err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(after) {
+		if key <= appStateGenesisName {
+			continue
+		}
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+		if err := writeGenesisDocField(w, key, after[key]); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "}\n")
+	return err
+}
+
+// genesisDocFields returns the fields of doc, except for the app state, as sorted
+// JSON values keyed by field name.
+func genesisDocFields(doc *octypes.GenesisDoc) (map[string]json.RawMessage, error) {
+	// NOTE: Tendermint uses a custom JSON encoder for GenesisDoc.
+	withoutAppState := *doc
+	withoutAppState.AppState = nil
+	bz, err := ostjson.Marshal(withoutAppState)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, appStateGenesisName)
+
+	for key, value := range fields {
+		if fields[key], err = sdk.SortJSON(value); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+func writeGenesisDocField(w io.Writer, key string, value json.RawMessage) error {
+	if _, err := fmt.Fprintf(w, "%q:", key); err != nil {
+		return err
+	}
+	_, err := w.Write(value)
+	return err
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeFileAtomically writes the file at path with write, through a temporary
+// file renamed to path once write succeeded, so that a failed write never leaves
+// a truncated file behind.
+func writeFileAtomically(path string, write func(io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if err = write(f); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func openTraceWriter(traceWriterFile string) (w io.WriteCloser, err error) {
+	if traceWriterFile == "" {
+		return
+	}
+	return os.OpenFile(
+		traceWriterFile,
+		os.O_WRONLY|os.O_APPEND|os.O_CREATE,
+		0o666,
+	)
+}
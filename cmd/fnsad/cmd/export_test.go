@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ostjson "github.com/Finschia/ostracon/libs/json"
+	octypes "github.com/Finschia/ostracon/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+func TestWriteGenesisDoc(t *testing.T) {
+	appState := []byte(`{"bank":{"supply":[],"balances":[]},"auth":null}`)
+	exported := servertypes.ExportedApp{
+		Height: 42,
+		ConsensusParams: &abci.ConsensusParams{
+			Block:     &abci.BlockParams{MaxBytes: 200000, MaxGas: 2000000},
+			Evidence:  &tmproto.EvidenceParams{MaxAgeNumBlocks: 302400, MaxAgeDuration: 504 * time.Hour, MaxBytes: 10000},
+			Validator: &tmproto.ValidatorParams{PubKeyTypes: []string{octypes.ABCIPubKeyTypeEd25519}},
+		},
+	}
+
+	newDoc := func() *octypes.GenesisDoc {
+		doc := &octypes.GenesisDoc{
+			GenesisTime: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			ChainID:     "finschia",
+		}
+		require.NoError(t, doc.ValidateAndComplete())
+		return doc
+	}
+
+	var buf bytes.Buffer
+	err := writeGenesisDoc(&buf, newDoc(), func(w io.Writer) (servertypes.ExportedApp, error) {
+		_, err := w.Write(sdk.MustSortJSON(appState))
+		return exported, err
+	})
+	require.NoError(t, err)
+
+	// the result must be the same as the one of the sdk export command
+	expected := newDoc()
+	expected.AppState = appState
+	expected.InitialHeight = exported.Height
+	expected.ConsensusParams = &tmproto.ConsensusParams{
+		Block:     tmproto.BlockParams{MaxBytes: 200000, MaxGas: 2000000, TimeIotaMs: expected.ConsensusParams.Block.TimeIotaMs},
+		Evidence:  *exported.ConsensusParams.Evidence,
+		Validator: *exported.ConsensusParams.Validator,
+	}
+	encoded, err := ostjson.Marshal(expected)
+	require.NoError(t, err)
+	require.Equal(t, string(sdk.MustSortJSON(encoded))+"\n", buf.String())
+}
+
+func TestWriteFileAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "genesis.json")
+	require.NoError(t, os.WriteFile(path, []byte("previous"), 0o600))
+
+	// a failed write keeps the previous file and leaves no temporary file
+	err := writeFileAtomically(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "trunc")
+		require.NoError(t, err)
+		return errors.New("export failed")
+	})
+	require.EqualError(t, err, "export failed")
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "previous", string(bz))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	err = writeFileAtomically(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "exported")
+		return err
+	})
+	require.NoError(t, err)
+	bz, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "exported", string(bz))
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/client/input"
+	"github.com/Finschia/finschia-sdk/client/keys"
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
+	"github.com/Finschia/finschia-sdk/crypto/ledger"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/cosmos/go-bip39"
+
+	fnsatypes "github.com/Finschia/finschia/types"
+)
+
+const (
+	flagCoinTypes = "coin-types"
+	flagAccounts  = "accounts"
+	flagIndexes   = "indexes"
+	flagDryRun    = "dry-run"
+
+	// legacyCoinType is the coin type of the accounts derived by the tools of
+	// Cosmos, which LINK accounts were also derived under.
+	legacyCoinType = 118
+)
+
+// KeysCommands returns the keys commands of the SDK with migrate-hd.
+func KeysCommands(defaultNodeHome string) *cobra.Command {
+	cmd := keys.Commands(defaultNodeHome)
+	cmd.AddCommand(migrateHDCmd())
+	return cmd
+}
+
+func migrateHDCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-hd [name]",
+		Short: "Import the keys holding funds under the HD paths other than the standard one of a mnemonic",
+		Long: fmt.Sprintf(`Derive the accounts of a mnemonic under the HD paths of the given coin types,
+accounts and indexes, query their balances, and import the keys holding funds
+under paths other than the standard m/44'/%[1]d'/0'/0/0, e.g. legacy accounts
+derived under the coin type %[2]d. The keys are named [name]-<coin type>-<account>-<index>.
+
+With --ledger, the keys are derived on a Ledger device instead, and references
+to them are stored. The app of the device may require its expert mode to derive
+the keys of coin types other than its own.
+
+The funds of such keys are not found by the tools deriving the standard path
+only, so that they should be moved to an account of the standard path.
+
+Example:
+$ fnsad keys migrate-hd legacy --coin-types %[1]d,%[2]d --indexes 5 --node tcp://localhost:26657
+$ fnsad keys migrate-hd legacy --ledger --coin-types %[2]d
+`, fnsatypes.CoinType, legacyCoinType),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			coinTypes, _ := cmd.Flags().GetUintSlice(flagCoinTypes)           // nolint: errcheck
+			accounts, _ := cmd.Flags().GetUint32(flagAccounts)                // nolint: errcheck
+			indexes, _ := cmd.Flags().GetUint32(flagIndexes)                  // nolint: errcheck
+			dryRun, _ := cmd.Flags().GetBool(flagDryRun)                      // nolint: errcheck
+			skipConfirm, _ := cmd.Flags().GetBool(flags.FlagSkipConfirmation) // nolint: errcheck
+			algoStr, _ := cmd.Flags().GetString(flags.FlagKeyAlgorithm)       // nolint: errcheck
+			useLedger, _ := cmd.Flags().GetBool(flags.FlagUseLedger)          // nolint: errcheck
+
+			keyringAlgos, ledgerAlgos := clientCtx.Keyring.SupportedAlgorithms()
+			if useLedger {
+				keyringAlgos = ledgerAlgos
+			}
+			algo, err := keyring.NewSigningAlgoFromString(algoStr, keyringAlgos)
+			if err != nil {
+				return err
+			}
+
+			inBuf := bufio.NewReader(clientCtx.Input)
+			var source hdKeySource
+			if useLedger {
+				source = ledgerKeySource{algo: algo, hrp: sdk.GetConfig().GetBech32AccountAddrPrefix()}
+			} else {
+				mnemonic, err := input.GetString("Enter your bip39 mnemonic", inBuf)
+				if err != nil {
+					return err
+				}
+				if !bip39.IsMnemonicValid(mnemonic) {
+					return fmt.Errorf("invalid mnemonic")
+				}
+				source = mnemonicKeySource{mnemonic: mnemonic, algo: algo}
+			}
+
+			queryClient := banktypes.NewQueryClient(clientCtx)
+			queryBalances := func(addr sdk.AccAddress) (sdk.Coins, error) {
+				res, err := queryClient.AllBalances(cmd.Context(), &banktypes.QueryAllBalancesRequest{Address: addr.String()})
+				if err != nil {
+					return nil, err
+				}
+				return res.Balances, nil
+			}
+
+			funds, err := scanHDPaths(source, coinTypes, accounts, indexes, queryBalances)
+			if err != nil {
+				return err
+			}
+
+			return migrateHD(cmd, clientCtx.Keyring, inBuf, args[0], source, funds, dryRun, skipConfirm)
+		},
+	}
+
+	cmd.Flags().UintSlice(flagCoinTypes, []uint{fnsatypes.CoinType, legacyCoinType}, "Coin types of the HD paths to scan")
+	cmd.Flags().Uint32(flagAccounts, 1, "Number of the accounts of the HD paths to scan for each coin type")
+	cmd.Flags().Uint32(flagIndexes, 5, "Number of the address indexes of the HD paths to scan for each account")
+	cmd.Flags().Bool(flagDryRun, false, "Only print the HD paths holding funds without importing the keys")
+	cmd.Flags().BoolP(flags.FlagSkipConfirmation, "y", false, "Skip the confirmation of importing the keys")
+	cmd.Flags().String(flags.FlagKeyAlgorithm, string(hd.Secp256k1Type), "Key signing algorithm to derive the keys for")
+	cmd.Flags().Bool(flags.FlagUseLedger, false, "Derive the keys on a Ledger device instead of from a mnemonic, and store references to them")
+	cmd.Flags().String(flags.FlagNode, "tcp://localhost:26657", "<host>:<port> to Ostracon RPC interface for this chain")
+
+	return cmd
+}
+
+// hdPathFunds are the funds of the account derived under an HD path.
+type hdPathFunds struct {
+	params   *hd.BIP44Params
+	address  sdk.AccAddress
+	balances sdk.Coins
+}
+
+// standard returns whether the path is the standard path of LINK, which the
+// keys are derived under by default.
+func (f hdPathFunds) standard() bool {
+	return f.params.CoinType == fnsatypes.CoinType && f.params.Account == 0 && f.params.AddressIndex == 0
+}
+
+func (f hdPathFunds) keyName(name string) string {
+	return fmt.Sprintf("%s-%d-%d-%d", name, f.params.CoinType, f.params.Account, f.params.AddressIndex)
+}
+
+// hdKeySource derives the keys of HD paths, from a mnemonic or on a Ledger
+// device.
+type hdKeySource interface {
+	// address returns the address of the key of the path.
+	address(params *hd.BIP44Params) (sdk.AccAddress, error)
+	// importKey saves the key of the path to kb under name.
+	importKey(kb keyring.Keyring, name string, params *hd.BIP44Params) error
+}
+
+type mnemonicKeySource struct {
+	mnemonic string
+	algo     keyring.SignatureAlgo
+}
+
+func (s mnemonicKeySource) address(params *hd.BIP44Params) (sdk.AccAddress, error) {
+	derivedPriv, err := s.algo.Derive()(s.mnemonic, "", params.String())
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(s.algo.Generate()(derivedPriv).PubKey().Address()), nil
+}
+
+func (s mnemonicKeySource) importKey(kb keyring.Keyring, name string, params *hd.BIP44Params) error {
+	_, err := kb.NewAccount(name, s.mnemonic, "", params.String(), s.algo)
+	return err
+}
+
+type ledgerKeySource struct {
+	algo keyring.SignatureAlgo
+	hrp  string
+}
+
+func (s ledgerKeySource) address(params *hd.BIP44Params) (sdk.AccAddress, error) {
+	priv, err := ledger.NewPrivKeySecp256k1Unsafe(*params)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(priv.PubKey().Address()), nil
+}
+
+func (s ledgerKeySource) importKey(kb keyring.Keyring, name string, params *hd.BIP44Params) error {
+	_, err := kb.SaveLedgerKey(name, s.algo, s.hrp, params.CoinType, params.Account, params.AddressIndex)
+	return err
+}
+
+// scanHDPaths derives the accounts of the source under the HD paths of the
+// given coin types, accounts and indexes, and returns the ones holding funds.
+func scanHDPaths(
+	source hdKeySource, coinTypes []uint, accounts, indexes uint32,
+	queryBalances func(sdk.AccAddress) (sdk.Coins, error),
+) ([]hdPathFunds, error) {
+	var funds []hdPathFunds
+	for _, coinType := range coinTypes {
+		for account := uint32(0); account < accounts; account++ {
+			for index := uint32(0); index < indexes; index++ {
+				params := hd.NewFundraiserParams(account, uint32(coinType), index)
+				addr, err := source.address(params)
+				if err != nil {
+					return nil, fmt.Errorf("failed to derive the key of %s: %w", params, err)
+				}
+
+				balances, err := queryBalances(addr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to query the balances of %s (%s): %w", addr, params, err)
+				}
+				if !balances.IsZero() {
+					funds = append(funds, hdPathFunds{params: params, address: addr, balances: balances})
+				}
+			}
+		}
+	}
+	return funds, nil
+}
+
+// migrateHD imports the keys of the given funds under the non standard paths,
+// with a warning for each.
+func migrateHD(
+	cmd *cobra.Command, kb keyring.Keyring, inBuf *bufio.Reader, name string, source hdKeySource,
+	funds []hdPathFunds, dryRun, skipConfirm bool,
+) error {
+	var migrations []hdPathFunds
+	for _, f := range funds {
+		cmd.Printf("%s\t%s\t%s\n", f.params, f.address, f.balances)
+		if f.standard() {
+			continue
+		}
+		if _, err := kb.KeyByAddress(f.address); err == nil {
+			cmd.PrintErrf("%s is already in the keyring\n", f.address)
+			continue
+		}
+		migrations = append(migrations, f)
+	}
+	if len(migrations) == 0 {
+		cmd.PrintErrln("No funds under the non standard HD paths")
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+
+	if !skipConfirm {
+		ok, err := input.GetConfirmation(fmt.Sprintf("Import %d keys derived under non standard HD paths?", len(migrations)), inBuf, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	for _, f := range migrations {
+		keyName := f.keyName(name)
+		if err := source.importKey(kb, keyName, f.params); err != nil {
+			return fmt.Errorf("failed to import %s: %w", keyName, err)
+		}
+		cmd.PrintErrf("WARNING: imported %s (%s) derived under %s instead of the standard path m/44'/%d'/0'/0/0; move its funds %s to an account of the standard path\n",
+			keyName, f.address, f.params, fnsatypes.CoinType, f.balances)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+//go:build ledger && test_ledger_mock
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
+	"github.com/Finschia/finschia-sdk/testutil/testdata"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+func TestMigrateHDLedger(t *testing.T) {
+	// the mock device derives the keys of testdata.TestMnemonic under the coin
+	// type of the config only
+	coinType := sdk.GetConfig().GetCoinType()
+	pathAddress := func(index uint32) sdk.AccAddress {
+		path := hd.NewFundraiserParams(0, coinType, index).String()
+		info, err := keyring.NewInMemory().NewAccount("key", testdata.TestMnemonic, "", path, hd.Secp256k1)
+		require.NoError(t, err)
+		return info.GetAddress()
+	}
+	funded := map[string]sdk.Coins{
+		pathAddress(1).String(): sdk.NewCoins(sdk.NewInt64Coin("stake", 1)),
+	}
+	queryBalances := func(addr sdk.AccAddress) (sdk.Coins, error) {
+		return funded[addr.String()], nil
+	}
+
+	source := ledgerKeySource{algo: hd.Secp256k1, hrp: sdk.GetConfig().GetBech32AccountAddrPrefix()}
+	funds, err := scanHDPaths(source, []uint{uint(coinType)}, 1, 3, queryBalances)
+	require.NoError(t, err)
+	require.Len(t, funds, 1)
+	require.Equal(t, pathAddress(1), funds[0].address)
+
+	kb := keyring.NewInMemory()
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	require.NoError(t, migrateHD(cmd, kb, bufio.NewReader(strings.NewReader("")), "legacy", source, funds, false, true))
+
+	info, err := kb.Key(funds[0].keyName("legacy"))
+	require.NoError(t, err)
+	require.Equal(t, keyring.TypeLedger, info.GetType())
+	require.Equal(t, pathAddress(1), info.GetAddress())
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
+	sdk "github.com/Finschia/finschia-sdk/types"
+)
+
+func TestMigrateHD(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	// derive the addresses of the paths by the keyring
+	pathAddress := func(path string) sdk.AccAddress {
+		info, err := keyring.NewInMemory().NewAccount("key", mnemonic, "", path, hd.Secp256k1)
+		require.NoError(t, err)
+		return info.GetAddress()
+	}
+	funded := map[string]sdk.Coins{
+		pathAddress("m/44'/438'/0'/0/0").String(): sdk.NewCoins(sdk.NewInt64Coin("stake", 1)),
+		pathAddress("m/44'/438'/0'/0/2").String(): sdk.NewCoins(sdk.NewInt64Coin("stake", 2)),
+		pathAddress("m/44'/118'/0'/0/0").String(): sdk.NewCoins(sdk.NewInt64Coin("stake", 3)),
+	}
+	queryBalances := func(addr sdk.AccAddress) (sdk.Coins, error) {
+		return funded[addr.String()], nil
+	}
+
+	source := mnemonicKeySource{mnemonic: mnemonic, algo: hd.Secp256k1}
+	funds, err := scanHDPaths(source, []uint{438, 118}, 1, 3, queryBalances)
+	require.NoError(t, err)
+	require.Len(t, funds, 3)
+
+	testCases := map[string]struct {
+		dryRun  bool
+		input   string
+		skip    bool
+		imports []string
+	}{
+		"dry run": {
+			dryRun: true,
+		},
+		"declined": {
+			input: "n\n",
+		},
+		"confirmed": {
+			input:   "y\n",
+			imports: []string{"legacy-118-0-0", "legacy-438-0-2"},
+		},
+		"skip confirmation": {
+			skip:    true,
+			imports: []string{"legacy-118-0-0", "legacy-438-0-2"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			kb := keyring.NewInMemory()
+			cmd := &cobra.Command{}
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&out)
+
+			inBuf := bufio.NewReader(strings.NewReader(tc.input))
+			err := migrateHD(cmd, kb, inBuf, "legacy", source, funds, tc.dryRun, tc.skip)
+			require.NoError(t, err)
+
+			infos, err := kb.List()
+			require.NoError(t, err)
+			var names []string
+			for _, info := range infos {
+				names = append(names, info.GetName())
+				require.Contains(t, out.String(), "WARNING: imported "+info.GetName())
+			}
+			require.Equal(t, tc.imports, names)
+			if len(tc.imports) != 0 {
+				info, err := kb.Key("legacy-118-0-0")
+				require.NoError(t, err)
+				require.Equal(t, pathAddress("m/44'/118'/0'/0/0"), info.GetAddress())
+			}
+		})
+	}
+}
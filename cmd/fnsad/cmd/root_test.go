@@ -1,15 +1,24 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/Finschia/finschia-sdk/client"
 	"github.com/Finschia/finschia-sdk/client/flags"
 	"github.com/Finschia/finschia-sdk/server"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
 	"github.com/Finschia/finschia-sdk/store/types"
 	"github.com/Finschia/ostracon/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	dbm "github.com/tendermint/tm-db"
+
+	"github.com/Finschia/finschia/app/history"
+	"github.com/Finschia/finschia/types/networks"
 )
 
 func TestNewApp(t *testing.T) {
@@ -21,3 +30,95 @@ func TestNewApp(t *testing.T) {
 	app := newApp(log.NewOCLogger(log.NewSyncWriter(os.Stdout)), db, nil, ctx.Viper)
 	require.NotNil(t, app)
 }
+
+func TestNodeCmd(t *testing.T) {
+	newViper := func(home string) *viper.Viper {
+		ctx := server.NewDefaultContext()
+		ctx.Viper.Set(flags.FlagHome, home)
+		ctx.Viper.Set(server.FlagPruning, types.PruningOptionNothing)
+		ctx.Viper.Set(history.OptEnable, true)
+		return ctx.Viper
+	}
+	openHistory := func(home string) error {
+		db, err := dbm.NewDB(history.DBName, dbm.GoLevelDBBackend, filepath.Join(home, "data"))
+		if err == nil {
+			return db.Close()
+		}
+		return err
+	}
+
+	// the apps of the other commands do not run the services
+	home := t.TempDir()
+	require.NotNil(t, newApp(log.NewNopLogger(), dbm.NewMemDB(), nil, newViper(home)))
+	require.NoError(t, openHistory(home))
+
+	// the app of the command running the node does, until the command returns
+	home = t.TempDir()
+	cmd := nodeCmd(newApp, func(appCreator servertypes.AppCreator) *cobra.Command {
+		return &cobra.Command{
+			Use: "start",
+			RunE: func(*cobra.Command, []string) error {
+				require.NotNil(t, appCreator(log.NewNopLogger(), dbm.NewMemDB(), nil, newViper(home)))
+				require.Error(t, openHistory(home))
+				return nil
+			},
+		}
+	})
+	require.NoError(t, cmd.RunE(cmd, nil))
+	require.NoError(t, openHistory(home))
+}
+
+func TestApplyNetworkProfile(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+		flags.AddTxFlagsToCmd(cmd)
+		cmd.Flags().String(flags.FlagChainID, "", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		cmd.SetContext(context.WithValue(context.Background(), client.ClientContextKey, &client.Context{}))
+
+		clientCtx := client.Context{}.WithViper("")
+		require.NoError(t, client.SetCmdClientContextHandler(clientCtx, cmd))
+		return cmd
+	}
+
+	cmd := newCmd()
+	profile, err := applyNetworkProfile(cmd, networks.Local)
+	require.NoError(t, err)
+	require.Equal(t, networks.Local, profile.Name)
+	clientCtx := client.GetClientContextFromCmd(cmd)
+	require.Equal(t, "tcp://localhost:26657", clientCtx.NodeURI)
+	require.NotNil(t, clientCtx.Client)
+	gasPrices, _ := cmd.Flags().GetString(flags.FlagGasPrices)
+	require.Equal(t, "0stake", gasPrices)
+
+	cmd = newCmd()
+	_, err = applyNetworkProfile(cmd, "ebony")
+	require.NoError(t, err)
+	require.Equal(t, "ebony-2", client.GetClientContextFromCmd(cmd).ChainID)
+
+	// the flags take precedence over the profile
+	cmd = newCmd("--chain-id=other", "--fees=1stake", "--node=tcp://other:26657")
+	_, err = applyNetworkProfile(cmd, networks.Local)
+	require.NoError(t, err)
+	clientCtx = client.GetClientContextFromCmd(cmd)
+	require.Equal(t, "other", clientCtx.ChainID)
+	require.Equal(t, "tcp://other:26657", clientCtx.NodeURI)
+	gasPrices, _ = cmd.Flags().GetString(flags.FlagGasPrices)
+	require.Empty(t, gasPrices)
+
+	// the bare amounts take the denom of the profile
+	cmd = newCmd("--fees=1000")
+	_, err = applyNetworkProfile(cmd, "ebony")
+	require.NoError(t, err)
+	fees, _ := cmd.Flags().GetString(flags.FlagFees)
+	require.Equal(t, "1000tcony", fees)
+
+	cmd = newCmd("--gas-prices=0.015")
+	_, err = applyNetworkProfile(cmd, networks.Mainnet)
+	require.NoError(t, err)
+	gasPrices, _ = cmd.Flags().GetString(flags.FlagGasPrices)
+	require.Equal(t, "0.015cony", gasPrices)
+
+	_, err = applyNetworkProfile(newCmd(), "unknown")
+	require.Error(t, err)
+}
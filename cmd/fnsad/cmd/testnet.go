@@ -41,6 +41,15 @@ var (
 	flagOutputDir         = "output-dir"
 	flagNodeDaemonHome    = "node-daemon-home"
 	flagStartingIPAddress = "starting-ip-address"
+	flagConfig            = "config"
+	flagSeed              = "seed"
+	flagMnemonicFile      = "mnemonic-file"
+	flagNoKeySeed         = "no-key-seed"
+	flagEmit              = "emit"
+	flagImage             = "image"
+	flagSentries          = "sentries"
+	flagSeedNodes         = "seed-nodes"
+	flagArchiveNodes      = "archive-nodes"
 )
 
 // get cmd to initialize all files for tendermint testnet and application
@@ -53,8 +62,30 @@ necessary files (private validator, genesis, config, etc.).
 
 Note, strict routability for addresses is turned off in the config file.
 
+The stake, commission and balances of each validator, extra genesis accounts,
+patches of the genesis of the modules and the ports of the nodes can be given by
+a YAML or JSON spec file with --config, in which case "v" is ignored.
+
+The chain ID, and the operator, consensus and node keys of the validators are
+derived from --seed, so that the same seed always results in the same addresses
+and node IDs. The operators of the first validators can also be given by the
+mnemonics of --mnemonic-file, one per line. Use --no-key-seed not to write the
+mnemonics of the operators to key_seed.json.
+
+With --emit docker-compose or --emit k8s, the manifests to run the nodes with the
+generated files, ports and persistent peers are also written to the output
+directory.
+
+Besides the validators, --sentries generates sentry nodes for each validator,
+which then only connects to its sentries with PEX disabled, and --seed-nodes and
+--archive-nodes generate full nodes in seed mode and keeping all the states.
+
 Example:
 	fnsad testnet --v 4 --output-dir ./output --starting-ip-address 192.168.10.2
+	fnsad testnet --config spec.yaml --output-dir ./output
+	fnsad testnet --v 4 --seed ci --no-key-seed
+	fnsad testnet --v 4 --output-dir ./output --emit docker-compose,k8s
+	fnsad testnet --v 4 --sentries 2 --seed-nodes 1 --archive-nodes 1
 	`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			clientCtx, err := client.GetClientQueryContext(cmd)
@@ -73,11 +104,56 @@ Example:
 			startingIPAddress, _ := cmd.Flags().GetString(flagStartingIPAddress) // nolint: errcheck
 			numValidators, _ := cmd.Flags().GetInt(flagNumValidators)            // nolint: errcheck
 			algo, _ := cmd.Flags().GetString(flags.FlagKeyAlgorithm)             // nolint: errcheck
+			specFile, _ := cmd.Flags().GetString(flagConfig)                     // nolint: errcheck
+			seed, _ := cmd.Flags().GetString(flagSeed)                           // nolint: errcheck
+			mnemonicFile, _ := cmd.Flags().GetString(flagMnemonicFile)           // nolint: errcheck
+			noKeySeed, _ := cmd.Flags().GetBool(flagNoKeySeed)                   // nolint: errcheck
+			emits, _ := cmd.Flags().GetStringSlice(flagEmit)                     // nolint: errcheck
+			image, _ := cmd.Flags().GetString(flagImage)                         // nolint: errcheck
+
+			if err := validateEmits(emits); err != nil {
+				return err
+			}
 
-			return InitTestnet(
+			spec := newTestnetSpec(numValidators, startingIPAddress)
+			if specFile != "" {
+				if spec, err = loadTestnetSpec(specFile, startingIPAddress); err != nil {
+					return err
+				}
+			}
+			for flag, count := range map[string]*int{
+				flagSentries:     &spec.SentriesPerValidator,
+				flagSeedNodes:    &spec.SeedNodes,
+				flagArchiveNodes: &spec.ArchiveNodes,
+			} {
+				if cmd.Flags().Changed(flag) {
+					*count, _ = cmd.Flags().GetInt(flag) // nolint: errcheck
+				}
+			}
+
+			keys := testnetKeys{seed: seed}
+			if mnemonicFile != "" {
+				if keys.mnemonics, err = loadMnemonics(mnemonicFile); err != nil {
+					return err
+				}
+			}
+
+			nodes, err := initTestnet(
 				clientCtx, cmd, config, mbm, genBalIterator, outputDir, chainID, minGasPrices,
-				nodeDirPrefix, nodeDaemonHome, startingIPAddress, keyringBackend, algo, numValidators,
+				nodeDirPrefix, nodeDaemonHome, keyringBackend, algo, spec, keys, !noKeySeed,
 			)
+			if err != nil {
+				return err
+			}
+
+			manifests := testnetManifests{
+				outputDir:      outputDir,
+				nodeDaemonHome: nodeDaemonHome,
+				image:          image,
+				spec:           spec,
+				nodes:          nodes,
+			}
+			return manifests.write(emits)
 		},
 	}
 
@@ -90,6 +166,15 @@ Example:
 	cmd.Flags().String(server.FlagMinGasPrices, fmt.Sprintf("0.000006%s", sdk.DefaultBondDenom), "Minimum gas prices to accept for transactions; All fees in a tx must meet this minimum (e.g. 0.01photino,0.001stake)")
 	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|test)")
 	cmd.Flags().String(flags.FlagKeyAlgorithm, string(hd.Secp256k1Type), "Key signing algorithm to generate keys for")
+	cmd.Flags().String(flagConfig, "", "YAML or JSON spec file of the validators, accounts, genesis patches and ports of the testnet")
+	cmd.Flags().String(flagSeed, "", "Seed to derive the chain ID and the keys of the validators from, instead of random ones")
+	cmd.Flags().String(flagMnemonicFile, "", "File of the mnemonics of the operators of the validators, one per line")
+	cmd.Flags().Bool(flagNoKeySeed, false, "Do not write the mnemonics of the operators to key_seed.json")
+	cmd.Flags().StringSlice(flagEmit, nil, fmt.Sprintf("Manifests to write to run the testnet (%s|%s)", emitDockerCompose, emitK8s))
+	cmd.Flags().String(flagImage, defaultTestnetImage, "Docker image of the nodes in the manifests")
+	cmd.Flags().Int(flagSentries, 0, "Number of sentry nodes to initialize for each validator")
+	cmd.Flags().Int(flagSeedNodes, 0, "Number of seed nodes to initialize")
+	cmd.Flags().Int(flagArchiveNodes, 0, "Number of archive full nodes to initialize")
 
 	return cmd
 }
@@ -113,12 +198,51 @@ func InitTestnet(
 	algoStr string,
 	numValidators int,
 ) error {
+	_, err := initTestnet(
+		clientCtx, cmd, nodeConfig, mbm, genBalIterator, outputDir, chainID, minGasPrices,
+		nodeDirPrefix, nodeDaemonHome, keyringBackend, algoStr, newTestnetSpec(numValidators, startingIPAddress),
+		testnetKeys{}, true,
+	)
+	return err
+}
+
+// initTestnet initializes the testnet described by spec, with the keys derived
+// by keys, and returns its nodes
+func initTestnet(
+	clientCtx client.Context,
+	cmd *cobra.Command,
+	nodeConfig *ostconfig.Config,
+	mbm module.BasicManager,
+	genBalIterator banktypes.GenesisBalancesIterator,
+	outputDir,
+	chainID,
+	minGasPrices,
+	nodeDirPrefix,
+	nodeDaemonHome,
+	keyringBackend,
+	algoStr string,
+	spec testnetSpec,
+	keys testnetKeys,
+	writeKeySeed bool,
+) ([]testnetNode, error) {
+	if chainID == "" {
+		chainID = spec.ChainID
+	}
+	if chainID == "" {
+		chainID = keys.chainID()
+	}
 	if chainID == "" {
 		chainID = "chain-" + ostrand.NewRand().Str(6)
 	}
 
-	nodeIDs := make([]string, numValidators)
-	valPubKeys := make([]cryptotypes.PubKey, numValidators)
+	if err := spec.validateNodes(); err != nil {
+		return nil, err
+	}
+	numNodes := spec.numNodes()
+
+	nodes := make([]testnetNode, numNodes)
+	nodeIDs := make([]string, numNodes)
+	valPubKeys := make([]cryptotypes.PubKey, numNodes)
 
 	appConfig := srvconfig.DefaultConfig()
 	appConfig.MinGasPrices = minGasPrices
@@ -136,92 +260,121 @@ func InitTestnet(
 	)
 
 	inBuf := bufio.NewReader(cmd.InOrStdin())
-	// generate private keys, node IDs, and initial transactions
-	for i := 0; i < numValidators; i++ {
+	// generate private keys, node IDs, and initial transactions of the
+	// validators
+	for i := 0; i < numNodes; i++ {
 		nodeDirName := fmt.Sprintf("%s%d", nodeDirPrefix, i)
 		nodeDir := filepath.Join(outputDir, nodeDirName, nodeDaemonHome)
 		gentxsDir := filepath.Join(outputDir, "gentxs")
 
 		nodeConfig.SetRoot(nodeDir)
-		nodeConfig.RPC.ListenAddress = "tcp://0.0.0.0:26657"
+		ports := spec.ports(i)
 
 		if err := os.MkdirAll(filepath.Join(nodeDir, "config"), nodeDirPerm); err != nil {
 			_ = os.RemoveAll(outputDir)
-			return err
+			return nil, err
 		}
 
 		nodeConfig.Moniker = nodeDirName
 
-		ip, err := getIP(i, startingIPAddress)
+		ip, err := spec.ip(i)
 		if err != nil {
 			_ = os.RemoveAll(outputDir)
-			return err
+			return nil, err
 		}
 
+		if err := keys.writeNodeValidatorFiles(nodeConfig, i); err != nil {
+			_ = os.RemoveAll(outputDir)
+			return nil, err
+		}
 		nodeIDs[i], valPubKeys[i], err = genutil.InitializeNodeValidatorFiles(nodeConfig)
 		if err != nil {
 			_ = os.RemoveAll(outputDir)
-			return err
+			return nil, err
 		}
 
-		memo := fmt.Sprintf("%s@%s:26656", nodeIDs[i], ip)
+		role, validator := spec.role(i)
+		nodes[i] = testnetNode{dirName: nodeDirName, id: nodeIDs[i], ip: ip, ports: ports, role: role, validator: validator}
+		memo := nodes[i].peer(ip)
 		genFiles = append(genFiles, nodeConfig.GenesisFile())
 
+		appConfig.Pruning = nodes[i].pruning()
+		appConfig.API.Address = fmt.Sprintf("tcp://0.0.0.0:%d", ports.API)
+		appConfig.GRPC.Address = fmt.Sprintf("0.0.0.0:%d", ports.GRPC)
+		appConfig.GRPCWeb.Address = fmt.Sprintf("0.0.0.0:%d", ports.GRPCWeb)
+		srvconfig.WriteConfigFile(filepath.Join(nodeDir, "config/app.toml"), appConfig)
+
+		if role != roleValidator {
+			continue
+		}
+
 		kb, err := keyring.New(sdk.KeyringServiceName(), keyringBackend, nodeDir, inBuf)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		keyringAlgos, _ := kb.SupportedAlgorithms()
 		algo, err := keyring.NewSigningAlgoFromString(algoStr, keyringAlgos)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		addr, secret, err := testutil.GenerateSaveCoinKey(kb, nodeDirName, "", true, algo)
+		mnemonic, err := keys.mnemonic(i)
+		if err != nil {
+			return nil, err
+		}
+		addr, secret, err := testutil.GenerateSaveCoinKey(kb, nodeDirName, mnemonic, true, algo)
 		if err != nil {
 			_ = os.RemoveAll(outputDir)
-			return err
+			return nil, err
 		}
 
-		info := map[string]string{"secret": secret}
+		if writeKeySeed {
+			info := map[string]string{"secret": secret}
 
-		cliPrint, err := json.Marshal(info)
-		if err != nil {
-			return err
-		}
+			cliPrint, err := json.Marshal(info)
+			if err != nil {
+				return nil, err
+			}
 
-		// save private key seed words
-		if err := writeFile(fmt.Sprintf("%v.json", "key_seed"), nodeDir, cliPrint); err != nil {
-			return err
+			// save private key seed words
+			if err := writeFile(fmt.Sprintf("%v.json", "key_seed"), nodeDir, cliPrint); err != nil {
+				return nil, err
+			}
 		}
 
-		accTokens := sdk.TokensFromConsensusPower(1000, sdk.DefaultPowerReduction)
-		accStakingTokens := sdk.TokensFromConsensusPower(500, sdk.DefaultPowerReduction)
-		coins := sdk.Coins{
-			sdk.NewCoin(fmt.Sprintf("%stoken", nodeDirName), accTokens),
-			sdk.NewCoin(sdk.DefaultBondDenom, accStakingTokens),
+		valSpec := spec.Validators[i]
+		coins, err := valSpec.balances(nodeDirName)
+		if err != nil {
+			return nil, err
 		}
 
 		genBalances = append(genBalances, banktypes.Balance{Address: addr.String(), Coins: coins.Sort()})
 		genAccounts = append(genAccounts, authtypes.NewBaseAccount(addr, nil, 0, 0))
 
-		valTokens := sdk.TokensFromConsensusPower(100, sdk.DefaultPowerReduction)
+		selfDelegation, err := valSpec.selfDelegation(nodeDirName)
+		if err != nil {
+			return nil, err
+		}
+		commission, err := valSpec.commissionRates(nodeDirName)
+		if err != nil {
+			return nil, err
+		}
 		createValMsg, err := stakingtypes.NewMsgCreateValidator(
 			sdk.ValAddress(addr),
 			valPubKeys[i],
-			sdk.NewCoin(sdk.DefaultBondDenom, valTokens),
+			selfDelegation,
 			stakingtypes.NewDescription(nodeDirName, "", "", "", ""),
-			stakingtypes.NewCommissionRates(sdk.OneDec(), sdk.OneDec(), sdk.OneDec()),
+			commission,
 			sdk.OneInt(),
 		)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		txBuilder := clientCtx.TxConfig.NewTxBuilder()
 		if err := txBuilder.SetMsgs(createValMsg); err != nil {
-			return err
+			return nil, err
 		}
 
 		txBuilder.SetMemo(memo)
@@ -234,41 +387,53 @@ func InitTestnet(
 			WithTxConfig(clientCtx.TxConfig)
 
 		if err := tx.Sign(txFactory, nodeDirName, txBuilder, true); err != nil {
-			return err
+			return nil, err
 		}
 
 		txBz, err := clientCtx.TxConfig.TxJSONEncoder()(txBuilder.GetTx())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if err := writeFile(fmt.Sprintf("%v.json", nodeDirName), gentxsDir, txBz); err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		srvconfig.WriteConfigFile(filepath.Join(nodeDir, "config/app.toml"), appConfig)
+	for _, account := range spec.Accounts {
+		addr, err := sdk.AccAddressFromBech32(account.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account address %q: %w", account.Address, err)
+		}
+		coins, err := sdk.ParseCoinsNormalized(account.Balances)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balances of %s: %w", account.Address, err)
+		}
+
+		genBalances = append(genBalances, banktypes.Balance{Address: addr.String(), Coins: coins})
+		genAccounts = append(genAccounts, authtypes.NewBaseAccount(addr, nil, 0, 0))
 	}
 
-	if err := initGenFiles(clientCtx, mbm, chainID, genAccounts, genBalances, genFiles, numValidators); err != nil {
-		return err
+	if err := initGenFiles(clientCtx, mbm, chainID, genAccounts, genBalances, genFiles, numNodes, spec); err != nil {
+		return nil, err
 	}
 
 	err := collectGenFiles(
-		clientCtx, nodeConfig, chainID, nodeIDs, valPubKeys, numValidators,
-		outputDir, nodeDirPrefix, nodeDaemonHome, genBalIterator,
+		clientCtx, nodeConfig, chainID, nodeIDs, valPubKeys, numNodes,
+		outputDir, nodeDirPrefix, nodeDaemonHome, genBalIterator, spec, nodes,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	cmd.PrintErrf("Successfully initialized %d node directories\n", numValidators)
-	return nil
+	cmd.PrintErrf("Successfully initialized %d node directories\n", numNodes)
+	return nodes, nil
 }
 
 func initGenFiles(
 	clientCtx client.Context, mbm module.BasicManager, chainID string,
 	genAccounts []authtypes.GenesisAccount, genBalances []banktypes.Balance,
-	genFiles []string, numValidators int,
+	genFiles []string, numNodes int, spec testnetSpec,
 ) error {
 	appGenState := mbm.DefaultGenesis(clientCtx.Codec)
 
@@ -294,6 +459,10 @@ func initGenFiles(
 	}
 	appGenState[banktypes.ModuleName] = clientCtx.Codec.MustMarshalJSON(&bankGenState)
 
+	if err := spec.patchGenesis(clientCtx, mbm, appGenState); err != nil {
+		return err
+	}
+
 	appGenStateJSON, err := json.MarshalIndent(appGenState, "", "  ")
 	if err != nil {
 		return err
@@ -305,8 +474,8 @@ func initGenFiles(
 		Validators: nil,
 	}
 
-	// generate empty genesis files for each validator and save
-	for i := 0; i < numValidators; i++ {
+	// generate empty genesis files for each node and save
+	for i := 0; i < numNodes; i++ {
 		if err := genDoc.SaveAs(genFiles[i]); err != nil {
 			return err
 		}
@@ -316,41 +485,52 @@ func initGenFiles(
 
 func collectGenFiles(
 	clientCtx client.Context, nodeConfig *ostconfig.Config, chainID string,
-	nodeIDs []string, valPubKeys []cryptotypes.PubKey, numValidators int,
+	nodeIDs []string, valPubKeys []cryptotypes.PubKey, numNodes int,
 	outputDir, nodeDirPrefix, nodeDaemonHome string, genBalIterator banktypes.GenesisBalancesIterator,
+	spec testnetSpec, nodes []testnetNode,
 ) error {
 	var appState json.RawMessage
 	genTime := osttime.Now()
 
-	for i := 0; i < numValidators; i++ {
+	for i := 0; i < numNodes; i++ {
 		nodeDirName := fmt.Sprintf("%s%d", nodeDirPrefix, i)
 		nodeDir := filepath.Join(outputDir, nodeDirName, nodeDaemonHome)
 		gentxsDir := filepath.Join(outputDir, "gentxs")
 		nodeConfig.Moniker = nodeDirName
 
 		nodeConfig.SetRoot(nodeDir)
+		ports := spec.ports(i)
+		nodeConfig.P2P.ListenAddress = fmt.Sprintf("tcp://0.0.0.0:%d", ports.P2P)
+		nodeConfig.RPC.ListenAddress = fmt.Sprintf("tcp://0.0.0.0:%d", ports.RPC)
 
-		nodeID, valPubKey := nodeIDs[i], valPubKeys[i]
-		initCfg := genutiltypes.NewInitConfig(chainID, gentxsDir, nodeID, valPubKey)
+		// the other nodes come after the validators, and take the canonical
+		// application state without collecting the gentxs
+		if nodes[i].role == roleValidator {
+			nodeID, valPubKey := nodeIDs[i], valPubKeys[i]
+			initCfg := genutiltypes.NewInitConfig(chainID, gentxsDir, nodeID, valPubKey)
 
-		genDoc, err := types.GenesisDocFromFile(nodeConfig.GenesisFile())
-		if err != nil {
-			return err
-		}
+			genDoc, err := types.GenesisDocFromFile(nodeConfig.GenesisFile())
+			if err != nil {
+				return err
+			}
 
-		nodeAppState, err := genutil.GenAppStateFromConfig(clientCtx.Codec, clientCtx.TxConfig, nodeConfig, initCfg, *genDoc, genBalIterator)
-		if err != nil {
-			return err
-		}
+			nodeAppState, err := genutil.GenAppStateFromConfig(clientCtx.Codec, clientCtx.TxConfig, nodeConfig, initCfg, *genDoc, genBalIterator)
+			if err != nil {
+				return err
+			}
 
-		if appState == nil {
-			// set the canonical application state (they should not differ)
-			appState = nodeAppState
+			if appState == nil {
+				// set the canonical application state (they should not differ)
+				appState = nodeAppState
+			}
 		}
 
+		configureP2P(nodeConfig.P2P, nodes, i)
+		ostconfig.WriteConfigFile(filepath.Join(nodeDir, "config", "config.toml"), nodeConfig)
+
 		genFile := nodeConfig.GenesisFile()
 
-		// overwrite each validator's genesis file to have a canonical genesis time
+		// overwrite each node's genesis file to have a canonical genesis time
 		if err := genutil.ExportGenesisFileWithTime(genFile, chainID, nil, appState, genTime); err != nil {
 			return err
 		}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	emitDockerCompose = "docker-compose"
+	emitK8s           = "k8s"
+
+	defaultTestnetImage = "finschia/finschianode"
+
+	// hostPortStride shifts the ports the nodes of a bridge network publish on
+	// the host, as they all listen on the same ports in their containers.
+	hostPortStride = 10
+
+	k8sNodeHome = "/home/fnsad"
+)
+
+// prometheusLabels are the labels or annotations for Prometheus to scrape the
+// telemetry of the node, served by the API server.
+func (n testnetNode) prometheusLabels() map[string]string {
+	return map[string]string{
+		"prometheus.io/scrape":       "true",
+		"prometheus.io/port":         strconv.Itoa(n.ports.API),
+		"prometheus.io/path":         "/metrics",
+		"prometheus.io/param_format": "prometheus",
+	}
+}
+
+// testnetManifests writes the manifests of the given kinds to run the generated
+// testnet.
+type testnetManifests struct {
+	outputDir      string
+	nodeDaemonHome string
+	image          string
+	spec           testnetSpec
+	nodes          []testnetNode
+}
+
+func validateEmits(emits []string) error {
+	for _, emit := range emits {
+		if emit != emitDockerCompose && emit != emitK8s {
+			return fmt.Errorf("unknown manifest %q, expected %s or %s", emit, emitDockerCompose, emitK8s)
+		}
+	}
+	return nil
+}
+
+func (m testnetManifests) write(emits []string) error {
+	for _, emit := range emits {
+		var (
+			name string
+			docs []interface{}
+			err  error
+		)
+		switch emit {
+		case emitDockerCompose:
+			name = "docker-compose.yml"
+			var doc interface{}
+			doc, err = m.dockerCompose()
+			docs = []interface{}{doc}
+		case emitK8s:
+			name = "k8s.yaml"
+			docs, err = m.k8s()
+		default:
+			err = fmt.Errorf("unknown manifest %q", emit)
+		}
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		for _, doc := range docs {
+			if err := encoder.Encode(doc); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+		if err := writeFile(name, m.outputDir, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerCompose returns the compose file of the finschianode containers, which
+// share the output directory. The nodes run on a bridge network with the IP
+// addresses of the persistent peers, or on the host network if their ports are
+// shifted by a port offset.
+func (m testnetManifests) dockerCompose() (interface{}, error) {
+	hostNetwork := m.spec.PortOffset != 0
+
+	services := map[string]interface{}{}
+	for i, node := range m.nodes {
+		service := map[string]interface{}{
+			"container_name": node.serviceName(),
+			"image":          m.image,
+			"environment": []string{
+				fmt.Sprintf("ID=%d", i),
+				"LOG=${LOG:-finschia.log}",
+				fmt.Sprintf("NODE_HOME=/data/%s/%s", node.dirName, m.nodeDaemonHome),
+			},
+			"volumes": []string{"./:/data:Z"},
+			"labels":  node.prometheusLabels(),
+		}
+		if hostNetwork {
+			service["network_mode"] = "host"
+		} else {
+			service["ports"] = node.publishedPorts(i)
+			service["networks"] = map[string]interface{}{
+				"localnet": map[string]string{"ipv4_address": node.ip},
+			}
+		}
+		services[node.serviceName()] = service
+	}
+
+	compose := map[string]interface{}{
+		"version":  "3",
+		"services": services,
+	}
+	if !hostNetwork {
+		subnet, err := localnetSubnet(m.spec.StartingIPAddress)
+		if err != nil {
+			return nil, err
+		}
+		compose["networks"] = map[string]interface{}{
+			"localnet": map[string]interface{}{
+				"driver": "bridge",
+				"ipam": map[string]interface{}{
+					"driver": "default",
+					"config": []map[string]string{{"subnet": subnet}},
+				},
+			},
+		}
+	}
+	return compose, nil
+}
+
+// publishedPorts maps the RPC, API, gRPC and gRPC-web ports of the i-th node to
+// distinct ports of the host.
+func (n testnetNode) publishedPorts(i int) []string {
+	offset := i * hostPortStride
+	ports := make([]string, 0, 4)
+	for _, port := range []int{n.ports.RPC, n.ports.API, n.ports.GRPC, n.ports.GRPCWeb} {
+		ports = append(ports, fmt.Sprintf("%d:%d", port+offset, port))
+	}
+	return ports
+}
+
+func localnetSubnet(startingIPAddress string) (string, error) {
+	ip := net.ParseIP(startingIPAddress).To4()
+	if ip == nil {
+		return "", fmt.Errorf("%v: non ipv4 address", startingIPAddress)
+	}
+	return fmt.Sprintf("%s/24", ip.Mask(net.CIDRMask(24, 32))), nil
+}
+
+// k8s returns a Secret of the node files, a Service and a StatefulSet for each
+// node. The nodes reach their persistent peers through the DNS names of the
+// services instead of IP addresses.
+func (m testnetManifests) k8s() ([]interface{}, error) {
+	peers := make([]string, 0, 2*len(m.nodes))
+	for _, node := range m.nodes {
+		peers = append(peers, node.peer(node.ip), node.peer(node.serviceName()))
+	}
+	peerReplacer := strings.NewReplacer(peers...)
+
+	var docs []interface{}
+	for _, node := range m.nodes {
+		nodeDir := filepath.Join(m.outputDir, node.dirName, m.nodeDaemonHome)
+		files := map[string]string{}
+		for _, file := range []string{
+			"config/config.toml", "config/app.toml", "config/genesis.json",
+			"config/node_key.json", "config/priv_validator_key.json", "data/priv_validator_state.json",
+		} {
+			bz, err := os.ReadFile(filepath.Join(nodeDir, file))
+			if err != nil {
+				return nil, err
+			}
+			files[filepath.Base(file)] = string(bz)
+		}
+		files["config.toml"] = peerReplacer.Replace(files["config.toml"])
+
+		name := node.serviceName()
+		labels := map[string]string{"app": name}
+		containerPorts := []map[string]interface{}{
+			{"name": "p2p", "containerPort": node.ports.P2P},
+			{"name": "rpc", "containerPort": node.ports.RPC},
+			{"name": "api", "containerPort": node.ports.API},
+			{"name": "grpc", "containerPort": node.ports.GRPC},
+			{"name": "grpc-web", "containerPort": node.ports.GRPCWeb},
+		}
+		servicePorts := make([]map[string]interface{}, 0, len(containerPorts))
+		for _, port := range containerPorts {
+			servicePorts = append(servicePorts, map[string]interface{}{
+				"name":       port["name"],
+				"port":       port["containerPort"],
+				"targetPort": port["name"],
+			})
+		}
+
+		docs = append(docs,
+			map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]interface{}{"name": name, "labels": labels},
+				"stringData": files,
+			},
+			map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]interface{}{"name": name, "labels": labels},
+				"spec": map[string]interface{}{
+					"selector": labels,
+					"ports":    servicePorts,
+				},
+			},
+			map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"metadata":   map[string]interface{}{"name": name, "labels": labels},
+				"spec": map[string]interface{}{
+					"serviceName": name,
+					"replicas":    1,
+					"selector":    map[string]interface{}{"matchLabels": labels},
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"labels":      labels,
+							"annotations": node.prometheusLabels(),
+						},
+						"spec": map[string]interface{}{
+							// the files of the secret are copied once, so that the node
+							// keeps the state of its validator across restarts
+							"initContainers": []map[string]interface{}{{
+								"name":    "init",
+								"image":   m.image,
+								"command": []string{"sh", "-c", k8sInitScript},
+								"volumeMounts": []map[string]string{
+									{"name": "data", "mountPath": k8sNodeHome},
+									{"name": "files", "mountPath": "/files"},
+								},
+							}},
+							"containers": []map[string]interface{}{{
+								"name":         "fnsad",
+								"image":        m.image,
+								"command":      []string{"fnsad"},
+								"args":         []string{"start", "--home", k8sNodeHome},
+								"ports":        containerPorts,
+								"volumeMounts": []map[string]string{{"name": "data", "mountPath": k8sNodeHome}},
+							}},
+							"volumes": []map[string]interface{}{
+								{"name": "files", "secret": map[string]string{"secretName": name}},
+							},
+						},
+					},
+					"volumeClaimTemplates": []map[string]interface{}{{
+						"metadata": map[string]string{"name": "data"},
+						"spec": map[string]interface{}{
+							"accessModes": []string{"ReadWriteOnce"},
+							"resources":   map[string]interface{}{"requests": map[string]string{"storage": "10Gi"}},
+						},
+					}},
+				},
+			},
+		)
+	}
+	return docs, nil
+}
+
+var k8sInitScript = strings.Join([]string{
+	fmt.Sprintf("mkdir -p %[1]s/config %[1]s/data", k8sNodeHome),
+	fmt.Sprintf("for f in config.toml app.toml genesis.json node_key.json priv_validator_key.json; do [ -f %[1]s/config/$f ] || cp /files/$f %[1]s/config/$f; done", k8sNodeHome),
+	fmt.Sprintf("[ -f %[1]s/data/priv_validator_state.json ] || cp /files/priv_validator_state.json %[1]s/data/", k8sNodeHome),
+}, " && ")
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	ostconfig "github.com/Finschia/ostracon/config"
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/Finschia/ostracon/node"
+	"github.com/Finschia/ostracon/privval"
+	sm "github.com/Finschia/ostracon/state"
+	"github.com/Finschia/ostracon/store"
+	octypes "github.com/Finschia/ostracon/types"
+	"github.com/spf13/cobra"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	cryptocodec "github.com/Finschia/finschia-sdk/crypto/codec"
+	"github.com/Finschia/finschia-sdk/server"
+	servertypes "github.com/Finschia/finschia-sdk/server/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/app"
+)
+
+const (
+	flagAccountsToFund = "accounts-to-fund"
+	flagFundAmount     = "fund-amount"
+	flagVotingPeriod   = "voting-period"
+	flagValidatorPower = "validator-power"
+)
+
+// inPlaceTestnetCmd forks an existing chain, e.g. mainnet, into a private
+// network run by the local validator, and starts the node.
+func inPlaceTestnetCmd(appCreator servertypes.AppCreator, defaultNodeHome string) *cobra.Command {
+	var args app.InPlaceTestnetArgs
+
+	cmd := server.StartCmd(func(logger log.Logger, db dbm.DB, traceStore io.Writer, appOpts servertypes.AppOptions) servertypes.Application {
+		linkApp := appCreator(logger, db, traceStore, appOpts).(*app.LinkApp)
+		if err := linkApp.InitInPlaceTestnet(args); err != nil {
+			panic(fmt.Errorf("failed to initialize in-place testnet: %w", err))
+		}
+		return linkApp
+	}, defaultNodeHome)
+
+	cmd.Use = "in-place [new-chain-id] [operator-address]"
+	cmd.Aliases = []string{"fork"}
+	cmd.Short = "Fork the chain in the data directory into a private network run by the local validator, and start it"
+	cmd.Long = `in-place rewrites the state of the chain in the data directory, e.g. a copy of
+mainnet, into the one of a private network run by a single local validator, and
+starts the node. It:
+
+- replaces the validator set by the validator of priv_validator_key.json,
+  operated by the given account, paying the delegations, unbonding
+  delegations and rewards of the former validators back to their delegators,
+- makes the operator the sole member of the foundation,
+- shortens the voting periods of gov and foundation proposals,
+- mints to the operator and the accounts of --accounts-to-fund the test funds
+  they lack,
+- changes the chain-id, so that transactions cannot be replayed on the original chain.
+
+The data directory is modified in place, so run it on a copy. Once the node has
+produced a block, it can be restarted with "fnsad start".
+
+Example:
+	fnsad testnet in-place fork-1 link1... --accounts-to-fund link1...,link1... --voting-period 1m
+`
+	cmd.Args = cobra.ExactArgs(2)
+
+	startRunE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, cmdArgs []string) error {
+		serverCtx := server.GetServerContextFromCmd(cmd)
+		config := serverCtx.Config
+
+		newChainID := cmdArgs[0]
+		operator, err := sdk.AccAddressFromBech32(cmdArgs[1])
+		if err != nil {
+			return err
+		}
+
+		accountsToFund := []sdk.AccAddress{operator}
+		addrs, _ := cmd.Flags().GetStringSlice(flagAccountsToFund) // nolint: errcheck
+		for _, addr := range addrs {
+			acc, err := sdk.AccAddressFromBech32(addr)
+			if err != nil {
+				return err
+			}
+			accountsToFund = append(accountsToFund, acc)
+		}
+
+		fundAmount, _ := cmd.Flags().GetString(flagFundAmount) // nolint: errcheck
+		coins, err := sdk.ParseCoinsNormalized(fundAmount)
+		if err != nil {
+			return err
+		}
+
+		votingPeriod, _ := cmd.Flags().GetDuration(flagVotingPeriod) // nolint: errcheck
+		power, _ := cmd.Flags().GetInt64(flagValidatorPower)         // nolint: errcheck
+
+		pv := privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+		consPubKey, err := cryptocodec.FromOcPubKeyInterface(pv.Key.PubKey)
+		if err != nil {
+			return err
+		}
+
+		if err := forkOstraconState(config, newChainID, pv, power); err != nil {
+			return err
+		}
+
+		// do not connect to the peers of the original chain
+		config.P2P.Seeds = ""
+		config.P2P.PersistentPeers = ""
+
+		args = app.InPlaceTestnetArgs{
+			ValidatorConsPubKey: consPubKey,
+			ValidatorOperator:   operator,
+			ValidatorPower:      power,
+			VotingPeriod:        votingPeriod,
+			AccountsToFund:      accountsToFund,
+			FundAmount:          coins,
+		}
+		return startRunE(cmd, cmdArgs)
+	}
+
+	cmd.Flags().StringSlice(flagAccountsToFund, []string{}, "Comma-separated list of accounts to mint test funds to, besides the operator")
+	cmd.Flags().String(flagFundAmount, fmt.Sprintf("1000000000000%s", sdk.DefaultBondDenom), "Amount of test funds each account is topped up to")
+	cmd.Flags().Duration(flagVotingPeriod, time.Minute, "Voting period of gov and foundation proposals")
+	cmd.Flags().Int64(flagValidatorPower, 100, "Consensus power of the local validator")
+
+	return cmd
+}
+
+// forkOstraconState rewrites the ostracon state in the data directory, so that
+// the chain continues under newChainID with the validator of pv as its sole
+// validator.
+func forkOstraconState(config *ostconfig.Config, newChainID string, pv *privval.FilePV, power int64) error {
+	blockStoreDB, err := node.DefaultDBProvider(&node.DBContext{ID: "blockstore", Config: config})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	blockStore := store.NewBlockStore(blockStoreDB)
+
+	stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: config})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+	stateStore := sm.NewStore(stateDB)
+
+	state, err := stateStore.Load()
+	if err != nil {
+		return err
+	}
+	if state.IsEmpty() {
+		return fmt.Errorf("no state found in %s", config.DBDir())
+	}
+	if blockStore.Height() != state.LastBlockHeight {
+		return fmt.Errorf("block store height %d and state height %d differ; start and stop the node once to catch up", blockStore.Height(), state.LastBlockHeight)
+	}
+	height := state.LastBlockHeight
+
+	// sign the last block by our validator alone, so that it is the last commit
+	// of the next block.
+	vote := &tmproto.Vote{
+		Type:             tmproto.PrecommitType,
+		Height:           height,
+		Round:            0,
+		BlockID:          state.LastBlockID.ToProto(),
+		Timestamp:        time.Now(),
+		ValidatorAddress: pv.Key.Address,
+		ValidatorIndex:   0,
+	}
+	signature, err := pv.Key.PrivKey.Sign(octypes.VoteSignBytes(newChainID, vote))
+	if err != nil {
+		return err
+	}
+	seenCommit := octypes.NewCommit(height, vote.Round, state.LastBlockID, []octypes.CommitSig{
+		octypes.NewCommitSigForBlock(signature, pv.Key.Address, vote.Timestamp),
+	})
+	if err := blockStore.SaveSeenCommit(height, seenCommit); err != nil {
+		return err
+	}
+
+	validator := octypes.NewValidator(pv.Key.PubKey, power)
+	validatorSet := octypes.NewValidatorSet([]*octypes.Validator{validator})
+
+	state.ChainID = newChainID
+	state.LastValidators = validatorSet
+	state.Validators = validatorSet.Copy()
+	state.NextValidators = validatorSet.Copy()
+	state.LastHeightValidatorsChanged = height + 2
+
+	// the validators of the next block are persisted by Save
+	valSet, err := validatorSet.ToProto()
+	if err != nil {
+		return err
+	}
+	for _, h := range []int64{height, height + 1} {
+		bz, err := (&tmstate.ValidatorsInfo{ValidatorSet: valSet, LastHeightChanged: h}).Marshal()
+		if err != nil {
+			return err
+		}
+		if err := stateDB.Set([]byte(fmt.Sprintf("validatorsKey:%d", h)), bz); err != nil {
+			return err
+		}
+	}
+	if err := stateStore.Save(state); err != nil {
+		return err
+	}
+
+	// ostracon keeps a copy of the genesis doc in the state db. Drop it, so that
+	// the one with the new chain-id is loaded from genesis.json.
+	genDoc, err := octypes.GenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return err
+	}
+	genDoc.ChainID = newChainID
+	if err := genDoc.SaveAs(config.GenesisFile()); err != nil {
+		return err
+	}
+
+	iter, err := stateDB.Iterator(append([]byte("genesisDoc"), byte(0)), append([]byte("genesisDoc"), byte(255)))
+	if err != nil {
+		return err
+	}
+	var genDocKeys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		genDocKeys = append(genDocKeys, iter.Key())
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+	for _, key := range genDocKeys {
+		if err := stateDB.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ostconfig "github.com/Finschia/ostracon/config"
+	"github.com/Finschia/ostracon/crypto/ed25519"
+	ostos "github.com/Finschia/ostracon/libs/os"
+	"github.com/Finschia/ostracon/p2p"
+	"github.com/Finschia/ostracon/privval"
+	"github.com/cosmos/go-bip39"
+)
+
+// testnetKeys derives the keys of the testnet nodes. Without a seed or
+// mnemonics, all the keys are random.
+type testnetKeys struct {
+	// seed derives the chain ID, and the operator, consensus and node keys of
+	// the nodes which have no mnemonic.
+	seed string
+	// mnemonics are the mnemonics of the operators of the first nodes, which
+	// also derive their consensus and node keys.
+	mnemonics []string
+}
+
+// loadMnemonics reads the mnemonics of the given file, one per line. Empty lines
+// and lines starting with # are skipped.
+func loadMnemonics(path string) ([]string, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mnemonics []string
+	scanner := bufio.NewScanner(bytes.NewReader(bz))
+	for line := 1; scanner.Scan(); line++ {
+		mnemonic := strings.Join(strings.Fields(scanner.Text()), " ")
+		if mnemonic == "" || strings.HasPrefix(mnemonic, "#") {
+			continue
+		}
+		if !bip39.IsMnemonicValid(mnemonic) {
+			return nil, fmt.Errorf("invalid mnemonic at line %d of %s", line, path)
+		}
+		mnemonics = append(mnemonics, mnemonic)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mnemonics, nil
+}
+
+// deterministic returns whether the keys of the i-th node are derived.
+func (k testnetKeys) deterministic(i int) bool {
+	return i < len(k.mnemonics) || k.seed != ""
+}
+
+// secret returns the secret the keys of the i-th node derive from.
+func (k testnetKeys) secret(i int) string {
+	if i < len(k.mnemonics) {
+		return k.mnemonics[i]
+	}
+	return fmt.Sprintf("%s/%d", k.seed, i)
+}
+
+// chainID returns the chain ID derived from the seed, or an empty string without
+// a seed.
+func (k testnetKeys) chainID() string {
+	if k.seed == "" {
+		return ""
+	}
+	return "chain-" + hex.EncodeToString(deriveSecret("chain-id", k.seed))[:6]
+}
+
+// mnemonic returns the mnemonic of the operator of the i-th node, or an empty
+// string for a random one.
+func (k testnetKeys) mnemonic(i int) (string, error) {
+	if !k.deterministic(i) {
+		return "", nil
+	}
+	if i < len(k.mnemonics) {
+		return k.mnemonics[i], nil
+	}
+	return bip39.NewMnemonic(deriveSecret("operator", k.secret(i)))
+}
+
+// writeNodeValidatorFiles writes the node key and the consensus key of the i-th
+// node, so that genutil.InitializeNodeValidatorFiles loads them instead of
+// generating random ones. Existing keys are overwritten.
+func (k testnetKeys) writeNodeValidatorFiles(config *ostconfig.Config, i int) error {
+	if !k.deterministic(i) {
+		return nil
+	}
+	secret := k.secret(i)
+
+	nodeKey := p2p.NodeKey{PrivKey: ed25519.GenPrivKeyFromSecret(deriveSecret("node", secret))}
+	if err := ostos.EnsureDir(filepath.Dir(config.NodeKeyFile()), 0o777); err != nil {
+		return err
+	}
+	if err := nodeKey.SaveAs(config.NodeKeyFile()); err != nil {
+		return err
+	}
+
+	pvKeyFile := config.PrivValidatorKeyFile()
+	if err := ostos.EnsureDir(filepath.Dir(pvKeyFile), 0o777); err != nil {
+		return err
+	}
+	pvStateFile := config.PrivValidatorStateFile()
+	if err := ostos.EnsureDir(filepath.Dir(pvStateFile), 0o777); err != nil {
+		return err
+	}
+	privKey := ed25519.GenPrivKeyFromSecret(deriveSecret("consensus", secret))
+	privval.NewFilePV(privKey, pvKeyFile, pvStateFile).Save()
+	return nil
+}
+
+// deriveSecret derives the secret of the given purpose, so that the keys of a
+// node are independent of each other.
+func deriveSecret(purpose, secret string) []byte {
+	hash := sha256.Sum256([]byte(purpose + "/" + secret))
+	return hash[:]
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	ostconfig "github.com/Finschia/ostracon/config"
+
+	storetypes "github.com/Finschia/finschia-sdk/store/types"
+)
+
+// testnetNodeRole is the role of a node of the testnet.
+type testnetNodeRole int
+
+const (
+	roleValidator testnetNodeRole = iota
+	// roleSentry nodes connect the validator they guard to the other nodes,
+	// without gossiping its address.
+	roleSentry
+	// roleSeed nodes only crawl the network and share the addresses of the
+	// peers.
+	roleSeed
+	// roleArchive nodes keep all the states.
+	roleArchive
+)
+
+// testnetNode is a node of the generated testnet.
+type testnetNode struct {
+	dirName string
+	id      string
+	ip      string
+	ports   testnetPorts
+	role    testnetNodeRole
+	// validator is the index of the validator the node is, or guards as a
+	// sentry.
+	validator int
+}
+
+func (n testnetNode) peer(host string) string {
+	return fmt.Sprintf("%s@%s:%d", n.id, host, n.ports.P2P)
+}
+
+// serviceName is the name of the container or the service of the node.
+func (n testnetNode) serviceName() string {
+	return "fnsad" + strings.ToLower(n.dirName)
+}
+
+// pruning returns the pruning strategy of the node.
+func (n testnetNode) pruning() string {
+	switch n.role {
+	case roleSeed:
+		return storetypes.PruningOptionEverything
+	case roleArchive:
+		return storetypes.PruningOptionNothing
+	default:
+		return storetypes.PruningOptionDefault
+	}
+}
+
+// configureP2P sets the peers of the i-th node in cfg. A validator guarded by
+// sentries only connects to them, with PEX disabled. Without sentries, the
+// validators keep the persistent peers of the gentxs.
+func configureP2P(cfg *ostconfig.P2PConfig, nodes []testnetNode, i int) {
+	node := nodes[i]
+
+	var sentries, seeds, public []testnetNode
+	for _, peer := range nodes {
+		switch peer.role {
+		case roleSentry:
+			sentries = append(sentries, peer)
+		case roleSeed:
+			seeds = append(seeds, peer)
+		}
+	}
+	// the nodes the full nodes connect to
+	public = sentries
+	if len(public) == 0 {
+		for _, peer := range nodes {
+			if peer.role == roleValidator {
+				public = append(public, peer)
+			}
+		}
+	}
+
+	cfg.PexReactor = true
+	cfg.SeedMode = false
+	cfg.PrivatePeerIDs = ""
+	cfg.UnconditionalPeerIDs = ""
+	cfg.Seeds = peerList(seeds, nil)
+
+	switch node.role {
+	case roleValidator:
+		var guards []testnetNode
+		for _, sentry := range sentries {
+			if sentry.validator == node.validator {
+				guards = append(guards, sentry)
+			}
+		}
+		if len(guards) != 0 {
+			cfg.PexReactor = false
+			cfg.Seeds = ""
+			cfg.PersistentPeers = peerList(guards, nil)
+			cfg.UnconditionalPeerIDs = peerIDs(guards)
+		}
+	case roleSentry:
+		validator := nodes[node.validator]
+		cfg.PersistentPeers = peerList(append([]testnetNode{validator}, sentries...), &node)
+		cfg.PrivatePeerIDs = validator.id
+		cfg.UnconditionalPeerIDs = validator.id
+	case roleSeed:
+		cfg.SeedMode = true
+		cfg.Seeds = peerList(seeds, &node)
+		cfg.PersistentPeers = peerList(public, nil)
+	case roleArchive:
+		cfg.PersistentPeers = peerList(public, nil)
+	}
+}
+
+// peerList returns the comma separated addresses of the peers, except self.
+func peerList(peers []testnetNode, self *testnetNode) string {
+	addrs := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if self != nil && peer.id == self.id {
+			continue
+		}
+		addrs = append(addrs, peer.peer(peer.ip))
+	}
+	return strings.Join(addrs, ",")
+}
+
+func peerIDs(peers []testnetNode) string {
+	ids := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		ids = append(ids, peer.id)
+	}
+	return strings.Join(ids, ",")
+}
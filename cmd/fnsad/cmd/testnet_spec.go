@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Finschia/finschia-sdk/client"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/module"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+)
+
+const (
+	defaultP2PPort     = 26656
+	defaultRPCPort     = 26657
+	defaultAPIPort     = 1317
+	defaultGRPCPort    = 9090
+	defaultGRPCWebPort = 9091
+)
+
+// testnetSpec describes the testnet generated by the testnet command. It is read
+// from the file given by --config, in YAML or JSON, e.g.
+//
+//	chain_id: localnet
+//	starting_ip_address: 127.0.0.1
+//	port_offset: 10
+//	validators:
+//	  - balances: 1000000000000stake
+//	    self_delegation: 100000000stake
+//	    commission: {rate: "0.1", max_rate: "0.2", max_change_rate: "0.01"}
+//	  - {}
+//	sentries_per_validator: 1
+//	seed_nodes: 1
+//	archive_nodes: 1
+//	accounts:
+//	  - address: link1...
+//	    balances: 1000000stake
+//	genesis:
+//	  gov:
+//	    voting_params: {voting_period: 60s}
+type testnetSpec struct {
+	// ChainID is used unless --chain-id is given. A random one is used if
+	// neither is given.
+	ChainID string `json:"chain_id"`
+	// StartingIPAddress is the IP address of the first node, incremented for
+	// each of the following nodes unless PortOffset is set. It defaults to
+	// --starting-ip-address.
+	StartingIPAddress string `json:"starting_ip_address"`
+	// PortOffset shifts the ports of each node from the ones of the previous
+	// node. Once set, all the nodes listen on StartingIPAddress, so that they
+	// can run on a single host.
+	PortOffset int `json:"port_offset"`
+	// P2PPort and RPCPort are the ports of the first node. They default to 26656
+	// and 26657.
+	P2PPort int `json:"p2p_port"`
+	RPCPort int `json:"rpc_port"`
+
+	Validators []testnetValidatorSpec `json:"validators"`
+	// SentriesPerValidator sentry nodes are generated for each validator, which
+	// then only connects to its sentries.
+	SentriesPerValidator int `json:"sentries_per_validator"`
+	// SeedNodes and ArchiveNodes are full nodes generated besides the validators
+	// and their sentries. Archive nodes keep all the states.
+	SeedNodes    int `json:"seed_nodes"`
+	ArchiveNodes int `json:"archive_nodes"`
+	// Accounts are funded in genesis besides the validator operators.
+	Accounts []testnetAccountSpec `json:"accounts"`
+	// Genesis holds JSON merge patches (RFC 7386) applied to the default genesis
+	// of the modules, keyed by module name.
+	Genesis map[string]json.RawMessage `json:"genesis"`
+}
+
+type testnetValidatorSpec struct {
+	// Balances of the operator account, as coins. They default to 1000 power of
+	// "<node-dir>token" and 500 power of the bond denom.
+	Balances string `json:"balances"`
+	// SelfDelegation is the coin bonded by the gentx of the validator. It
+	// defaults to 100 power of the bond denom.
+	SelfDelegation string `json:"self_delegation"`
+	// Commission defaults to 100% rates.
+	Commission *testnetCommissionSpec `json:"commission"`
+}
+
+type testnetCommissionSpec struct {
+	Rate          string `json:"rate"`
+	MaxRate       string `json:"max_rate"`
+	MaxChangeRate string `json:"max_change_rate"`
+}
+
+type testnetAccountSpec struct {
+	Address  string `json:"address"`
+	Balances string `json:"balances"`
+}
+
+// testnetPorts are the ports a node listens on.
+type testnetPorts struct {
+	P2P, RPC, API, GRPC, GRPCWeb int
+}
+
+// newTestnetSpec returns the spec of numValidators validators with the default
+// settings, as generated without --config.
+func newTestnetSpec(numValidators int, startingIPAddress string) testnetSpec {
+	spec := testnetSpec{
+		StartingIPAddress: startingIPAddress,
+		Validators:        make([]testnetValidatorSpec, numValidators),
+	}
+	spec.setDefaults(startingIPAddress)
+	return spec
+}
+
+// loadTestnetSpec reads the spec from the given YAML or JSON file.
+func loadTestnetSpec(path, startingIPAddress string) (testnetSpec, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return testnetSpec{}, err
+	}
+
+	// YAML is a superset of JSON, and goes through JSON so that a single set of
+	// field names applies to both.
+	var raw interface{}
+	if err := yaml.Unmarshal(bz, &raw); err != nil {
+		return testnetSpec{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	jsonBz, err := json.Marshal(raw)
+	if err != nil {
+		return testnetSpec{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var spec testnetSpec
+	decoder := json.NewDecoder(bytes.NewReader(jsonBz))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return testnetSpec{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(spec.Validators) == 0 {
+		return testnetSpec{}, fmt.Errorf("no validators in %s", path)
+	}
+
+	spec.setDefaults(startingIPAddress)
+	return spec, nil
+}
+
+func (s *testnetSpec) setDefaults(startingIPAddress string) {
+	if s.StartingIPAddress == "" {
+		s.StartingIPAddress = startingIPAddress
+	}
+	if s.P2PPort == 0 {
+		s.P2PPort = defaultP2PPort
+	}
+	if s.RPCPort == 0 {
+		s.RPCPort = defaultRPCPort
+	}
+}
+
+func (s testnetSpec) validateNodes() error {
+	if s.SentriesPerValidator < 0 || s.SeedNodes < 0 || s.ArchiveNodes < 0 {
+		return fmt.Errorf("negative number of sentry, seed or archive nodes")
+	}
+	return nil
+}
+
+// numNodes returns the number of the nodes of the testnet, validators or not.
+func (s testnetSpec) numNodes() int {
+	return len(s.Validators)*(1+s.SentriesPerValidator) + s.SeedNodes + s.ArchiveNodes
+}
+
+// role returns the role of the i-th node, and the index of the validator it is
+// or guards as a sentry. The validators come first, followed by the sentries of
+// each validator in turn, the seed nodes and the archive nodes.
+func (s testnetSpec) role(i int) (testnetNodeRole, int) {
+	numValidators := len(s.Validators)
+	if i < numValidators {
+		return roleValidator, i
+	}
+	i -= numValidators
+
+	numSentries := numValidators * s.SentriesPerValidator
+	if i < numSentries {
+		return roleSentry, i / s.SentriesPerValidator
+	}
+	i -= numSentries
+
+	if i < s.SeedNodes {
+		return roleSeed, -1
+	}
+	return roleArchive, -1
+}
+
+// ip returns the IP address of the i-th node.
+func (s testnetSpec) ip(i int) (string, error) {
+	if s.PortOffset != 0 {
+		i = 0
+	}
+	return getIP(i, s.StartingIPAddress)
+}
+
+// ports returns the ports of the i-th node.
+func (s testnetSpec) ports(i int) testnetPorts {
+	offset := i * s.PortOffset
+	return testnetPorts{
+		P2P:     s.P2PPort + offset,
+		RPC:     s.RPCPort + offset,
+		API:     defaultAPIPort + offset,
+		GRPC:    defaultGRPCPort + offset,
+		GRPCWeb: defaultGRPCWebPort + offset,
+	}
+}
+
+// balances returns the balances of the operator of the validator of the given
+// node directory.
+func (v testnetValidatorSpec) balances(nodeDirName string) (sdk.Coins, error) {
+	if v.Balances == "" {
+		return sdk.NewCoins(
+			sdk.NewCoin(fmt.Sprintf("%stoken", nodeDirName), sdk.TokensFromConsensusPower(1000, sdk.DefaultPowerReduction)),
+			sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromConsensusPower(500, sdk.DefaultPowerReduction)),
+		), nil
+	}
+	coins, err := sdk.ParseCoinsNormalized(v.Balances)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balances of %s: %w", nodeDirName, err)
+	}
+	return coins, nil
+}
+
+func (v testnetValidatorSpec) selfDelegation(nodeDirName string) (sdk.Coin, error) {
+	if v.SelfDelegation == "" {
+		return sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromConsensusPower(100, sdk.DefaultPowerReduction)), nil
+	}
+	coin, err := sdk.ParseCoinNormalized(v.SelfDelegation)
+	if err != nil {
+		return sdk.Coin{}, fmt.Errorf("invalid self delegation of %s: %w", nodeDirName, err)
+	}
+	return coin, nil
+}
+
+func (v testnetValidatorSpec) commissionRates(nodeDirName string) (stakingtypes.CommissionRates, error) {
+	if v.Commission == nil {
+		return stakingtypes.NewCommissionRates(sdk.OneDec(), sdk.OneDec(), sdk.OneDec()), nil
+	}
+
+	var rates [3]sdk.Dec
+	for i, rate := range []string{v.Commission.Rate, v.Commission.MaxRate, v.Commission.MaxChangeRate} {
+		dec, err := sdk.NewDecFromStr(rate)
+		if err != nil {
+			return stakingtypes.CommissionRates{}, fmt.Errorf("invalid commission of %s: %w", nodeDirName, err)
+		}
+		rates[i] = dec
+	}
+	commission := stakingtypes.NewCommissionRates(rates[0], rates[1], rates[2])
+	if err := commission.Validate(); err != nil {
+		return stakingtypes.CommissionRates{}, fmt.Errorf("invalid commission of %s: %w", nodeDirName, err)
+	}
+	return commission, nil
+}
+
+// patchGenesis applies the genesis patches of the spec to appGenState, and
+// validates the patched genesis of the modules.
+func (s testnetSpec) patchGenesis(clientCtx client.Context, mbm module.BasicManager, appGenState map[string]json.RawMessage) error {
+	moduleNames := make([]string, 0, len(s.Genesis))
+	for moduleName := range s.Genesis {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	for _, moduleName := range moduleNames {
+		basic, ok := mbm[moduleName]
+		if !ok {
+			return fmt.Errorf("genesis patch of unknown module %q", moduleName)
+		}
+
+		patched, err := mergeJSONPatch(appGenState[moduleName], s.Genesis[moduleName])
+		if err != nil {
+			return fmt.Errorf("failed to patch genesis of %s: %w", moduleName, err)
+		}
+		if err := basic.ValidateGenesis(clientCtx.Codec, clientCtx.TxConfig, patched); err != nil {
+			return fmt.Errorf("invalid patched genesis of %s: %w", moduleName, err)
+		}
+		appGenState[moduleName] = patched
+	}
+	return nil
+}
+
+// mergeJSONPatch applies the JSON merge patch (RFC 7386) to target.
+func mergeJSONPatch(target, patch json.RawMessage) (json.RawMessage, error) {
+	var targetValue, patchValue interface{}
+	if len(target) != 0 {
+		if err := unmarshalJSONNumbers(target, &targetValue); err != nil {
+			return nil, err
+		}
+	}
+	if err := unmarshalJSONNumbers(patch, &patchValue); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatchValue(targetValue, patchValue))
+}
+
+func mergePatchValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatchValue(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// unmarshalJSONNumbers works like json.Unmarshal, but keeps numbers as they are
+// instead of converting them to float64.
+func unmarshalJSONNumbers(bz []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
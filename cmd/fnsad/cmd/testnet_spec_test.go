@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTestnetSpec(t *testing.T) {
+	testCases := map[string]struct {
+		spec  string
+		valid bool
+	}{
+		"yaml": {
+			spec:  "validators:\n  - balances: 1stake\n  - {}\n",
+			valid: true,
+		},
+		"json": {
+			spec:  `{"validators": [{"balances": "1stake"}, {}]}`,
+			valid: true,
+		},
+		"unknown field": {
+			spec: "validators:\n  - {}\nnosuchfield: 1\n",
+		},
+		"no validators": {
+			spec: "chain_id: test\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec")
+			require.NoError(t, os.WriteFile(path, []byte(tc.spec), 0o600))
+
+			spec, err := loadTestnetSpec(path, "192.168.0.1")
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, spec.Validators, 2)
+			require.Equal(t, "1stake", spec.Validators[0].Balances)
+			require.Equal(t, "192.168.0.1", spec.StartingIPAddress)
+			require.Equal(t, testnetPorts{P2P: 26656, RPC: 26657, API: 1317, GRPC: 9090, GRPCWeb: 9091}, spec.ports(1))
+		})
+	}
+}
+
+func TestMergeJSONPatch(t *testing.T) {
+	testCases := map[string]struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		"nested": {
+			target:   `{"a":{"b":"1","c":"2"},"d":3}`,
+			patch:    `{"a":{"b":"4"}}`,
+			expected: `{"a":{"b":"4","c":"2"},"d":3}`,
+		},
+		"remove": {
+			target:   `{"a":1,"b":2}`,
+			patch:    `{"a":null}`,
+			expected: `{"b":2}`,
+		},
+		"replace array": {
+			target:   `{"a":[1,2]}`,
+			patch:    `{"a":[3]}`,
+			expected: `{"a":[3]}`,
+		},
+		"large number": {
+			target:   `{"a":100000000000000000001}`,
+			patch:    `{}`,
+			expected: `{"a":100000000000000000001}`,
+		},
+		"no target": {
+			patch:    `{"a":1}`,
+			expected: `{"a":1}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			patched, err := mergeJSONPatch([]byte(tc.target), []byte(tc.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(patched))
+		})
+	}
+}
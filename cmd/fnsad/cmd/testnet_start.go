@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/Finschia/ostracon/libs/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/server"
+
+	"github.com/Finschia/finschia/app/helpers"
+)
+
+const (
+	flagBlockTime     = "block-time"
+	flagEnableLogging = "enable-logging"
+)
+
+// testnetStartCmd runs a network of validators in the current process, e.g. for
+// integration tests, until it is interrupted.
+func testnetStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Run a network of validators in a single process",
+		Long: `start runs "v" validators in the current process, keeping their state in
+memory, until it is interrupted. The nodes connect to each other over the
+loopback interface, and every validator serves RPC, REST and gRPC on ephemeral
+ports, which are printed once the network is up. The keyring of each validator
+operator is written to its node directory with the test backend.
+
+Example:
+	fnsad testnet start --v 4 --block-time 500ms
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			outputDir, _ := cmd.Flags().GetString(flagOutputDir)       // nolint: errcheck
+			chainID, _ := cmd.Flags().GetString(flags.FlagChainID)     // nolint: errcheck
+			numValidators, _ := cmd.Flags().GetInt(flagNumValidators)  // nolint: errcheck
+			blockTime, _ := cmd.Flags().GetDuration(flagBlockTime)     // nolint: errcheck
+			enableLogging, _ := cmd.Flags().GetBool(flagEnableLogging) // nolint: errcheck
+
+			if outputDir == "" {
+				dir, err := os.MkdirTemp("", "fnsad-testnet-")
+				if err != nil {
+					return err
+				}
+				defer os.RemoveAll(dir)
+				outputDir = dir
+			}
+
+			cfg := helpers.DefaultNetworkConfig()
+			cfg.NumValidators = numValidators
+			cfg.TimeoutCommit = blockTime
+			if chainID != "" {
+				cfg.ChainID = chainID
+			}
+			if enableLogging {
+				cfg.Logger = log.NewOCLogger(log.NewSyncWriter(cmd.ErrOrStderr()))
+			}
+
+			network, err := helpers.NewNetwork(outputDir, cfg)
+			if err != nil {
+				return err
+			}
+			defer network.Stop()
+
+			if _, err := network.WaitForHeight(1, 10*blockTime+time.Minute); err != nil {
+				return err
+			}
+
+			cmd.Printf("chain-id: %s\n", cfg.ChainID)
+			for _, val := range network.Validators {
+				cmd.Printf("%s: %s (%s)\n", val.Moniker, val.Address, val.Dir)
+				cmd.Printf("  rpc:  %s\n", val.RPCAddress)
+				cmd.Printf("  rest: %s\n", val.APIAddress)
+				cmd.Printf("  grpc: %s\n", val.GRPCAddress)
+			}
+			cmd.PrintErrln("Press Ctrl+C to stop the network")
+
+			server.WaitForQuitSignals()
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flagNumValidators, 4, "Number of validators to run")
+	cmd.Flags().StringP(flagOutputDir, "o", "", "Directory to store the files of the nodes (a temporary one removed on exit if empty)")
+	cmd.Flags().String(flags.FlagChainID, "", "Chain-id of the network, if left blank will be randomly created")
+	cmd.Flags().Duration(flagBlockTime, time.Second, "Time to wait after a block is committed before starting the next one")
+	cmd.Flags().Bool(flagEnableLogging, false, "Write the logs of the nodes to stderr")
+
+	return cmd
+}
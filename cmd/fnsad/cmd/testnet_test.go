@@ -1,25 +1,38 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Finschia/finschia-sdk/client"
 	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
 	"github.com/Finschia/finschia-sdk/server"
+	sdk "github.com/Finschia/finschia-sdk/types"
 	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
 	genutiltest "github.com/Finschia/finschia-sdk/x/genutil/client/testutil"
 	genutiltypes "github.com/Finschia/finschia-sdk/x/genutil/types"
+	govtypes "github.com/Finschia/finschia-sdk/x/gov/types"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+	ostconfig "github.com/Finschia/ostracon/config"
 	"github.com/Finschia/ostracon/libs/log"
+	"github.com/Finschia/ostracon/p2p"
+	"github.com/Finschia/ostracon/privval"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/Finschia/finschia/app"
 )
 
-func Test_TestnetCmd(t *testing.T) {
-	home := t.TempDir()
+func execTestnetCmd(t *testing.T, home string, args ...string) *ostconfig.Config {
 	encodingConfig := app.MakeEncodingConfig()
 	logger := log.NewNopLogger()
 	cfg, err := genutiltest.CreateDefaultTendermintConfig(home)
@@ -38,10 +51,18 @@ func Test_TestnetCmd(t *testing.T) {
 	ctx = context.WithValue(ctx, server.ServerContextKey, serverCtx)
 	ctx = context.WithValue(ctx, client.ClientContextKey, &clientCtx)
 	cmd := testnetCmd(app.ModuleBasics, banktypes.GenesisBalancesIterator{})
-	cmd.SetArgs([]string{fmt.Sprintf("--%s=test", flags.FlagKeyringBackend), fmt.Sprintf("--output-dir=%s", home)})
+	cmd.SetArgs(append([]string{fmt.Sprintf("--%s=test", flags.FlagKeyringBackend), fmt.Sprintf("--output-dir=%s", home)}, args...))
 	err = cmd.ExecuteContext(ctx)
 	require.NoError(t, err)
 
+	return cfg
+}
+
+func Test_TestnetCmd(t *testing.T) {
+	home := t.TempDir()
+	encodingConfig := app.MakeEncodingConfig()
+	cfg := execTestnetCmd(t, home)
+
 	genFile := cfg.GenesisFile()
 	appState, _, err := genutiltypes.GenesisStateFromGenFile(genFile)
 	require.NoError(t, err)
@@ -49,3 +70,278 @@ func Test_TestnetCmd(t *testing.T) {
 	bankGenState := banktypes.GetGenesisStateFromAppState(encodingConfig.Marshaler, appState)
 	require.NotEmpty(t, bankGenState.Supply.String())
 }
+
+func Test_TestnetCmdWithConfig(t *testing.T) {
+	home := t.TempDir()
+	encodingConfig := app.MakeEncodingConfig()
+	account := sdk.AccAddress("account_____________")
+
+	spec := fmt.Sprintf(`chain_id: spec-chain
+starting_ip_address: 127.0.0.1
+port_offset: 10
+validators:
+  - balances: 2000000000stake
+    self_delegation: 1000000000stake
+    commission: {rate: "0.1", max_rate: "0.2", max_change_rate: "0.01"}
+  - {}
+accounts:
+  - address: %s
+    balances: 42stake
+genesis:
+  gov:
+    voting_params: {voting_period: 60s}
+`, account)
+	specFile := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte(spec), 0o600))
+
+	execTestnetCmd(t, home, fmt.Sprintf("--%s=%s", flagConfig, specFile))
+
+	nodeConfig := ostconfig.DefaultConfig()
+	nodeConfig.SetRoot(filepath.Join(home, "node1", "finschia"))
+	appState, genDoc, err := genutiltypes.GenesisStateFromGenFile(nodeConfig.GenesisFile())
+	require.NoError(t, err)
+	require.Equal(t, "spec-chain", genDoc.ChainID)
+
+	bankGenState := banktypes.GetGenesisStateFromAppState(encodingConfig.Marshaler, appState)
+	var found bool
+	for _, balance := range bankGenState.Balances {
+		if balance.Address == account.String() {
+			require.Equal(t, "42stake", balance.Coins.String())
+			found = true
+		}
+	}
+	require.True(t, found)
+
+	var govGenState govtypes.GenesisState
+	encodingConfig.Marshaler.MustUnmarshalJSON(appState[govtypes.ModuleName], &govGenState)
+	require.Equal(t, time.Minute, govGenState.VotingParams.VotingPeriod)
+
+	genutilGenState := genutiltypes.GetGenesisStateFromAppState(encodingConfig.Marshaler, appState)
+	require.Len(t, genutilGenState.GenTxs, 2)
+	commissions := map[string]bool{}
+	for _, genTx := range genutilGenState.GenTxs {
+		tx, err := encodingConfig.TxConfig.TxJSONDecoder()(genTx)
+		require.NoError(t, err)
+		msg := tx.GetMsgs()[0].(*stakingtypes.MsgCreateValidator)
+		commissions[msg.Commission.Rate.String()] = true
+	}
+	require.Equal(t, map[string]bool{"0.100000000000000000": true, "1.000000000000000000": true}, commissions)
+
+	// the ports of the second node are shifted by the offset
+	bz, err := os.ReadFile(filepath.Join(nodeConfig.RootDir, "config", "config.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `laddr = "tcp://0.0.0.0:26666"`)
+	require.Contains(t, string(bz), `laddr = "tcp://0.0.0.0:26667"`)
+	require.Contains(t, string(bz), "@127.0.0.1:26656")
+	bz, err = os.ReadFile(filepath.Join(nodeConfig.RootDir, "config", "app.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `address = "0.0.0.0:9100"`)
+}
+
+func Test_TestnetCmdWithSeed(t *testing.T) {
+	type node struct {
+		nodeID    string
+		consensus string
+		operator  string
+	}
+	readNodes := func(home string) (string, []node) {
+		var chainID string
+		nodes := make([]node, 2)
+		for i := range nodes {
+			nodeConfig := ostconfig.DefaultConfig()
+			nodeConfig.SetRoot(filepath.Join(home, fmt.Sprintf("node%d", i), "finschia"))
+
+			_, genDoc, err := genutiltypes.GenesisStateFromGenFile(nodeConfig.GenesisFile())
+			require.NoError(t, err)
+			chainID = genDoc.ChainID
+
+			nodeKey, err := p2p.LoadNodeKey(nodeConfig.NodeKeyFile())
+			require.NoError(t, err)
+			pv := privval.LoadFilePV(nodeConfig.PrivValidatorKeyFile(), nodeConfig.PrivValidatorStateFile())
+
+			kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, nodeConfig.RootDir, nil)
+			require.NoError(t, err)
+			info, err := kb.Key(fmt.Sprintf("node%d", i))
+			require.NoError(t, err)
+
+			nodes[i] = node{
+				nodeID:    string(nodeKey.ID()),
+				consensus: pv.GetAddress().String(),
+				operator:  info.GetAddress().String(),
+			}
+			_, err = os.Stat(filepath.Join(nodeConfig.RootDir, "key_seed.json"))
+			require.True(t, os.IsNotExist(err))
+		}
+		return chainID, nodes
+	}
+
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	mnemonicFile := filepath.Join(t.TempDir(), "mnemonics")
+	require.NoError(t, os.WriteFile(mnemonicFile, []byte("# operator of node0\n"+mnemonic+"\n"), 0o600))
+
+	testCases := map[string]struct {
+		args []string
+	}{
+		"seed": {
+			args: []string{"--seed=ci"},
+		},
+		"seed and mnemonic file": {
+			args: []string{"--seed=ci", fmt.Sprintf("--%s=%s", flagMnemonicFile, mnemonicFile)},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args := append([]string{"--v=2", "--no-key-seed"}, tc.args...)
+			home1, home2 := t.TempDir(), t.TempDir()
+			execTestnetCmd(t, home1, args...)
+			execTestnetCmd(t, home2, args...)
+
+			chainID1, nodes1 := readNodes(home1)
+			chainID2, nodes2 := readNodes(home2)
+			require.Equal(t, chainID1, chainID2)
+			require.Equal(t, nodes1, nodes2)
+			require.NotEqual(t, nodes1[0], nodes1[1])
+		})
+	}
+
+	// the mnemonic file overrides the seed of the first node only
+	home1, home2 := t.TempDir(), t.TempDir()
+	execTestnetCmd(t, home1, "--v=2", "--no-key-seed", "--seed=ci")
+	execTestnetCmd(t, home2, "--v=2", "--no-key-seed", "--seed=ci", fmt.Sprintf("--%s=%s", flagMnemonicFile, mnemonicFile))
+	_, nodes1 := readNodes(home1)
+	_, nodes2 := readNodes(home2)
+	require.NotEqual(t, nodes1[0], nodes2[0])
+	info, err := keyring.NewInMemory().NewAccount("operator", mnemonic, "", sdk.GetConfig().GetFullBIP44Path(), hd.Secp256k1)
+	require.NoError(t, err)
+	require.Equal(t, info.GetAddress().String(), nodes2[0].operator)
+	require.Equal(t, nodes1[1], nodes2[1])
+}
+
+func Test_TestnetCmdEmit(t *testing.T) {
+	home := t.TempDir()
+	execTestnetCmd(t, home, "--v=2", "--starting-ip-address=192.168.10.2", fmt.Sprintf("--%s=%s,%s", flagEmit, emitDockerCompose, emitK8s))
+
+	nodeIDs := make([]string, 2)
+	for i := range nodeIDs {
+		nodeKey, err := p2p.LoadNodeKey(filepath.Join(home, fmt.Sprintf("node%d", i), "finschia", "config", "node_key.json"))
+		require.NoError(t, err)
+		nodeIDs[i] = string(nodeKey.ID())
+	}
+
+	bz, err := os.ReadFile(filepath.Join(home, "docker-compose.yml"))
+	require.NoError(t, err)
+	var compose struct {
+		Services map[string]struct {
+			Environment []string `yaml:"environment"`
+			Ports       []string `yaml:"ports"`
+			Labels      map[string]string
+			Networks    map[string]struct {
+				IPv4Address string `yaml:"ipv4_address"`
+			}
+		}
+	}
+	require.NoError(t, yaml.Unmarshal(bz, &compose))
+	require.Len(t, compose.Services, 2)
+	node1 := compose.Services["fnsadnode1"]
+	require.Equal(t, "192.168.10.3", node1.Networks["localnet"].IPv4Address)
+	require.Contains(t, node1.Environment, "NODE_HOME=/data/node1/finschia")
+	require.Equal(t, []string{"26667:26657", "1327:1317", "9100:9090", "9101:9091"}, node1.Ports)
+	require.Equal(t, "1317", node1.Labels["prometheus.io/port"])
+
+	bz, err = os.ReadFile(filepath.Join(home, "k8s.yaml"))
+	require.NoError(t, err)
+	decoder := yaml.NewDecoder(bytes.NewReader(bz))
+	secrets := map[string]map[string]string{}
+	kinds := map[string]int{}
+	for {
+		var doc struct {
+			Kind     string
+			Metadata struct {
+				Name string
+			}
+			StringData map[string]string `yaml:"stringData"`
+		}
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		kinds[doc.Kind]++
+		if doc.Kind == "Secret" {
+			secrets[doc.Metadata.Name] = doc.StringData
+		}
+	}
+	require.Equal(t, map[string]int{"Secret": 2, "Service": 2, "StatefulSet": 2}, kinds)
+	configToml := secrets["fnsadnode1"]["config.toml"]
+	require.Contains(t, configToml, fmt.Sprintf("%s@fnsadnode0:26656", nodeIDs[0]))
+	require.NotContains(t, configToml, "192.168.10.2")
+	require.Contains(t, secrets["fnsadnode1"]["priv_validator_key.json"], "priv_key")
+}
+
+func Test_TestnetCmdWithFullNodes(t *testing.T) {
+	home := t.TempDir()
+	execTestnetCmd(t, home, "--v=2", "--starting-ip-address=192.168.10.2",
+		fmt.Sprintf("--%s=1", flagSentries), fmt.Sprintf("--%s=1", flagSeedNodes), fmt.Sprintf("--%s=1", flagArchiveNodes))
+
+	// node0 and node1 are validators guarded by node2 and node3, followed by the
+	// seed node4 and the archive node5
+	nodeIDs := make([]string, 6)
+	configs := make([]*ostconfig.Config, 6)
+	pruning := make([]string, 6)
+	var genDocs []string
+	for i := range nodeIDs {
+		nodeDir := filepath.Join(home, fmt.Sprintf("node%d", i), "finschia")
+		nodeKey, err := p2p.LoadNodeKey(filepath.Join(nodeDir, "config", "node_key.json"))
+		require.NoError(t, err)
+		nodeIDs[i] = string(nodeKey.ID())
+
+		v := viper.New()
+		v.SetConfigFile(filepath.Join(nodeDir, "config", "config.toml"))
+		require.NoError(t, v.ReadInConfig())
+		configs[i] = ostconfig.DefaultConfig()
+		require.NoError(t, v.Unmarshal(configs[i]))
+
+		v = viper.New()
+		v.SetConfigFile(filepath.Join(nodeDir, "config", "app.toml"))
+		require.NoError(t, v.ReadInConfig())
+		pruning[i] = v.GetString("pruning")
+
+		bz, err := os.ReadFile(filepath.Join(nodeDir, "config", "genesis.json"))
+		require.NoError(t, err)
+		genDocs = append(genDocs, string(bz))
+	}
+	peer := func(i int) string {
+		return fmt.Sprintf("%s@192.168.10.%d:26656", nodeIDs[i], i+2)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(home, "gentxs"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for i := 1; i < len(genDocs); i++ {
+		require.Equal(t, genDocs[0], genDocs[i])
+	}
+
+	validator := configs[0].P2P
+	require.False(t, validator.PexReactor)
+	require.Equal(t, peer(2), validator.PersistentPeers)
+	require.Equal(t, nodeIDs[2], validator.UnconditionalPeerIDs)
+
+	sentry := configs[3].P2P
+	require.True(t, sentry.PexReactor)
+	require.Equal(t, peer(1)+","+peer(2), sentry.PersistentPeers)
+	require.Equal(t, nodeIDs[1], sentry.PrivatePeerIDs)
+	require.Equal(t, nodeIDs[1], sentry.UnconditionalPeerIDs)
+	require.Equal(t, peer(4), sentry.Seeds)
+
+	seed := configs[4].P2P
+	require.True(t, seed.SeedMode)
+	require.Equal(t, peer(2)+","+peer(3), seed.PersistentPeers)
+
+	archive := configs[5].P2P
+	require.False(t, archive.SeedMode)
+	require.Equal(t, peer(2)+","+peer(3), archive.PersistentPeers)
+	require.Equal(t, peer(4), archive.Seeds)
+
+	require.Equal(t, []string{"default", "default", "default", "default", "everything", "nothing"}, pruning)
+}
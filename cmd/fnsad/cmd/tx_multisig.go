@@ -0,0 +1,662 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/codec"
+	cryptotypes "github.com/Finschia/finschia-sdk/crypto/types"
+	"github.com/Finschia/finschia-sdk/crypto/types/multisig"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/tx/signing"
+	authclient "github.com/Finschia/finschia-sdk/x/auth/client"
+	authsigning "github.com/Finschia/finschia-sdk/x/auth/signing"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+)
+
+// multisigSignMode is the sign mode of the signatures of a session, as the
+// sign bytes of SIGN_MODE_DIRECT depend on the signatures of the others.
+const multisigSignMode = signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON
+
+// MultisigCmd returns the commands to collect the signatures of a transaction
+// of legacy multisig keys or several signers in a session file.
+func MultisigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "multisig",
+		Short: "Collect the signatures of a transaction of multisig keys or several signers in a session file",
+		Long: `Collect the signatures of a transaction in a session file, instead of passing
+the signature files of 'fnsad tx multisign' around. A session is created from an
+unsigned transaction, its signers or the members of its legacy multisig signers
+sign the session file or add their signature files to it, and the transaction
+is broadcast once every signer has its threshold of signatures.
+
+Transactions of several signers, e.g. foundation proposals of several proposing
+members, are signed the same way.`,
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		multisigCreateCmd(),
+		multisigSignCmd(),
+		multisigAddCmd(),
+		multisigStatusCmd(),
+		multisigBroadcastCmd(),
+	)
+
+	return cmd
+}
+
+func multisigCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [unsigned-tx-file] [session-file]",
+		Short: "Create a session of an unsigned transaction",
+		Long: `Create a session file of an unsigned transaction generated by --generate-only.
+The account numbers and the sequences of the signers are queried, and their
+public keys are taken from the keyring, e.g. multisig keys added by
+'fnsad keys add --multisig', or from their accounts. The signers of foundation
+messages must be members of the foundation.
+
+Example:
+$ fnsad tx bank send <multisig address> <to address> 1000cony --generate-only > unsigned.json
+$ fnsad tx multisig create unsigned.json session.json
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			if clientCtx.ChainID == "" {
+				return fmt.Errorf("--%s is required", flags.FlagChainID)
+			}
+			if _, err := os.Stat(args[1]); err == nil {
+				return fmt.Errorf("session file %s already exists", args[1])
+			}
+
+			stdTx, err := authclient.ReadTxFromFile(clientCtx, args[0])
+			if err != nil {
+				return err
+			}
+			unsignedTx, ok := stdTx.(authsigning.Tx)
+			if !ok {
+				return fmt.Errorf("unexpected transaction %T", stdTx)
+			}
+			if err := validateFoundationMembers(cmd, clientCtx, unsignedTx.GetMsgs()); err != nil {
+				return err
+			}
+
+			session, err := newMultisigSession(clientCtx, unsignedTx)
+			if err != nil {
+				return err
+			}
+			return session.save(args[1])
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|kwallet|pass|test|memory)")
+	cmd.Flags().String(flags.FlagKeyringDir, "", "The client Keyring directory; if omitted, the default 'home' directory will be used")
+
+	return cmd
+}
+
+func multisigSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign [session-file]",
+		Short: "Sign a session with a key of a signer or a member of a multisig signer",
+		Long: `Sign the transaction of a session with a key of the keyring, which is a signer
+of the transaction or a member of its multisig signer, and add the signature to
+the session file. The node is never accessed.
+
+Example:
+$ fnsad tx multisig sign session.json --from member1
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			from, _ := cmd.Flags().GetString(flags.FlagFrom) // nolint: errcheck
+			if from == "" {
+				return fmt.Errorf("--%s is required", flags.FlagFrom)
+			}
+			info, err := clientCtx.Keyring.Key(from)
+			if err != nil {
+				return err
+			}
+
+			session, unsignedTx, err := loadMultisigSession(clientCtx.TxConfig, args[0])
+			if err != nil {
+				return err
+			}
+			indexes, err := session.signersOf(clientCtx.Codec, info.GetPubKey())
+			if err != nil {
+				return err
+			}
+			if len(indexes) == 0 {
+				return fmt.Errorf("%s is neither a signer nor a member of a multisig signer of the session", info.GetAddress())
+			}
+
+			for _, i := range indexes {
+				signBytes, err := session.signBytes(clientCtx.TxConfig, unsignedTx, i)
+				if err != nil {
+					return err
+				}
+				sig, pubKey, err := clientCtx.Keyring.Sign(from, signBytes)
+				if err != nil {
+					return err
+				}
+				if err := session.addSignature(clientCtx.Codec, i, signBytes, pubKey, sig); err != nil {
+					return err
+				}
+				cmd.PrintErrf("signed for %s\n", session.Signers[i].Address)
+			}
+			return session.save(args[0])
+		},
+	}
+
+	cmd.Flags().String(flags.FlagFrom, "", "Name of the key to sign with in the keyring")
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|kwallet|pass|test|memory)")
+	cmd.Flags().String(flags.FlagKeyringDir, "", "The client Keyring directory; if omitted, the default 'home' directory will be used")
+
+	return cmd
+}
+
+func multisigAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add [session-file] [signature-file]...",
+		Short: "Add the signatures of signature files to a session",
+		Long: fmt.Sprintf(`Add the signatures of the signature files written by 'fnsad tx sign
+--signature-only --sign-mode %s' to a session. Each signature must be of a
+signer of the transaction or a member of its multisig signer, and of the sign
+bytes of the session.
+
+Example:
+$ fnsad tx sign unsigned.json --from member2 --multisig <multisig address> --sign-mode %[1]s --signature-only > member2.json
+$ fnsad tx multisig add session.json member2.json
+`, flags.SignModeLegacyAminoJSON),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			session, unsignedTx, err := loadMultisigSession(clientCtx.TxConfig, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, file := range args[1:] {
+				bz, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				sigs, err := clientCtx.TxConfig.UnmarshalSignatureJSON(bz)
+				if err != nil {
+					return fmt.Errorf("failed to parse the signatures of %s: %w", file, err)
+				}
+				for _, sig := range sigs {
+					if err := session.addSignatureV2(clientCtx.Codec, clientCtx.TxConfig, unsignedTx, sig); err != nil {
+						return fmt.Errorf("%s: %w", file, err)
+					}
+				}
+			}
+			return session.save(args[0])
+		},
+	}
+}
+
+func multisigStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [session-file]",
+		Short: "Print the signers of a session and who has signed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			session, _, err := loadMultisigSession(clientCtx.TxConfig, args[0])
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Chain ID: %s\n", session.ChainID)
+			ready := true
+			for _, signer := range session.Signers {
+				status, err := signer.status(clientCtx.Codec)
+				if err != nil {
+					return err
+				}
+				ready = ready && status.complete()
+
+				cmd.Printf("Signer %s (%s), account number %d, sequence %d: %d/%d signed\n",
+					signer.Address, status.kind, signer.AccountNumber, signer.Sequence, status.signed, status.threshold)
+				for _, member := range status.members {
+					state := "pending"
+					if member.signed {
+						state = "signed"
+					}
+					cmd.Printf("  %s\t%s\n", member.address, state)
+				}
+			}
+			if ready {
+				cmd.Println("Ready to broadcast")
+			} else {
+				cmd.Println("Waiting for signatures")
+			}
+			return nil
+		},
+	}
+}
+
+func multisigBroadcastCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "broadcast [session-file]",
+		Short: "Broadcast the transaction of a session once every signer has its threshold of signatures",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			if offline, _ := cmd.Flags().GetBool(flags.FlagOffline); offline {
+				return errors.New("cannot broadcast tx during offline mode")
+			}
+
+			session, unsignedTx, err := loadMultisigSession(clientCtx.TxConfig, args[0])
+			if err != nil {
+				return err
+			}
+			signedTx, err := session.signedTx(clientCtx.Codec, clientCtx.TxConfig, unsignedTx)
+			if err != nil {
+				return err
+			}
+
+			txBytes, err := clientCtx.TxConfig.TxEncoder()(signedTx)
+			if err != nil {
+				return err
+			}
+			res, err := clientCtx.BroadcastTx(txBytes)
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// validateFoundationMembers checks that the signers of the foundation messages
+// which only members may sign are members of the foundation.
+func validateFoundationMembers(cmd *cobra.Command, clientCtx client.Context, msgs []sdk.Msg) error {
+	queryClient := foundation.NewQueryClient(clientCtx)
+	for _, msg := range msgs {
+		switch msg.(type) {
+		case *foundation.MsgSubmitProposal, *foundation.MsgVote, *foundation.MsgExec, *foundation.MsgLeaveFoundation:
+		default:
+			continue
+		}
+		for _, signer := range msg.GetSigners() {
+			if _, err := queryClient.Member(cmd.Context(), &foundation.QueryMemberRequest{Address: signer.String()}); err != nil {
+				return fmt.Errorf("%s of %s is not a member of the foundation: %w", signer, sdk.MsgTypeURL(msg), err)
+			}
+		}
+	}
+	return nil
+}
+
+// multisigSession is the session file of the signatures of a transaction.
+type multisigSession struct {
+	ChainID string            `json:"chain_id"`
+	Tx      json.RawMessage   `json:"tx"`
+	Signers []*multisigSigner `json:"signers"`
+}
+
+// multisigSigner is a signer of the transaction of a session. Its public key is
+// empty if it is unknown, i.e. the signer is a single key which has never
+// signed on chain.
+type multisigSigner struct {
+	Address       string                     `json:"address"`
+	AccountNumber uint64                     `json:"account_number,string"`
+	Sequence      uint64                     `json:"sequence,string"`
+	PubKey        json.RawMessage            `json:"pub_key,omitempty"`
+	Signatures    []multisigPartialSignature `json:"signatures"`
+}
+
+// multisigPartialSignature is the signature of a signer, or of a member of a
+// multisig signer.
+type multisigPartialSignature struct {
+	PubKey    json.RawMessage `json:"pub_key"`
+	Signature []byte          `json:"signature"`
+}
+
+func newMultisigSession(clientCtx client.Context, unsignedTx authsigning.Tx) (*multisigSession, error) {
+	if sigs, err := unsignedTx.GetSignaturesV2(); err != nil {
+		return nil, err
+	} else if len(sigs) != 0 {
+		return nil, fmt.Errorf("the transaction is already signed")
+	}
+
+	bz, err := clientCtx.TxConfig.TxJSONEncoder()(unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	session := &multisigSession{
+		ChainID: clientCtx.ChainID,
+		Tx:      bz,
+	}
+
+	for _, addr := range unsignedTx.GetSigners() {
+		accountNumber, sequence, err := clientCtx.AccountRetriever.GetAccountNumberSequence(clientCtx, addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the account of %s: %w", addr, err)
+		}
+		signer := &multisigSigner{
+			Address:       addr.String(),
+			AccountNumber: accountNumber,
+			Sequence:      sequence,
+			Signatures:    []multisigPartialSignature{},
+		}
+
+		var pubKey cryptotypes.PubKey
+		if info, err := clientCtx.Keyring.KeyByAddress(addr); err == nil {
+			pubKey = info.GetPubKey()
+		} else if acc, err := clientCtx.AccountRetriever.GetAccount(clientCtx, addr); err == nil {
+			pubKey = acc.GetPubKey()
+		}
+		if pubKey != nil {
+			if signer.PubKey, err = clientCtx.Codec.MarshalInterfaceJSON(pubKey); err != nil {
+				return nil, err
+			}
+		}
+		session.Signers = append(session.Signers, signer)
+	}
+	return session, nil
+}
+
+func loadMultisigSession(txConfig client.TxConfig, path string) (*multisigSession, authsigning.Tx, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var session multisigSession
+	if err := json.Unmarshal(bz, &session); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the session %s: %w", path, err)
+	}
+
+	decoded, err := txConfig.TxJSONDecoder()(session.Tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	unsignedTx, ok := decoded.(authsigning.Tx)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected transaction %T", decoded)
+	}
+
+	signers := unsignedTx.GetSigners()
+	if len(signers) != len(session.Signers) {
+		return nil, nil, fmt.Errorf("the session %s has %d signers, but its transaction has %d", path, len(session.Signers), len(signers))
+	}
+	for i, signer := range signers {
+		if signer.String() != session.Signers[i].Address {
+			return nil, nil, fmt.Errorf("the signer %d of the session %s is %s, but the one of its transaction is %s", i, path, session.Signers[i].Address, signer)
+		}
+	}
+	return &session, unsignedTx, nil
+}
+
+func (s *multisigSession) save(path string) error {
+	bz, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bz, '\n'), 0o600)
+}
+
+// signBytes returns the bytes for the i-th signer or its members to sign.
+func (s *multisigSession) signBytes(txConfig client.TxConfig, unsignedTx authsigning.Tx, i int) ([]byte, error) {
+	signer := s.Signers[i]
+	return offlineSignBytes(txConfig, unsignedTx, s.ChainID, signer.AccountNumber, signer.Sequence, multisigSignMode)
+}
+
+// signersOf returns the indexes of the signers the key may sign for.
+func (s *multisigSession) signersOf(cdc codec.Codec, pubKey cryptotypes.PubKey) ([]int, error) {
+	var indexes []int
+	for i, signer := range s.Signers {
+		ok, err := signer.accepts(cdc, pubKey)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// addSignature adds the signature of the key to the i-th signer, which must be
+// of the key of the signer or of a member of the multisig signer.
+func (s *multisigSession) addSignature(cdc codec.Codec, i int, signBytes []byte, pubKey cryptotypes.PubKey, sig []byte) error {
+	signer := s.Signers[i]
+	addr := sdk.AccAddress(pubKey.Address())
+	ok, err := signer.accepts(cdc, pubKey)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is neither the signer %s nor its member", addr, signer.Address)
+	}
+	signed, err := signer.signedBy(cdc, pubKey)
+	if err != nil {
+		return err
+	}
+	if signed {
+		return fmt.Errorf("%s has already signed for %s", addr, signer.Address)
+	}
+	if !pubKey.VerifySignature(signBytes, sig) {
+		return fmt.Errorf("invalid signature of %s for %s", addr, signer.Address)
+	}
+
+	bz, err := cdc.MarshalInterfaceJSON(pubKey)
+	if err != nil {
+		return err
+	}
+	signer.Signatures = append(signer.Signatures, multisigPartialSignature{PubKey: bz, Signature: sig})
+	return nil
+}
+
+// addSignatureV2 adds a signature of a signature file to the signer it is of.
+func (s *multisigSession) addSignatureV2(cdc codec.Codec, txConfig client.TxConfig, unsignedTx authsigning.Tx, sig signing.SignatureV2) error {
+	addr := sdk.AccAddress(sig.PubKey.Address())
+	data, ok := sig.Data.(*signing.SingleSignatureData)
+	if !ok {
+		return fmt.Errorf("expected a single signature of %s, got %T", addr, sig.Data)
+	}
+	if data.SignMode != multisigSignMode {
+		return fmt.Errorf("the signature of %s is of %s, but the ones of a session must be of %s", addr, data.SignMode, multisigSignMode)
+	}
+
+	indexes, err := s.signersOf(cdc, sig.PubKey)
+	if err != nil {
+		return err
+	}
+	for _, i := range indexes {
+		if s.Signers[i].Sequence != sig.Sequence {
+			continue
+		}
+		signBytes, err := s.signBytes(txConfig, unsignedTx, i)
+		if err != nil {
+			return err
+		}
+		if sig.PubKey.VerifySignature(signBytes, data.Signature) {
+			return s.addSignature(cdc, i, signBytes, sig.PubKey, data.Signature)
+		}
+	}
+	return fmt.Errorf("the signature of %s is not of a signer of the session", addr)
+}
+
+// signedTx returns the transaction signed by the signatures of the session,
+// once every signer has its threshold of signatures.
+func (s *multisigSession) signedTx(cdc codec.Codec, txConfig client.TxConfig, unsignedTx authsigning.Tx) (authsigning.Tx, error) {
+	sigs := make([]signing.SignatureV2, len(s.Signers))
+	for i, signer := range s.Signers {
+		status, err := signer.status(cdc)
+		if err != nil {
+			return nil, err
+		}
+		if !status.complete() {
+			return nil, fmt.Errorf("%s has %d of the %d signatures required", signer.Address, status.signed, status.threshold)
+		}
+
+		signerPubKey, err := signer.pubKey(cdc)
+		if err != nil {
+			return nil, err
+		}
+		multisigPubKey, isMultisig := signerPubKey.(multisig.PubKey)
+		if !isMultisig {
+			pubKey, err := signer.Signatures[0].pubKey(cdc)
+			if err != nil {
+				return nil, err
+			}
+			sigs[i] = signing.SignatureV2{
+				PubKey:   pubKey,
+				Data:     &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: signer.Signatures[0].Signature},
+				Sequence: signer.Sequence,
+			}
+			continue
+		}
+
+		members := multisigPubKey.GetPubKeys()
+		multiSig := multisig.NewMultisig(len(members))
+		for _, partial := range signer.Signatures {
+			pubKey, err := partial.pubKey(cdc)
+			if err != nil {
+				return nil, err
+			}
+			data := &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: partial.Signature}
+			if err := multisig.AddSignatureFromPubKey(multiSig, data, pubKey, members); err != nil {
+				return nil, err
+			}
+		}
+		sigs[i] = signing.SignatureV2{
+			PubKey:   multisigPubKey,
+			Data:     multiSig,
+			Sequence: signer.Sequence,
+		}
+	}
+
+	builder, err := txConfig.WrapTxBuilder(unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	if err := builder.SetSignatures(sigs...); err != nil {
+		return nil, err
+	}
+	return builder.GetTx(), nil
+}
+
+func (s *multisigSigner) pubKey(cdc codec.Codec) (cryptotypes.PubKey, error) {
+	if len(s.PubKey) == 0 {
+		return nil, nil
+	}
+	var pubKey cryptotypes.PubKey
+	if err := cdc.UnmarshalInterfaceJSON(s.PubKey, &pubKey); err != nil {
+		return nil, fmt.Errorf("invalid public key of %s: %w", s.Address, err)
+	}
+	return pubKey, nil
+}
+
+// accepts returns whether the key may sign for the signer, i.e. it is the key
+// of the signer or of a member of the multisig signer.
+func (s *multisigSigner) accepts(cdc codec.Codec, pubKey cryptotypes.PubKey) (bool, error) {
+	signerPubKey, err := s.pubKey(cdc)
+	if err != nil {
+		return false, err
+	}
+	switch signerPubKey := signerPubKey.(type) {
+	case nil:
+		return sdk.AccAddress(pubKey.Address()).String() == s.Address, nil
+	case multisig.PubKey:
+		for _, member := range signerPubKey.GetPubKeys() {
+			if member.Equals(pubKey) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return signerPubKey.Equals(pubKey), nil
+	}
+}
+
+func (s *multisigSigner) signedBy(cdc codec.Codec, pubKey cryptotypes.PubKey) (bool, error) {
+	for _, partial := range s.Signatures {
+		signed, err := partial.pubKey(cdc)
+		if err != nil {
+			return false, err
+		}
+		if signed.Equals(pubKey) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// multisigSignerStatus is who has signed for a signer.
+type multisigSignerStatus struct {
+	kind      string
+	threshold int
+	signed    int
+	members   []multisigMemberStatus
+}
+
+type multisigMemberStatus struct {
+	address sdk.AccAddress
+	signed  bool
+}
+
+func (s multisigSignerStatus) complete() bool {
+	return s.signed >= s.threshold
+}
+
+func (s *multisigSigner) status(cdc codec.Codec) (multisigSignerStatus, error) {
+	signerPubKey, err := s.pubKey(cdc)
+	if err != nil {
+		return multisigSignerStatus{}, err
+	}
+
+	status := multisigSignerStatus{kind: "single key", threshold: 1, signed: len(s.Signatures)}
+	multisigPubKey, ok := signerPubKey.(multisig.PubKey)
+	if !ok {
+		return status, nil
+	}
+
+	members := multisigPubKey.GetPubKeys()
+	status.kind = fmt.Sprintf("multisig %d of %d", multisigPubKey.GetThreshold(), len(members))
+	status.threshold = int(multisigPubKey.GetThreshold())
+	for _, member := range members {
+		signed, err := s.signedBy(cdc, member)
+		if err != nil {
+			return multisigSignerStatus{}, err
+		}
+		status.members = append(status.members, multisigMemberStatus{address: sdk.AccAddress(member.Address()), signed: signed})
+	}
+	return status, nil
+}
+
+func (s multisigPartialSignature) pubKey(cdc codec.Codec) (cryptotypes.PubKey, error) {
+	var pubKey cryptotypes.PubKey
+	if err := cdc.UnmarshalInterfaceJSON(s.PubKey, &pubKey); err != nil {
+		return nil, fmt.Errorf("invalid public key of a signature: %w", err)
+	}
+	return pubKey, nil
+}
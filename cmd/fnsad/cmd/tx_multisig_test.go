@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
+	kmultisig "github.com/Finschia/finschia-sdk/crypto/keys/multisig"
+	cryptotypes "github.com/Finschia/finschia-sdk/crypto/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/tx/signing"
+	authsigning "github.com/Finschia/finschia-sdk/x/auth/signing"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+
+	"github.com/Finschia/finschia/app"
+)
+
+func TestMultisigSession(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	cdc, txConfig := encodingConfig.Marshaler, encodingConfig.TxConfig
+
+	kb := keyring.NewInMemory()
+	newKey := func(name string) keyring.Info {
+		info, _, err := kb.NewMnemonic(name, keyring.English, "m/44'/438'/0'/0/0", "", hd.Secp256k1)
+		require.NoError(t, err)
+		return info
+	}
+	members := []keyring.Info{newKey("member1"), newKey("member2"), newKey("member3")}
+	single := newKey("single")
+	newKey("outsider")
+
+	memberPubKeys := make([]cryptotypes.PubKey, len(members))
+	for i, member := range members {
+		memberPubKeys[i] = member.GetPubKey()
+	}
+	multisigPubKey := kmultisig.NewLegacyAminoPubKey(2, memberPubKeys)
+	multisigAddr := sdk.AccAddress(multisigPubKey.Address())
+
+	// a foundation proposal of a multisig member and a single key member
+	msg := &foundation.MsgSubmitProposal{
+		Proposers: []string{multisigAddr.String(), single.GetAddress().String()},
+		Metadata:  "proposal",
+		Exec:      foundation.Exec_EXEC_UNSPECIFIED,
+	}
+	require.NoError(t, msg.SetMsgs([]sdk.Msg{&foundation.MsgFundTreasury{
+		From:   multisigAddr.String(),
+		Amount: sdk.NewCoins(sdk.NewInt64Coin("cony", 1)),
+	}}))
+	builder := txConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(msg))
+	builder.SetGasLimit(200000)
+	txJSON, err := txConfig.TxJSONEncoder()(builder.GetTx())
+	require.NoError(t, err)
+
+	multisigPubKeyJSON, err := cdc.MarshalInterfaceJSON(multisigPubKey)
+	require.NoError(t, err)
+	session := &multisigSession{
+		ChainID: "finschia-2",
+		Tx:      txJSON,
+		Signers: []*multisigSigner{
+			{Address: multisigAddr.String(), AccountNumber: 7, Sequence: 1, PubKey: multisigPubKeyJSON, Signatures: []multisigPartialSignature{}},
+			// the public key of the single key is unknown until it signs
+			{Address: single.GetAddress().String(), AccountNumber: 8, Sequence: 2, Signatures: []multisigPartialSignature{}},
+		},
+	}
+	sessionFile := filepath.Join(t.TempDir(), "session.json")
+	require.NoError(t, session.save(sessionFile))
+
+	execCmd := func(cmd *cobra.Command, args ...string) (string, error) {
+		clientCtx := client.Context{}.WithKeyring(kb).WithCodec(cdc).WithTxConfig(txConfig)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &clientCtx))
+		return out.String(), err
+	}
+
+	_, err = execCmd(multisigSignCmd(), sessionFile, "--from=outsider")
+	require.ErrorContains(t, err, "is neither a signer nor a member")
+
+	_, err = execCmd(multisigSignCmd(), sessionFile, "--from=member1")
+	require.NoError(t, err)
+	_, err = execCmd(multisigSignCmd(), sessionFile, "--from=member1")
+	require.ErrorContains(t, err, "has already signed")
+	_, err = execCmd(multisigSignCmd(), sessionFile, "--from=single")
+	require.NoError(t, err)
+
+	out, err := execCmd(multisigStatusCmd(), sessionFile)
+	require.NoError(t, err)
+	require.Contains(t, out, "Signer "+multisigAddr.String()+" (multisig 2 of 3), account number 7, sequence 1: 1/2 signed")
+	require.Contains(t, out, members[0].GetAddress().String()+"\tsigned")
+	require.Contains(t, out, members[1].GetAddress().String()+"\tpending")
+	require.Contains(t, out, "Signer "+single.GetAddress().String()+" (single key), account number 8, sequence 2: 1/1 signed")
+	require.Contains(t, out, "Waiting for signatures")
+
+	session, unsignedTx, err := loadMultisigSession(txConfig, sessionFile)
+	require.NoError(t, err)
+	_, err = session.signedTx(cdc, txConfig, unsignedTx)
+	require.ErrorContains(t, err, "has 1 of the 2 signatures required")
+
+	// a signature of the outsider or of other sign bytes is rejected
+	signBytes, err := session.signBytes(txConfig, unsignedTx, 0)
+	require.NoError(t, err)
+	sig, pubKey, err := kb.Sign("outsider", signBytes)
+	require.NoError(t, err)
+	err = session.addSignatureV2(cdc, txConfig, unsignedTx, signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: sig},
+		Sequence: 1,
+	})
+	require.ErrorContains(t, err, "is not of a signer of the session")
+	sig, pubKey, err = kb.Sign("member3", []byte("other sign bytes"))
+	require.NoError(t, err)
+	err = session.addSignatureV2(cdc, txConfig, unsignedTx, signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: sig},
+		Sequence: 1,
+	})
+	require.ErrorContains(t, err, "is not of a signer of the session")
+	err = session.addSignatureV2(cdc, txConfig, unsignedTx, signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: signing.SignMode_SIGN_MODE_DIRECT, Signature: sig},
+		Sequence: 1,
+	})
+	require.ErrorContains(t, err, "must be of SIGN_MODE_LEGACY_AMINO_JSON")
+
+	// the signature file of member3 meets the threshold
+	sig, pubKey, err = kb.Sign("member3", signBytes)
+	require.NoError(t, err)
+	sigJSON, err := txConfig.MarshalSignatureJSON([]signing.SignatureV2{{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: multisigSignMode, Signature: sig},
+		Sequence: 1,
+	}})
+	require.NoError(t, err)
+	sigFile := filepath.Join(t.TempDir(), "member3.json")
+	require.NoError(t, os.WriteFile(sigFile, sigJSON, 0o600))
+	_, err = execCmd(multisigAddCmd(), sessionFile, sigFile)
+	require.NoError(t, err)
+
+	out, err = execCmd(multisigStatusCmd(), sessionFile)
+	require.NoError(t, err)
+	require.Contains(t, out, "Ready to broadcast")
+
+	session, unsignedTx, err = loadMultisigSession(txConfig, sessionFile)
+	require.NoError(t, err)
+	signedTx, err := session.signedTx(cdc, txConfig, unsignedTx)
+	require.NoError(t, err)
+
+	sigs, err := signedTx.GetSignaturesV2()
+	require.NoError(t, err)
+	require.Len(t, sigs, 2)
+	for i, signer := range session.Signers {
+		signerData := authsigning.SignerData{ChainID: "finschia-2", AccountNumber: signer.AccountNumber, Sequence: signer.Sequence}
+		require.NoError(t, authsigning.VerifySignature(sigs[i].PubKey, signerData, sigs[i].Data, txConfig.SignModeHandler(), signedTx))
+	}
+	require.True(t, sigs[0].PubKey.Equals(multisigPubKey))
+	require.True(t, sigs[1].PubKey.Equals(single.GetPubKey()))
+}
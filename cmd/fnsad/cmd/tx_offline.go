@@ -0,0 +1,503 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/codec"
+	cryptotypes "github.com/Finschia/finschia-sdk/crypto/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/tx"
+	"github.com/Finschia/finschia-sdk/types/tx/signing"
+	authsigning "github.com/Finschia/finschia-sdk/x/auth/signing"
+
+	"github.com/Finschia/finschia/app"
+	"github.com/Finschia/finschia/app/params"
+)
+
+const (
+	flagPubKey        = "pubkey"
+	flagAccountNumber = "account-number"
+	flagSequence      = "sequence"
+)
+
+// OfflineCmd returns the commands to build, verify and sign transactions on a
+// machine without access to a node, e.g. an air-gapped one.
+func OfflineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        "offline",
+		Short:                      "Build, verify and sign transactions without access to a node",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		offlineBuildCmd(),
+		offlineVerifyCmd(),
+		offlineSignCmd(),
+	)
+
+	return cmd
+}
+
+func offlineBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build [template-file]",
+		Short: "Build the sign request of an unsigned transaction from a JSON template",
+		Long: `Build an unsigned transaction of any registered messages from a JSON template,
+and write the sign request of the transaction, which holds the transaction and
+its sign bytes for the given sign mode, account number and sequence of the
+signer. The node is never accessed, so that the account number and the sequence
+must be given.
+
+The signer is a key of the keyring given by --from, or a public key given by
+--pubkey in the JSON form of 'fnsad keys show --pubkey'. It must be the only
+signer of the messages.
+
+Example template:
+{
+  "messages": [
+    {
+      "@type": "/cosmos.bank.v1beta1.MsgSend",
+      "from_address": "link1...",
+      "to_address": "link1...",
+      "amount": [{"denom": "cony", "amount": "1000"}]
+    }
+  ],
+  "memo": "",
+  "timeout_height": 0,
+  "fee": {"amount": [{"denom": "cony", "amount": "2000"}], "gas_limit": 200000}
+}
+
+Example:
+$ fnsad tx offline build template.json --from custody --account-number 12 --sequence 3 --chain-id finschia-2 > request.json
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			if clientCtx.ChainID == "" {
+				return fmt.Errorf("--%s is required", flags.FlagChainID)
+			}
+
+			from, _ := cmd.Flags().GetString(flags.FlagFrom)                     // nolint: errcheck
+			pubKeyJSON, _ := cmd.Flags().GetString(flagPubKey)                   // nolint: errcheck
+			accountNumber, _ := cmd.Flags().GetUint64(flagAccountNumber)         // nolint: errcheck
+			sequence, _ := cmd.Flags().GetUint64(flagSequence)                   // nolint: errcheck
+			signModeStr, _ := cmd.Flags().GetString(flags.FlagSignMode)          // nolint: errcheck
+			outputDocument, _ := cmd.Flags().GetString(flags.FlagOutputDocument) // nolint: errcheck
+
+			encodingConfig := app.MakeEncodingConfig()
+			signMode, err := parseSignMode(signModeStr)
+			if err != nil {
+				return err
+			}
+
+			var pubKey cryptotypes.PubKey
+			switch {
+			case from != "" && pubKeyJSON != "":
+				return fmt.Errorf("--%s and --%s are exclusive", flags.FlagFrom, flagPubKey)
+			case from != "":
+				info, err := clientCtx.Keyring.Key(from)
+				if err != nil {
+					return err
+				}
+				pubKey = info.GetPubKey()
+			case pubKeyJSON != "":
+				if err := encodingConfig.Marshaler.UnmarshalInterfaceJSON([]byte(pubKeyJSON), &pubKey); err != nil {
+					return fmt.Errorf("invalid --%s: %w", flagPubKey, err)
+				}
+			default:
+				return fmt.Errorf("either --%s or --%s is required", flags.FlagFrom, flagPubKey)
+			}
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var template offlineTxTemplate
+			if err := json.Unmarshal(bz, &template); err != nil {
+				return fmt.Errorf("failed to parse the template %s: %w", args[0], err)
+			}
+
+			unsignedTx, err := template.build(encodingConfig, pubKey, sequence, signMode)
+			if err != nil {
+				return err
+			}
+			req, err := newOfflineSignRequest(encodingConfig.TxConfig, unsignedTx, clientCtx.ChainID, accountNumber, sequence, signMode)
+			if err != nil {
+				return err
+			}
+			return writeJSONDocument(cmd, outputDocument, req)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagFrom, "", "Name of the key of the signer in the keyring")
+	cmd.Flags().String(flagPubKey, "", "Public key of the signer in JSON, instead of --from")
+	cmd.Flags().Uint64(flagAccountNumber, 0, "Account number of the signer")
+	cmd.Flags().Uint64(flagSequence, 0, "Sequence of the signer")
+	cmd.Flags().String(flags.FlagSignMode, flags.SignModeDirect, fmt.Sprintf("Sign mode (%s|%s)", flags.SignModeDirect, flags.SignModeLegacyAminoJSON))
+	cmd.Flags().String(flags.FlagOutputDocument, "", "The document is written to the given file instead of STDOUT")
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|kwallet|pass|test|memory)")
+	cmd.Flags().String(flags.FlagKeyringDir, "", "The client Keyring directory; if omitted, the default 'home' directory will be used")
+	_ = cmd.MarkFlagRequired(flagAccountNumber)
+	_ = cmd.MarkFlagRequired(flagSequence)
+
+	return cmd
+}
+
+func offlineVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify [request-file]",
+		Short: "Verify a sign request and print what is signed",
+		Long: `Verify that the sign bytes of a sign request are the ones of its transaction,
+and print them decoded: the sign doc of SIGN_MODE_DIRECT with its body and auth
+info, or the JSON of LEGACY_AMINO_JSON, which is exactly what a signer signs.
+
+Example:
+$ fnsad tx offline verify request.json
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encodingConfig := app.MakeEncodingConfig()
+			req, unsignedTx, err := readOfflineSignRequest(encodingConfig.TxConfig, args[0])
+			if err != nil {
+				return err
+			}
+
+			signers := unsignedTx.GetSigners()
+			signMode, _ := parseSignMode(req.SignMode) // nolint: errcheck
+			doc, err := decodeSignBytes(encodingConfig.Marshaler, signMode, req.SignBytes)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Sign mode: %s\n", req.SignMode)
+			cmd.Printf("Signer: %s\n", signers[0])
+			cmd.Printf("Sign bytes (sha256): %X\n", sha256.Sum256(req.SignBytes))
+			cmd.Println(doc)
+			return nil
+		},
+	}
+}
+
+func offlineSignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign [request-file]",
+		Short: "Sign the sign bytes of a sign request with a key of the keyring",
+		Long: `Verify a sign request, sign its sign bytes with a key of the keyring, and write
+the signed transaction, to be broadcast by 'fnsad tx broadcast' from a machine
+with access to a node.
+
+Example:
+$ fnsad tx offline sign request.json --from custody > signed.json
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			from, _ := cmd.Flags().GetString(flags.FlagFrom)                     // nolint: errcheck
+			outputDocument, _ := cmd.Flags().GetString(flags.FlagOutputDocument) // nolint: errcheck
+			if from == "" {
+				return fmt.Errorf("--%s is required", flags.FlagFrom)
+			}
+
+			encodingConfig := app.MakeEncodingConfig()
+			req, unsignedTx, err := readOfflineSignRequest(encodingConfig.TxConfig, args[0])
+			if err != nil {
+				return err
+			}
+
+			sig, pubKey, err := clientCtx.Keyring.Sign(from, req.SignBytes)
+			if err != nil {
+				return err
+			}
+			signedTx, err := setOfflineSignature(encodingConfig.TxConfig, unsignedTx, pubKey, sig)
+			if err != nil {
+				return err
+			}
+
+			bz, err := encodingConfig.TxConfig.TxJSONEncoder()(signedTx)
+			if err != nil {
+				return err
+			}
+			return writeDocument(cmd, outputDocument, bz)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagFrom, "", "Name of the key of the signer in the keyring")
+	cmd.Flags().String(flags.FlagOutputDocument, "", "The document is written to the given file instead of STDOUT")
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "Select keyring's backend (os|file|kwallet|pass|test|memory)")
+	cmd.Flags().String(flags.FlagKeyringDir, "", "The client Keyring directory; if omitted, the default 'home' directory will be used")
+
+	return cmd
+}
+
+// offlineTxTemplate is the JSON template of an unsigned transaction. The
+// messages are in the JSON form of their Any, i.e. with their @type.
+type offlineTxTemplate struct {
+	Messages      []json.RawMessage `json:"messages"`
+	Memo          string            `json:"memo"`
+	TimeoutHeight uint64            `json:"timeout_height"`
+	Fee           struct {
+		Amount   sdk.Coins `json:"amount"`
+		GasLimit uint64    `json:"gas_limit"`
+		Granter  string    `json:"granter"`
+	} `json:"fee"`
+}
+
+// build returns the unsigned transaction of the template, with the signer info
+// of the given public key, sequence and sign mode and an empty signature.
+func (t offlineTxTemplate) build(encodingConfig params.EncodingConfig, pubKey cryptotypes.PubKey, sequence uint64, signMode signing.SignMode) (authsigning.Tx, error) {
+	if len(t.Messages) == 0 {
+		return nil, fmt.Errorf("no messages in the template")
+	}
+	msgs := make([]sdk.Msg, len(t.Messages))
+	for i, bz := range t.Messages {
+		if err := encodingConfig.Marshaler.UnmarshalInterfaceJSON(bz, &msgs[i]); err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", i, err)
+		}
+		if err := msgs[i].ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", i, err)
+		}
+	}
+
+	builder := encodingConfig.TxConfig.NewTxBuilder()
+	if err := builder.SetMsgs(msgs...); err != nil {
+		return nil, err
+	}
+	builder.SetMemo(t.Memo)
+	builder.SetTimeoutHeight(t.TimeoutHeight)
+	builder.SetFeeAmount(t.Fee.Amount)
+	builder.SetGasLimit(t.Fee.GasLimit)
+	if t.Fee.Granter != "" {
+		granter, err := sdk.AccAddressFromBech32(t.Fee.Granter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee granter: %w", err)
+		}
+		builder.SetFeeGranter(granter)
+	}
+
+	signers := builder.GetTx().GetSigners()
+	if len(signers) != 1 {
+		return nil, fmt.Errorf("expected a single signer, got %d: %v", len(signers), signers)
+	}
+	if signer := sdk.AccAddress(pubKey.Address()); !signer.Equals(signers[0]) {
+		return nil, fmt.Errorf("the public key of %s is not of the signer %s", signer, signers[0])
+	}
+
+	sig := signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: signMode},
+		Sequence: sequence,
+	}
+	if err := builder.SetSignatures(sig); err != nil {
+		return nil, err
+	}
+	return builder.GetTx(), nil
+}
+
+// offlineSignRequest is an unsigned transaction with its sign bytes.
+type offlineSignRequest struct {
+	ChainID       string          `json:"chain_id"`
+	AccountNumber uint64          `json:"account_number,string"`
+	Sequence      uint64          `json:"sequence,string"`
+	SignMode      string          `json:"sign_mode"`
+	Tx            json.RawMessage `json:"tx"`
+	SignBytes     []byte          `json:"sign_bytes"`
+}
+
+func newOfflineSignRequest(
+	txConfig client.TxConfig, unsignedTx authsigning.Tx, chainID string, accountNumber, sequence uint64, signMode signing.SignMode,
+) (offlineSignRequest, error) {
+	signBytes, err := offlineSignBytes(txConfig, unsignedTx, chainID, accountNumber, sequence, signMode)
+	if err != nil {
+		return offlineSignRequest{}, err
+	}
+	bz, err := txConfig.TxJSONEncoder()(unsignedTx)
+	if err != nil {
+		return offlineSignRequest{}, err
+	}
+	return offlineSignRequest{
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		SignMode:      signMode.String(),
+		Tx:            bz,
+		SignBytes:     signBytes,
+	}, nil
+}
+
+func offlineSignBytes(
+	txConfig client.TxConfig, unsignedTx authsigning.Tx, chainID string, accountNumber, sequence uint64, signMode signing.SignMode,
+) ([]byte, error) {
+	signerData := authsigning.SignerData{
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+	}
+	return txConfig.SignModeHandler().GetSignBytes(signMode, signerData, unsignedTx)
+}
+
+// readOfflineSignRequest reads the sign request of the given file, and checks
+// that its sign bytes are the ones of its transaction.
+func readOfflineSignRequest(txConfig client.TxConfig, path string) (offlineSignRequest, authsigning.Tx, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return offlineSignRequest{}, nil, err
+	}
+	var req offlineSignRequest
+	if err := json.Unmarshal(bz, &req); err != nil {
+		return offlineSignRequest{}, nil, fmt.Errorf("failed to parse the sign request %s: %w", path, err)
+	}
+
+	signMode, err := parseSignMode(req.SignMode)
+	if err != nil {
+		return offlineSignRequest{}, nil, err
+	}
+	decoded, err := txConfig.TxJSONDecoder()(req.Tx)
+	if err != nil {
+		return offlineSignRequest{}, nil, err
+	}
+	unsignedTx, ok := decoded.(authsigning.Tx)
+	if !ok {
+		return offlineSignRequest{}, nil, fmt.Errorf("unexpected transaction %T", decoded)
+	}
+	if n := len(unsignedTx.GetSigners()); n != 1 {
+		return offlineSignRequest{}, nil, fmt.Errorf("expected a single signer, got %d", n)
+	}
+
+	signBytes, err := offlineSignBytes(txConfig, unsignedTx, req.ChainID, req.AccountNumber, req.Sequence, signMode)
+	if err != nil {
+		return offlineSignRequest{}, nil, err
+	}
+	if !bytes.Equal(signBytes, req.SignBytes) {
+		return offlineSignRequest{}, nil, fmt.Errorf("the sign bytes of %s are not the ones of its transaction", path)
+	}
+	return req, unsignedTx, nil
+}
+
+// setOfflineSignature returns the transaction signed by the given signature,
+// which must be of the public key in its signer info.
+func setOfflineSignature(txConfig client.TxConfig, unsignedTx authsigning.Tx, pubKey cryptotypes.PubKey, signature []byte) (authsigning.Tx, error) {
+	sigs, err := unsignedTx.GetSignaturesV2()
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) != 1 {
+		return nil, fmt.Errorf("expected a single signer info, got %d", len(sigs))
+	}
+	if !pubKey.Equals(sigs[0].PubKey) {
+		return nil, fmt.Errorf("the key of %s is not of the signer %s", sdk.AccAddress(pubKey.Address()), sdk.AccAddress(sigs[0].PubKey.Address()))
+	}
+	data, ok := sigs[0].Data.(*signing.SingleSignatureData)
+	if !ok {
+		return nil, fmt.Errorf("unexpected signature data %T", sigs[0].Data)
+	}
+
+	builder, err := txConfig.WrapTxBuilder(unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	sig := signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: data.SignMode, Signature: signature},
+		Sequence: sigs[0].Sequence,
+	}
+	if err := builder.SetSignatures(sig); err != nil {
+		return nil, err
+	}
+	return builder.GetTx(), nil
+}
+
+// decodeSignBytes returns the indented JSON of the sign bytes, decoding the
+// body and the auth info of a SIGN_MODE_DIRECT sign doc.
+func decodeSignBytes(cdc codec.Codec, signMode signing.SignMode, signBytes []byte) (string, error) {
+	var out bytes.Buffer
+	switch signMode {
+	case signing.SignMode_SIGN_MODE_DIRECT:
+		var doc tx.SignDoc
+		if err := cdc.Unmarshal(signBytes, &doc); err != nil {
+			return "", err
+		}
+		var body tx.TxBody
+		if err := cdc.Unmarshal(doc.BodyBytes, &body); err != nil {
+			return "", err
+		}
+		var authInfo tx.AuthInfo
+		if err := cdc.Unmarshal(doc.AuthInfoBytes, &authInfo); err != nil {
+			return "", err
+		}
+
+		bodyJSON, err := cdc.MarshalJSON(&body)
+		if err != nil {
+			return "", err
+		}
+		authInfoJSON, err := cdc.MarshalJSON(&authInfo)
+		if err != nil {
+			return "", err
+		}
+		bz, err := json.Marshal(struct {
+			ChainID       string          `json:"chain_id"`
+			AccountNumber uint64          `json:"account_number,string"`
+			Body          json.RawMessage `json:"body"`
+			AuthInfo      json.RawMessage `json:"auth_info"`
+		}{doc.ChainId, doc.AccountNumber, bodyJSON, authInfoJSON})
+		if err != nil {
+			return "", err
+		}
+		if err := json.Indent(&out, bz, "", "  "); err != nil {
+			return "", err
+		}
+	case signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON:
+		if err := json.Indent(&out, signBytes, "", "  "); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported sign mode %s", signMode)
+	}
+	return out.String(), nil
+}
+
+// parseSignMode parses the value of --sign-mode or the name of a sign mode.
+func parseSignMode(s string) (signing.SignMode, error) {
+	switch s {
+	case flags.SignModeDirect, signing.SignMode_SIGN_MODE_DIRECT.String():
+		return signing.SignMode_SIGN_MODE_DIRECT, nil
+	case flags.SignModeLegacyAminoJSON, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON.String():
+		return signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, nil
+	default:
+		return signing.SignMode_SIGN_MODE_UNSPECIFIED, fmt.Errorf("unsupported sign mode %q, expected %s or %s", s, flags.SignModeDirect, flags.SignModeLegacyAminoJSON)
+	}
+}
+
+func writeJSONDocument(cmd *cobra.Command, outputDocument string, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeDocument(cmd, outputDocument, bz)
+}
+
+// writeDocument writes the document to the given file, or to the output of the
+// command without a file.
+func writeDocument(cmd *cobra.Command, outputDocument string, bz []byte) error {
+	if outputDocument == "" {
+		cmd.Println(string(bz))
+		return nil
+	}
+	return os.WriteFile(outputDocument, append(bz, '\n'), 0o600)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/crypto/hd"
+	"github.com/Finschia/finschia-sdk/crypto/keyring"
+	"github.com/Finschia/finschia-sdk/types/tx/signing"
+	authsigning "github.com/Finschia/finschia-sdk/x/auth/signing"
+
+	"github.com/Finschia/finschia/app"
+)
+
+func TestTxOffline(t *testing.T) {
+	kb := keyring.NewInMemory()
+	custody, err := kb.NewAccount("custody", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "", "m/44'/438'/0'/0/0", hd.Secp256k1)
+	require.NoError(t, err)
+	other, _, err := kb.NewMnemonic("other", keyring.English, "m/44'/438'/0'/0/0", "", hd.Secp256k1)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "template.json")
+	template := fmt.Sprintf(`{
+  "messages": [{
+    "@type": "/cosmos.bank.v1beta1.MsgSend",
+    "from_address": "%s",
+    "to_address": "%s",
+    "amount": [{"denom": "cony", "amount": "1000"}]
+  }],
+  "memo": "offline",
+  "fee": {"amount": [{"denom": "cony", "amount": "2000"}], "gas_limit": 200000}
+}`, custody.GetAddress(), other.GetAddress())
+	require.NoError(t, os.WriteFile(templateFile, []byte(template), 0o600))
+
+	execCmd := func(cmd *cobra.Command, args ...string) (string, error) {
+		clientCtx := client.Context{}.WithKeyring(kb).WithChainID("finschia-2")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		err := cmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &clientCtx))
+		return out.String(), err
+	}
+
+	testCases := map[string]struct {
+		signMode string
+		expected signing.SignMode
+		signed   string
+	}{
+		"direct": {
+			signMode: flags.SignModeDirect,
+			expected: signing.SignMode_SIGN_MODE_DIRECT,
+			signed:   `"@type": "/cosmos.bank.v1beta1.MsgSend"`,
+		},
+		"amino json": {
+			signMode: flags.SignModeLegacyAminoJSON,
+			expected: signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+			signed:   `"type": "cosmos-sdk/MsgSend"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requestFile := filepath.Join(dir, name+"-request.json")
+			_, err := execCmd(offlineBuildCmd(), templateFile,
+				"--from=custody", "--account-number=12", "--sequence=3",
+				"--sign-mode="+tc.signMode, "--output-document="+requestFile)
+			require.NoError(t, err)
+
+			out, err := execCmd(offlineVerifyCmd(), requestFile)
+			require.NoError(t, err)
+			require.Contains(t, out, "Sign mode: "+tc.expected.String())
+			require.Contains(t, out, "Signer: "+custody.GetAddress().String())
+			require.Contains(t, out, `"chain_id": "finschia-2"`)
+			require.Contains(t, out, `"account_number": "12"`)
+			require.Contains(t, out, tc.signed)
+			require.Contains(t, out, `"memo": "offline"`)
+
+			signedFile := filepath.Join(dir, name+"-signed.json")
+			_, err = execCmd(offlineSignCmd(), requestFile, "--from=custody", "--output-document="+signedFile)
+			require.NoError(t, err)
+
+			// the signature is of the sign bytes of the request
+			var req offlineSignRequest
+			bz, err := os.ReadFile(requestFile)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(bz, &req))
+
+			txConfig := app.MakeEncodingConfig().TxConfig
+			bz, err = os.ReadFile(signedFile)
+			require.NoError(t, err)
+			decoded, err := txConfig.TxJSONDecoder()(bz)
+			require.NoError(t, err)
+			sigs, err := decoded.(authsigning.Tx).GetSignaturesV2()
+			require.NoError(t, err)
+			require.Len(t, sigs, 1)
+			require.Equal(t, uint64(3), sigs[0].Sequence)
+			data := sigs[0].Data.(*signing.SingleSignatureData)
+			require.Equal(t, tc.expected, data.SignMode)
+			require.True(t, custody.GetPubKey().VerifySignature(req.SignBytes, data.Signature))
+
+			// the request is rejected once its sign bytes are not of its transaction
+			req.AccountNumber = 13
+			bz, err = json.Marshal(req)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(requestFile, bz, 0o600))
+			_, err = execCmd(offlineVerifyCmd(), requestFile)
+			require.ErrorContains(t, err, "are not the ones of its transaction")
+			_, err = execCmd(offlineSignCmd(), requestFile, "--from=custody")
+			require.ErrorContains(t, err, "are not the ones of its transaction")
+		})
+	}
+
+	// the signer must be the one of the messages
+	_, err = execCmd(offlineBuildCmd(), templateFile, "--from=other", "--account-number=12", "--sequence=3")
+	require.ErrorContains(t, err, "is not of the signer")
+}
+
+func TestParseSignMode(t *testing.T) {
+	for _, s := range []string{"direct", "SIGN_MODE_DIRECT"} {
+		signMode, err := parseSignMode(s)
+		require.NoError(t, err)
+		require.Equal(t, signing.SignMode_SIGN_MODE_DIRECT, signMode)
+	}
+	for _, s := range []string{"amino-json", "SIGN_MODE_LEGACY_AMINO_JSON"} {
+		signMode, err := parseSignMode(s)
+		require.NoError(t, err)
+		require.Equal(t, signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signMode)
+	}
+	_, err := parseSignMode("textual")
+	require.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	octypes "github.com/Finschia/ostracon/types"
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/server"
+	"github.com/Finschia/finschia-sdk/types/module"
+
+	"github.com/Finschia/finschia/app"
+)
+
+// validateGenesisCmd takes a genesis file, and makes sure that it is valid.
+// Unlike the one of genutil, it understands genesis states of modules
+// referenced by file (see app.GenesisFileRefKey), validating them one module
+// at a time.
+func validateGenesisCmd(mbm module.BasicManager) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate-genesis [file]",
+		Args:  cobra.RangeArgs(0, 1),
+		Short: "validates the genesis file at the default location or at the location passed as an arg",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			// Load default if passed no args, otherwise load passed file
+			var genesis string
+			if len(args) == 0 {
+				genesis = serverCtx.Config.GenesisFile()
+			} else {
+				genesis = args[0]
+			}
+
+			genDoc, err := octypes.GenesisDocFromFile(genesis)
+			if err != nil {
+				return err
+			}
+
+			var genState app.GenesisState
+			if err = json.Unmarshal(genDoc.AppState, &genState); err != nil {
+				return fmt.Errorf("error unmarshalling genesis doc %s: %s", genesis, err.Error())
+			}
+
+			if err = validateGenesisState(clientCtx, mbm, genState, app.NewGenesisFileResolver(genesis)); err != nil {
+				return fmt.Errorf("error validating genesis file %s: %s", genesis, err.Error())
+			}
+
+			cmd.Printf("File at %s is a valid genesis file\n", genesis)
+			return nil
+		},
+	}
+}
+
+// validateGenesisState validates the genesis state of each module, loading the
+// referenced states with resolver.
+func validateGenesisState(clientCtx client.Context, mbm module.BasicManager, genState app.GenesisState, resolver app.GenesisFileResolver) error {
+	moduleNames := make([]string, 0, len(mbm))
+	for moduleName := range mbm {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	for _, moduleName := range moduleNames {
+		moduleGenesis, err := resolver.LoadModuleGenesis(genState, moduleName)
+		if err != nil {
+			return err
+		}
+		if err := mbm[moduleName].ValidateGenesis(clientCtx.Codec, clientCtx.TxConfig, moduleGenesis); err != nil {
+			return err
+		}
+
+		// release the state of the module before loading the next one
+		delete(genState, moduleName)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Finschia/finschia-sdk/client"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+
+	"github.com/Finschia/finschia/app"
+)
+
+func TestValidateGenesisState(t *testing.T) {
+	encodingConfig := app.MakeEncodingConfig()
+	clientCtx := client.Context{}.
+		WithCodec(encodingConfig.Marshaler).
+		WithTxConfig(encodingConfig.TxConfig)
+
+	baseDir := t.TempDir()
+	valid := app.ModuleBasics[banktypes.ModuleName].DefaultGenesis(encodingConfig.Marshaler)
+	invalid := []byte(`{"supply":[{"denom":"","amount":"1"}]}`)
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "valid.json"), valid, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "invalid.json"), invalid, 0o600))
+
+	testCases := map[string]struct {
+		bankGenesis json.RawMessage
+		valid       bool
+	}{
+		"valid reference": {
+			bankGenesis: app.NewGenesisFileRef("valid.json", valid),
+			valid:       true,
+		},
+		"invalid state": {
+			bankGenesis: app.NewGenesisFileRef("invalid.json", invalid),
+		},
+		"file not found": {
+			bankGenesis: app.NewGenesisFileRef("nosuchfile.json", valid),
+		},
+		"hash mismatch": {
+			bankGenesis: app.NewGenesisFileRef("valid.json", invalid),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			genState := app.NewDefaultGenesisState(encodingConfig.Marshaler)
+			genState[banktypes.ModuleName] = tc.bankGenesis
+
+			err := validateGenesisState(clientCtx, app.ModuleBasics, genState, app.NewGenesisFileResolver(filepath.Join(baseDir, "genesis.json")))
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -18,7 +18,7 @@ fi
 ##
 ## Run binary with all parameters
 ##
-export FINSCHIAHOME="/data/node${ID}/finschia"
+export FINSCHIAHOME="${NODE_HOME:-/data/node${ID}/finschia}"
 
 if [ -d "$(dirname "${FINSCHIAHOME}"/"${LOG}")" ]; then
   "${BINARY}" --home "${FINSCHIAHOME}" "$@" | tee "${FINSCHIAHOME}/${LOG}"
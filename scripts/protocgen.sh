@@ -0,0 +1,12 @@
+#!/usr/bin/env bash
+
+set -eo pipefail
+
+proto_dirs=$(find ./proto -path -prune -o -name '*.proto' -print0 | xargs -0 -n1 dirname | sort | uniq)
+for dir in $proto_dirs; do
+  buf generate --template proto/buf.gen.gogo.yaml --path "${dir}"
+done
+
+# move proto files to the right places
+cp -r github.com/Finschia/finschia/* ./
+rm -rf github.com
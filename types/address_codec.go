@@ -0,0 +1,150 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/types/bech32"
+)
+
+// AddressKind is the kind of a Bech32 address, which determines its prefix
+// besides the network.
+type AddressKind int
+
+const (
+	AddressKindAcc AddressKind = iota
+	AddressKindVal
+	AddressKindCons
+)
+
+func (k AddressKind) String() string {
+	switch k {
+	case AddressKindAcc:
+		return "account"
+	case AddressKindVal:
+		return "validator"
+	case AddressKindCons:
+		return "consensus"
+	default:
+		return fmt.Sprintf("AddressKind(%d)", int(k))
+	}
+}
+
+// Bech32Prefix returns the prefix of the addresses of the given kind on the
+// given network.
+func Bech32Prefix(kind AddressKind, testnet bool) string {
+	switch kind {
+	case AddressKindVal:
+		return Bech32PrefixValAddr(testnet)
+	case AddressKindCons:
+		return Bech32PrefixConsAddr(testnet)
+	default:
+		return Bech32PrefixAcc(testnet)
+	}
+}
+
+// Address is a Bech32 address of either network.
+type Address struct {
+	Bytes   []byte
+	Kind    AddressKind
+	Testnet bool
+}
+
+// String returns the Bech32 form of the address.
+func (a Address) String() string {
+	s, err := bech32.ConvertAndEncode(Bech32Prefix(a.Kind, a.Testnet), a.Bytes)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// WithNetwork returns the address of the same bytes and kind on the given
+// network, e.g. tlink1... for link1... on the testnet.
+func (a Address) WithNetwork(testnet bool) Address {
+	a.Testnet = testnet
+	return a
+}
+
+// ParseAddress parses a Bech32 address of any kind of either network,
+// regardless of the prefixes in sdk.GetConfig().
+func ParseAddress(s string) (Address, error) {
+	hrp, bz, err := bech32.DecodeAndConvert(s)
+	if err != nil {
+		return Address{}, err
+	}
+	if err := sdk.VerifyAddressFormat(bz); err != nil {
+		return Address{}, err
+	}
+
+	for _, testnet := range []bool{false, true} {
+		for _, kind := range []AddressKind{AddressKindAcc, AddressKindVal, AddressKindCons} {
+			if hrp == Bech32Prefix(kind, testnet) {
+				return Address{Bytes: bz, Kind: kind, Testnet: testnet}, nil
+			}
+		}
+	}
+	return Address{}, fmt.Errorf("unknown bech32 prefix %q of %s", hrp, s)
+}
+
+// AddressCodec parses and formats the addresses of a network, so that a
+// process can handle both networks with a codec per network instead of the
+// prefixes sealed in sdk.GetConfig().
+type AddressCodec struct {
+	testnet bool
+}
+
+// NewAddressCodec returns the codec of the testnet or the mainnet addresses.
+func NewAddressCodec(testnet bool) AddressCodec {
+	return AddressCodec{testnet: testnet}
+}
+
+// Testnet returns whether the codec is of the testnet addresses.
+func (c AddressCodec) Testnet() bool {
+	return c.testnet
+}
+
+// Parse parses an address of the given kind, which must belong to the network
+// of the codec.
+func (c AddressCodec) Parse(s string, kind AddressKind) ([]byte, error) {
+	addr, err := ParseAddress(s)
+	if err != nil {
+		return nil, err
+	}
+	if addr.Testnet != c.testnet {
+		return nil, fmt.Errorf("%s is not an address of the %s, expected prefix %s", s, networkName(c.testnet), Bech32Prefix(kind, c.testnet))
+	}
+	if addr.Kind != kind {
+		return nil, fmt.Errorf("%s is not a %s address, expected prefix %s", s, kind, Bech32Prefix(kind, c.testnet))
+	}
+	return addr.Bytes, nil
+}
+
+// Format returns the Bech32 form of the address of the given kind on the
+// network of the codec.
+func (c AddressCodec) Format(bz []byte, kind AddressKind) string {
+	return Address{Bytes: bz, Kind: kind, Testnet: c.testnet}.String()
+}
+
+// ParseAccAddress parses an account address of the network of the codec.
+func (c AddressCodec) ParseAccAddress(s string) (sdk.AccAddress, error) {
+	return c.Parse(s, AddressKindAcc)
+}
+
+// ParseValAddress parses a validator operator address of the network of the
+// codec.
+func (c AddressCodec) ParseValAddress(s string) (sdk.ValAddress, error) {
+	return c.Parse(s, AddressKindVal)
+}
+
+// ParseConsAddress parses a consensus address of the network of the codec.
+func (c AddressCodec) ParseConsAddress(s string) (sdk.ConsAddress, error) {
+	return c.Parse(s, AddressKindCons)
+}
+
+func networkName(testnet bool) string {
+	if testnet {
+		return "testnet"
+	}
+	return "mainnet"
+}
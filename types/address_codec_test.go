@@ -0,0 +1,93 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	bz := []byte("address_____________")
+
+	testCases := map[string]struct {
+		kind    AddressKind
+		testnet bool
+	}{
+		"mainnet account":   {kind: AddressKindAcc},
+		"mainnet validator": {kind: AddressKindVal},
+		"mainnet consensus": {kind: AddressKindCons},
+		"testnet account":   {kind: AddressKindAcc, testnet: true},
+		"testnet validator": {kind: AddressKindVal, testnet: true},
+		"testnet consensus": {kind: AddressKindCons, testnet: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := NewAddressCodec(tc.testnet).Format(bz, tc.kind)
+			addr, err := ParseAddress(s)
+			require.NoError(t, err)
+			require.Equal(t, Address{Bytes: bz, Kind: tc.kind, Testnet: tc.testnet}, addr)
+			require.Equal(t, s, addr.String())
+
+			other := addr.WithNetwork(!tc.testnet)
+			require.Equal(t, NewAddressCodec(!tc.testnet).Format(bz, tc.kind), other.String())
+		})
+	}
+
+	_, err := ParseAddress("cosmos1w3jhxarpv3j8yh6lta047h6lta047h6lwpmfdp")
+	require.Error(t, err)
+	_, err = ParseAddress("link1invalid")
+	require.Error(t, err)
+}
+
+func TestAddressCodec(t *testing.T) {
+	bz := []byte("address_____________")
+	mainnet, testnet := NewAddressCodec(false), NewAddressCodec(true)
+
+	example, err := hex.DecodeString("2B909D13605707A1D0B0596997D7352EF45157BF")
+	require.NoError(t, err)
+	require.Equal(t, "link19wgf6ymq2ur6r59st95e04e49m69z4al4fc982", mainnet.Format(example, AddressKindAcc))
+	require.Equal(t, "tlink19wgf6ymq2ur6r59st95e04e49m69z4al37f470", testnet.Format(example, AddressKindAcc))
+
+	testCases := map[string]struct {
+		codec AddressCodec
+		addr  string
+		valid bool
+	}{
+		"mainnet account": {
+			codec: mainnet,
+			addr:  mainnet.Format(bz, AddressKindAcc),
+			valid: true,
+		},
+		"testnet account": {
+			codec: testnet,
+			addr:  testnet.Format(bz, AddressKindAcc),
+			valid: true,
+		},
+		"testnet account on mainnet": {
+			codec: mainnet,
+			addr:  testnet.Format(bz, AddressKindAcc),
+		},
+		"mainnet account on testnet": {
+			codec: testnet,
+			addr:  mainnet.Format(bz, AddressKindAcc),
+		},
+		"validator as account": {
+			codec: mainnet,
+			addr:  mainnet.Format(bz, AddressKindVal),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			addr, err := tc.codec.ParseAccAddress(tc.addr)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, bz, []byte(addr))
+		})
+	}
+}
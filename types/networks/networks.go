@@ -0,0 +1,127 @@
+// Package networks provides the profiles of the networks the client connects
+// to, so that the network specific settings are selected by name.
+package networks
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	Mainnet = "finschia"
+	Testnet = "ebony"
+	Local   = "local"
+
+	// ConfigKey is the table of client.toml holding the user-defined profiles,
+	// e.g.
+	//
+	//	[networks.devnet]
+	//	chain-id = "devnet-1"
+	//	testnet = true
+	//	node = "tcp://devnet.example.com:26657"
+	//	gas-prices = "0.015tcony"
+	//	denom = "tcony"
+	ConfigKey = "networks"
+)
+
+// Profile holds the settings of a network.
+type Profile struct {
+	Name string `mapstructure:"-"`
+	// ChainID is the chain ID of the transactions.
+	ChainID string `mapstructure:"chain-id"`
+	// Testnet selects the Bech32 prefixes of the testnet (tlink) instead of the
+	// ones of the mainnet (link).
+	Testnet bool `mapstructure:"testnet"`
+	// Node is the RPC endpoint of the node to connect to.
+	Node string `mapstructure:"node"`
+	// GasPrices are the default gas prices of the transactions.
+	GasPrices string `mapstructure:"gas-prices"`
+	// Denom is the denom of the fees and the stakes. The fees and gas prices
+	// given without a denom are in Denom.
+	Denom string `mapstructure:"denom"`
+}
+
+// NOTE: the built-in public networks leave the nodes and the gas prices to the
+// user-defined profiles of the same names in client.toml, as they depend on
+// the operators of the nodes.
+var builtinProfiles = []Profile{
+	{
+		Name:    Mainnet,
+		ChainID: "finschia-2",
+		Denom:   "cony",
+	},
+	{
+		Name:    Testnet,
+		ChainID: "ebony-2",
+		Testnet: true,
+		Denom:   "tcony",
+	},
+	{
+		Name:      Local,
+		Node:      "tcp://localhost:26657",
+		GasPrices: "0stake",
+		Denom:     "stake",
+	},
+}
+
+// aliases are the alternative names of the built-in profiles.
+var aliases = map[string]string{
+	"mainnet": Mainnet,
+	"testnet": Testnet,
+}
+
+// Registry holds the profiles of the networks by name.
+type Registry struct {
+	profiles map[string]Profile
+}
+
+// NewRegistry returns the registry of the built-in profiles and the
+// user-defined profiles of v, which are read from client.toml. The settings of
+// a user-defined profile override the ones of the built-in profile of the same
+// name. v may be nil.
+func NewRegistry(v *viper.Viper) (Registry, error) {
+	profiles := map[string]Profile{}
+	for _, profile := range builtinProfiles {
+		profiles[profile.Name] = profile
+	}
+
+	if v != nil {
+		for key := range v.GetStringMap(ConfigKey) {
+			name := key
+			if canonical, ok := aliases[name]; ok {
+				name = canonical
+			}
+			profile := profiles[name]
+			if err := v.UnmarshalKey(ConfigKey+"."+key, &profile); err != nil {
+				return Registry{}, fmt.Errorf("invalid network profile %s: %w", key, err)
+			}
+			profile.Name = name
+			profiles[name] = profile
+		}
+	}
+	return Registry{profiles: profiles}, nil
+}
+
+// Profile returns the profile of the given name or alias.
+func (r Registry) Profile(name string) (Profile, error) {
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+	profile, ok := r.profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown network %q, expected one of %v", name, r.Names())
+	}
+	return profile, nil
+}
+
+// Names returns the sorted names of the profiles.
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
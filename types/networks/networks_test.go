@@ -0,0 +1,96 @@
+package networks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	clientToml := `chain-id = ""
+node = "tcp://localhost:26657"
+
+[networks.testnet]
+node = "https://rpc.ebony.example.com:443"
+gas-prices = "0.015tcony"
+
+[networks.devnet]
+chain-id = "devnet-1"
+testnet = true
+node = "tcp://devnet.example.com:26657"
+denom = "tcony"
+`
+	path := filepath.Join(t.TempDir(), "client.toml")
+	require.NoError(t, os.WriteFile(path, []byte(clientToml), 0o600))
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+
+	registry, err := NewRegistry(v)
+	require.NoError(t, err)
+	require.Equal(t, []string{"devnet", Testnet, Mainnet, Local}, registry.Names())
+
+	testCases := map[string]struct {
+		name     string
+		expected Profile
+		valid    bool
+	}{
+		"built-in": {
+			name:     Mainnet,
+			expected: Profile{Name: Mainnet, ChainID: "finschia-2", Denom: "cony"},
+			valid:    true,
+		},
+		"alias": {
+			name:     "mainnet",
+			expected: Profile{Name: Mainnet, ChainID: "finschia-2", Denom: "cony"},
+			valid:    true,
+		},
+		"built-in overridden by alias": {
+			name: Testnet,
+			expected: Profile{
+				Name:      Testnet,
+				ChainID:   "ebony-2",
+				Testnet:   true,
+				Node:      "https://rpc.ebony.example.com:443",
+				GasPrices: "0.015tcony",
+				Denom:     "tcony",
+			},
+			valid: true,
+		},
+		"user-defined": {
+			name: "devnet",
+			expected: Profile{
+				Name:    "devnet",
+				ChainID: "devnet-1",
+				Testnet: true,
+				Node:    "tcp://devnet.example.com:26657",
+				Denom:   "tcony",
+			},
+			valid: true,
+		},
+		"unknown": {
+			name: "unknown",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			profile, err := registry.Profile(tc.name)
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, profile)
+		})
+	}
+}
+
+func TestRegistryWithoutConfig(t *testing.T) {
+	registry, err := NewRegistry(nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{Testnet, Mainnet, Local}, registry.Names())
+}
@@ -0,0 +1,55 @@
+package ante
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/authz"
+
+	"github.com/Finschia/finschia/x/blocklist/keeper"
+)
+
+// BlockedAddressDecorator rejects the txs transferring the coins of bank, the
+// tokens of token and collection, or the coins over IBC from or to an address
+// in the blocklist. The messages executed through authz are inspected as well.
+//
+// NOTE: the bank keeper and the msg servers of token and collection respecting
+// the blocklist reject the transfers anyway, whatever route the messages take.
+// The decorator keeps such txs out of the mempool.
+type BlockedAddressDecorator struct {
+	keeper keeper.Keeper
+}
+
+// NewBlockedAddressDecorator returns a BlockedAddressDecorator over the blocklist keeper.
+func NewBlockedAddressDecorator(k keeper.Keeper) BlockedAddressDecorator {
+	return BlockedAddressDecorator{
+		keeper: k,
+	}
+}
+
+func (d BlockedAddressDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if err := d.checkMsgs(tx.GetMsgs()); err != nil {
+		return ctx, err
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+func (d BlockedAddressDecorator) checkMsgs(msgs []sdk.Msg) error {
+	for _, msg := range msgs {
+		if exec, ok := msg.(*authz.MsgExec); ok {
+			inner, err := exec.GetMessages()
+			if err != nil {
+				return err
+			}
+			if err := d.checkMsgs(inner); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := d.keeper.CheckMsg(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
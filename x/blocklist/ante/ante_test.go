@@ -0,0 +1,83 @@
+package ante_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	"github.com/Finschia/finschia-sdk/x/authz"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+	ibctransfertypes "github.com/Finschia/ibc-go/v3/modules/apps/transfer/types"
+	clienttypes "github.com/Finschia/ibc-go/v3/modules/core/02-client/types"
+
+	"github.com/Finschia/finschia/app/helpers"
+	"github.com/Finschia/finschia/x/blocklist/ante"
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+type mockTx []sdk.Msg
+
+func (tx mockTx) GetMsgs() []sdk.Msg { return tx }
+func (mockTx) ValidateBasic() error  { return nil }
+
+func TestBlockedAddressDecorator(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	blocked := sdk.AccAddress("blocked_____________")
+	other := sdk.AccAddress("other_______________")
+	params := app.BlocklistKeeper.GetParams(ctx)
+	params.BlockedAddresses = []string{blocked.String()}
+	app.BlocklistKeeper.SetParams(ctx, params)
+	app.BlocklistKeeper.Refresh(ctx)
+
+	exec := authz.NewMsgExec(other, []sdk.Msg{&token.MsgSend{ContractId: "deadbeef", From: other.String(), To: blocked.String()}})
+	testCases := map[string]struct {
+		msg     sdk.Msg
+		blocked bool
+	}{
+		"token send from": {
+			msg:     &token.MsgSend{ContractId: "deadbeef", From: blocked.String(), To: other.String()},
+			blocked: true,
+		},
+		"token operator send": {
+			msg:     &token.MsgOperatorSend{ContractId: "deadbeef", Operator: blocked.String(), From: other.String(), To: other.String()},
+			blocked: true,
+		},
+		"collection send to": {
+			msg:     &collection.MsgSendNFT{ContractId: "deadbeef", From: other.String(), To: blocked.String()},
+			blocked: true,
+		},
+		"collection mint": {
+			msg:     &collection.MsgMintFT{ContractId: "deadbeef", From: other.String(), To: blocked.String()},
+			blocked: true,
+		},
+		"ibc transfer": {
+			msg:     ibctransfertypes.NewMsgTransfer("transfer", "channel-0", sdk.NewInt64Coin("stake", 1), blocked.String(), "cosmos1receiver", clienttypes.NewHeight(0, 100), 0),
+			blocked: true,
+		},
+		"authz exec": {
+			msg:     &exec,
+			blocked: true,
+		},
+		"not blocked": {
+			msg: &token.MsgSend{ContractId: "deadbeef", From: other.String(), To: other.String()},
+		},
+	}
+
+	decorator := ante.NewBlockedAddressDecorator(app.BlocklistKeeper)
+	next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) { return ctx, nil }
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := decorator.AnteHandle(ctx, mockTx{tc.msg}, false, next)
+			if tc.blocked {
+				require.ErrorIs(t, err, types.ErrBlockedAddress)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
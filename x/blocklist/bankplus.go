@@ -0,0 +1,44 @@
+package blocklist
+
+import (
+	"fmt"
+
+	"github.com/Finschia/finschia-sdk/codec"
+	"github.com/Finschia/finschia-sdk/types/module"
+	accountkeeper "github.com/Finschia/finschia-sdk/x/auth/keeper"
+	bankkeeper "github.com/Finschia/finschia-sdk/x/bank/keeper"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/bankplus"
+	bankpluskeeper "github.com/Finschia/finschia-sdk/x/bankplus/keeper"
+
+	"github.com/Finschia/finschia/x/blocklist/keeper"
+)
+
+// BankPlusAppModule is the bankplus module serving its messages by the bank
+// keeper respecting the blocklist.
+type BankPlusAppModule struct {
+	bankplus.AppModule
+
+	keeper keeper.BankKeeper
+}
+
+// NewBankPlusAppModule creates a new bankplus AppModule over the keeper
+// wrapping a bankplus BaseKeeper.
+func NewBankPlusAppModule(cdc codec.Codec, keeper keeper.BankKeeper, accountKeeper accountkeeper.AccountKeeper) BankPlusAppModule {
+	return BankPlusAppModule{
+		AppModule: bankplus.NewAppModule(cdc, keeper.Keeper, accountKeeper),
+		keeper:    keeper,
+	}
+}
+
+// RegisterServices registers the bank services as bankplus does, except that
+// the messages are served by the keeper respecting the blocklist.
+func (am BankPlusAppModule) RegisterServices(cfg module.Configurator) {
+	banktypes.RegisterMsgServer(cfg.MsgServer(), bankkeeper.NewMsgServerImpl(am.keeper))
+	banktypes.RegisterQueryServer(cfg.QueryServer(), am.keeper)
+
+	m := bankkeeper.NewMigrator(am.keeper.Keeper.(bankpluskeeper.BaseKeeper).BaseKeeper)
+	if err := cfg.RegisterMigration(banktypes.ModuleName, 1, m.Migrate1to2); err != nil {
+		panic(fmt.Sprintf("failed to migrate x/bank from version 1 to 2: %v", err))
+	}
+}
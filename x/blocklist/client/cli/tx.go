@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Finschia/finschia-sdk/client"
+	"github.com/Finschia/finschia-sdk/client/flags"
+	"github.com/Finschia/finschia-sdk/client/tx"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+// GetTxCmd returns the transaction commands for the blocklist module.
+// The txs are executed by the authority, e.g. through a proposal of foundation.
+func GetTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Blocklist transactions subcommands",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		NewTxCmdBlockAddress(),
+		NewTxCmdUnblockAddress(),
+	)
+
+	return cmd
+}
+
+func validateGenerateOnly(cmd *cobra.Command) error {
+	generateOnly, err := cmd.Flags().GetBool(flags.FlagGenerateOnly)
+	if err != nil {
+		return err
+	}
+	if !generateOnly {
+		return fmt.Errorf("you must use it with the flag --%s", flags.FlagGenerateOnly)
+	}
+	return nil
+}
+
+func NewTxCmdBlockAddress() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "block-address [authority] [address]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Block an address",
+		Long: `Block an address from the next block on. A module account of the app is
+no longer allowed to receive funds, and any other address to send nor receive them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateGenerateOnly(cmd); err != nil {
+				return err
+			}
+
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.MsgBlockAddress{
+				Authority: args[0],
+				Address:   args[1],
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), &msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+func NewTxCmdUnblockAddress() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unblock-address [authority] [address]",
+		Args:  cobra.ExactArgs(2),
+		Short: "Unblock an address",
+		Long:  `Lift the block of an address from the next block on.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateGenerateOnly(cmd); err != nil {
+				return err
+			}
+
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.MsgUnblockAddress{
+				Authority: args[0],
+				Address:   args[1],
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), &msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
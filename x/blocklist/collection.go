@@ -0,0 +1,36 @@
+package blocklist
+
+import (
+	"github.com/Finschia/finschia-sdk/codec"
+	"github.com/Finschia/finschia-sdk/types/module"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	collectionkeeper "github.com/Finschia/finschia-sdk/x/collection/keeper"
+	collectionmodule "github.com/Finschia/finschia-sdk/x/collection/module"
+
+	"github.com/Finschia/finschia/x/blocklist/keeper"
+)
+
+// CollectionAppModule is the collection module serving its messages by the msg server
+// respecting the blocklist.
+type CollectionAppModule struct {
+	collectionmodule.AppModule
+
+	keeper    collectionkeeper.Keeper
+	blocklist keeper.Keeper
+}
+
+// NewCollectionAppModule creates a new collection AppModule respecting the blocklist.
+func NewCollectionAppModule(cdc codec.Codec, keeper collectionkeeper.Keeper, blocklist keeper.Keeper) CollectionAppModule {
+	return CollectionAppModule{
+		AppModule: collectionmodule.NewAppModule(cdc, keeper),
+		keeper:    keeper,
+		blocklist: blocklist,
+	}
+}
+
+// RegisterServices registers the collection services as the collection module does, except
+// that the messages are served by the msg server respecting the blocklist.
+func (am CollectionAppModule) RegisterServices(cfg module.Configurator) {
+	collection.RegisterMsgServer(cfg.MsgServer(), keeper.NewCollectionMsgServer(collectionkeeper.NewMsgServer(am.keeper), am.blocklist))
+	collection.RegisterQueryServer(cfg.QueryServer(), collectionkeeper.NewQueryServer(am.keeper))
+}
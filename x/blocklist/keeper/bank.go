@@ -0,0 +1,147 @@
+package keeper
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+	sdkerrors "github.com/Finschia/finschia-sdk/types/errors"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	bankpluskeeper "github.com/Finschia/finschia-sdk/x/bankplus/keeper"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+var _ bankpluskeeper.Keeper = BankKeeper{}
+
+// BankKeeper wraps the bankplus keeper to tell the blocked addresses by the
+// blocklist instead of the static set given on construction, and to reject the
+// transfers from and to the addresses in the blocklist, including the payouts
+// and the (un)delegations of the modules, along with the payouts to the module
+// accounts not allowed to receive funds.
+//
+// The payouts the modules cannot refuse without halting the chain go to the
+// community pool instead, see DistrHooks and GovBankKeeper. The msgs of the
+// users are checked by the BlockedAddr of the msg servers and by the ante
+// handler.
+//
+// NOTE: the wrapped keeper should be constructed with no blocked addresses,
+// since its own methods tell them by the static set, which BankKeeper
+// overrides all the methods checking.
+type BankKeeper struct {
+	bankpluskeeper.Keeper
+
+	blocklist Keeper
+}
+
+// NewBankKeeper returns a bank keeper respecting the blocklist.
+func NewBankKeeper(bk bankpluskeeper.Keeper, blocklist Keeper) BankKeeper {
+	return BankKeeper{
+		Keeper:    bk,
+		blocklist: blocklist,
+	}
+}
+
+// BlockedAddr checks if a given address is restricted from receiving funds.
+func (k BankKeeper) BlockedAddr(addr sdk.AccAddress) bool {
+	return k.blocklist.BlockedAddr(addr)
+}
+
+// SendCoinsFromAccountToModule transfers coins from an AccAddress to a ModuleAccount.
+// It will panic if the module account does not exist.
+func (k BankKeeper) SendCoinsFromAccountToModule(
+	ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins,
+) error {
+	if err := k.checkNotBlocked(senderAddr.String()); err != nil {
+		return err
+	}
+
+	return k.Keeper.SendCoinsFromAccountToModule(ctx, senderAddr, recipientModule, amt)
+}
+
+// SendCoinsFromModuleToAccount transfers coins from a ModuleAccount to an
+// AccAddress. It fails if the recipient is not allowed to receive funds.
+func (k BankKeeper) SendCoinsFromModuleToAccount(
+	ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins,
+) error {
+	if err := k.checkReceivable(recipientAddr); err != nil {
+		return err
+	}
+
+	return k.Keeper.SendCoinsFromModuleToAccount(ctx, senderModule, recipientAddr, amt)
+}
+
+// DelegateCoinsFromAccountToModule delegates coins and transfers them from a
+// delegator account to a module account. It fails if the delegator is in the
+// blocklist.
+func (k BankKeeper) DelegateCoinsFromAccountToModule(
+	ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins,
+) error {
+	if err := k.checkNotBlocked(senderAddr.String()); err != nil {
+		return err
+	}
+
+	return k.Keeper.DelegateCoinsFromAccountToModule(ctx, senderAddr, recipientModule, amt)
+}
+
+// UndelegateCoinsFromModuleToAccount undelegates the unbonding coins and
+// transfers them from a module account to the delegator account. It fails if
+// the delegator is not allowed to receive funds, which leaves the mature
+// entries in the unbonding delegation, completed along with a later unbonding
+// of the delegation once the delegator is unblocked.
+func (k BankKeeper) UndelegateCoinsFromModuleToAccount(
+	ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins,
+) error {
+	if err := k.checkReceivable(recipientAddr); err != nil {
+		return err
+	}
+
+	return k.Keeper.UndelegateCoinsFromModuleToAccount(ctx, senderModule, recipientAddr, amt)
+}
+
+// SendCoins transfers amt coins from a sending account to a receiving account.
+// It fails if either of them is in the blocklist.
+func (k BankKeeper) SendCoins(ctx sdk.Context, fromAddr sdk.AccAddress, toAddr sdk.AccAddress, amt sdk.Coins) error {
+	for _, addr := range []sdk.AccAddress{fromAddr, toAddr} {
+		if err := k.checkNotBlocked(addr.String()); err != nil {
+			return err
+		}
+	}
+
+	return k.Keeper.SendCoins(ctx, fromAddr, toAddr, amt)
+}
+
+// InputOutputCoins performs multi-send functionality. It fails if any of the
+// inputs and outputs is in the blocklist.
+func (k BankKeeper) InputOutputCoins(ctx sdk.Context, inputs []banktypes.Input, outputs []banktypes.Output) error {
+	for _, in := range inputs {
+		if err := k.checkNotBlocked(in.Address); err != nil {
+			return err
+		}
+	}
+	for _, out := range outputs {
+		if err := k.checkNotBlocked(out.Address); err != nil {
+			return err
+		}
+	}
+
+	return k.Keeper.InputOutputCoins(ctx, inputs, outputs)
+}
+
+// checkReceivable fails if the address is in the blocklist, or is a module
+// account not allowed to receive funds.
+func (k BankKeeper) checkReceivable(addr sdk.AccAddress) error {
+	if err := k.checkNotBlocked(addr.String()); err != nil {
+		return err
+	}
+	if k.blocklist.BlockedAddr(addr) {
+		return sdkerrors.ErrUnauthorized.Wrapf("%s is not allowed to receive funds", addr)
+	}
+
+	return nil
+}
+
+func (k BankKeeper) checkNotBlocked(addr string) error {
+	if k.blocklist.IsBlockedAddress(addr) {
+		return types.ErrBlockedAddress.Wrapf("%s is not allowed to send nor receive funds", addr)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/Finschia/finschia-sdk/x/collection"
+)
+
+// collectionMsgServer serves the messages of collection, rejecting the
+// transfers from and to the addresses in the blocklist.
+type collectionMsgServer struct {
+	collection.MsgServer
+
+	blocklist Keeper
+}
+
+// NewCollectionMsgServer returns a collection MsgServer respecting the
+// blocklist over the given one.
+func NewCollectionMsgServer(server collection.MsgServer, blocklist Keeper) collection.MsgServer {
+	return collectionMsgServer{
+		MsgServer: server,
+		blocklist: blocklist,
+	}
+}
+
+func (s collectionMsgServer) SendFT(c context.Context, req *collection.MsgSendFT) (*collection.MsgSendFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.SendFT(c, req)
+}
+
+func (s collectionMsgServer) OperatorSendFT(c context.Context, req *collection.MsgOperatorSendFT) (*collection.MsgOperatorSendFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.OperatorSendFT(c, req)
+}
+
+func (s collectionMsgServer) SendNFT(c context.Context, req *collection.MsgSendNFT) (*collection.MsgSendNFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.SendNFT(c, req)
+}
+
+func (s collectionMsgServer) OperatorSendNFT(c context.Context, req *collection.MsgOperatorSendNFT) (*collection.MsgOperatorSendNFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.OperatorSendNFT(c, req)
+}
+
+func (s collectionMsgServer) IssueFT(c context.Context, req *collection.MsgIssueFT) (*collection.MsgIssueFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.IssueFT(c, req)
+}
+
+func (s collectionMsgServer) MintFT(c context.Context, req *collection.MsgMintFT) (*collection.MsgMintFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.MintFT(c, req)
+}
+
+func (s collectionMsgServer) MintNFT(c context.Context, req *collection.MsgMintNFT) (*collection.MsgMintNFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.MintNFT(c, req)
+}
+
+func (s collectionMsgServer) BurnFT(c context.Context, req *collection.MsgBurnFT) (*collection.MsgBurnFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.BurnFT(c, req)
+}
+
+func (s collectionMsgServer) OperatorBurnFT(c context.Context, req *collection.MsgOperatorBurnFT) (*collection.MsgOperatorBurnFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.OperatorBurnFT(c, req)
+}
+
+func (s collectionMsgServer) BurnNFT(c context.Context, req *collection.MsgBurnNFT) (*collection.MsgBurnNFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.BurnNFT(c, req)
+}
+
+func (s collectionMsgServer) OperatorBurnNFT(c context.Context, req *collection.MsgOperatorBurnNFT) (*collection.MsgOperatorBurnNFTResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.OperatorBurnNFT(c, req)
+}
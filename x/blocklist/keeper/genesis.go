@@ -0,0 +1,21 @@
+package keeper
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+// InitGenesis sets the params from the genesis state and loads the blocked
+// addresses.
+func (k Keeper) InitGenesis(ctx sdk.Context, data types.GenesisState) {
+	k.SetParams(ctx, data.Params)
+	k.Refresh(ctx)
+}
+
+// ExportGenesis returns the genesis state of the blocklist module.
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params: k.GetParams(ctx),
+	}
+}
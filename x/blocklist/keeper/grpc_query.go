@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+type queryServer struct {
+	keeper Keeper
+}
+
+// NewQueryServer returns an implementation of the blocklist QueryServer interface
+// for the provided Keeper.
+func NewQueryServer(keeper Keeper) types.QueryServer {
+	return &queryServer{
+		keeper: keeper,
+	}
+}
+
+var _ types.QueryServer = queryServer{}
+
+func (s queryServer) Params(c context.Context, req *types.QueryParamsRequest) (*types.QueryParamsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	return &types.QueryParamsResponse{Params: s.keeper.GetParams(ctx)}, nil
+}
+
+func (s queryServer) ModuleAccounts(c context.Context, req *types.QueryModuleAccountsRequest) (*types.QueryModuleAccountsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	return &types.QueryModuleAccountsResponse{ModuleAccounts: s.keeper.ModuleAccounts(ctx)}, nil
+}
+
+func (s queryServer) BlockedAddresses(c context.Context, req *types.QueryBlockedAddressesRequest) (*types.QueryBlockedAddressesResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	ctx := sdk.UnwrapSDKContext(c)
+	return &types.QueryBlockedAddressesResponse{Addresses: s.keeper.BlockedAddresses(ctx)}, nil
+}
+
+func (s queryServer) BlockedAddress(c context.Context, req *types.QueryBlockedAddressRequest) (*types.QueryBlockedAddressResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+
+	addr, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address: %s", err)
+	}
+
+	// under the current params, not as of the last Refresh
+	ctx := sdk.UnwrapSDKContext(c)
+	blocked := stringSet(s.keeper.BlockedAddresses(ctx))[addr.String()]
+	return &types.QueryBlockedAddressResponse{Address: addr.String(), Blocked: blocked}, nil
+}
@@ -0,0 +1,271 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/Finschia/ostracon/libs/log"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	authtypes "github.com/Finschia/finschia-sdk/x/auth/types"
+	paramtypes "github.com/Finschia/finschia-sdk/x/params/types"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+// Keeper of the blocklist module. It keeps the blocked addresses derived from
+// the params in memory, so that the bank keeper can tell them without a context.
+type Keeper struct {
+	paramSpace paramtypes.Subspace
+
+	// names of the module accounts of the app, sorted
+	moduleAccounts []string
+
+	// the address capable of executing the msgs of the module
+	authority string
+
+	// blocked holds the *blockedAddrs, which is replaced as a whole on Refresh.
+	blocked *atomic.Value
+}
+
+// blockedAddrs are the addresses blocked as of the last Refresh.
+type blockedAddrs struct {
+	// module accounts not allowed to receive funds
+	moduleAccounts map[string]bool
+
+	// addresses not allowed to send nor receive funds
+	addrsSet map[string]bool
+}
+
+// NewKeeper returns a blocklist keeper over the module accounts of the given
+// names, whose msgs are executed by the authority.
+func NewKeeper(paramSpace paramtypes.Subspace, moduleAccounts []string, authority string) Keeper {
+	if _, err := sdk.AccAddressFromBech32(authority); err != nil {
+		panic(fmt.Errorf("invalid authority address: %w", err))
+	}
+
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	names := make([]string, len(moduleAccounts))
+	copy(names, moduleAccounts)
+	sort.Strings(names)
+
+	k := Keeper{
+		paramSpace:     paramSpace,
+		moduleAccounts: names,
+		authority:      authority,
+		blocked:        &atomic.Value{},
+	}
+	k.blocked.Store(k.blockedAddrs(types.DefaultParams()))
+
+	return k
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetAuthority returns the address capable of executing the msgs of the module.
+func (k Keeper) GetAuthority() string {
+	return k.authority
+}
+
+// GetParams returns the params of the blocklist module. The default params
+// apply to the chains which have not set them yet.
+func (k Keeper) GetParams(ctx sdk.Context) types.Params {
+	params := types.DefaultParams()
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the params of the blocklist module.
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// Refresh reloads the blocked addresses from the params in the state, so that
+// the parameter changes take effect without restarting the node.
+func (k Keeper) Refresh(ctx sdk.Context) {
+	k.blocked.Store(k.blockedAddrs(k.GetParams(ctx)))
+}
+
+// EmitBlockEvents emits an event for every address blocked or unblocked by the
+// change of the params from prev to the current ones. A module account of the
+// app is blocked when it is no longer allowed to receive funds.
+func (k Keeper) EmitBlockEvents(ctx sdk.Context, prev types.Params) {
+	prevBlocked := k.blockedList(prev)
+	nextBlocked := k.blockedList(k.GetParams(ctx))
+	prevSet := stringSet(prevBlocked)
+	nextSet := stringSet(nextBlocked)
+
+	for _, addr := range nextBlocked {
+		if !prevSet[addr] {
+			k.Logger(ctx).Info("address blocked", types.AttributeKeyAddress, addr)
+			ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeBlockAddress,
+				sdk.NewAttribute(types.AttributeKeyAddress, addr)))
+		}
+	}
+	for _, addr := range prevBlocked {
+		if !nextSet[addr] {
+			k.Logger(ctx).Info("address unblocked", types.AttributeKeyAddress, addr)
+			ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeUnblockAddress,
+				sdk.NewAttribute(types.AttributeKeyAddress, addr)))
+		}
+	}
+}
+
+// BlockedAddr returns whether the address is not allowed to receive funds, as
+// of the last Refresh.
+func (k Keeper) BlockedAddr(addr sdk.AccAddress) bool {
+	blocked := k.load()
+	return blocked.moduleAccounts[addr.String()] || blocked.addrsSet[addr.String()]
+}
+
+// IsBlockedAddress returns whether the address is in the blocklist, which is
+// not allowed to send nor receive funds, as of the last Refresh. The address
+// may be in any case of bech32; an invalid one is never blocked.
+func (k Keeper) IsBlockedAddress(addr string) bool {
+	acc, err := sdk.AccAddressFromBech32(addr)
+	if err != nil {
+		return false
+	}
+
+	return k.load().addrsSet[acc.String()]
+}
+
+// BlockAddress blocks the address from the next block on. A module account of
+// the app is no longer allowed to receive funds, and any other address is put
+// in the blocklist.
+func (k Keeper) BlockAddress(ctx sdk.Context, addr sdk.AccAddress) error {
+	prev := k.GetParams(ctx)
+	params := k.GetParams(ctx)
+	if name, ok := k.moduleAccountName(addr); ok {
+		receivable := stringSet(params.ReceivableModuleAccounts)
+		if !receivable[name] {
+			return types.ErrAlreadyBlocked.Wrapf("module account %s", name)
+		}
+		params.ReceivableModuleAccounts = removeString(params.ReceivableModuleAccounts, name)
+	} else {
+		if stringSet(params.BlockedAddresses)[addr.String()] {
+			return types.ErrAlreadyBlocked.Wrap(addr.String())
+		}
+		params.BlockedAddresses = append(params.BlockedAddresses, addr.String())
+	}
+
+	k.SetParams(ctx, params)
+	k.EmitBlockEvents(ctx, prev)
+	return nil
+}
+
+// UnblockAddress lifts the block of the address from the next block on.
+func (k Keeper) UnblockAddress(ctx sdk.Context, addr sdk.AccAddress) error {
+	prev := k.GetParams(ctx)
+	params := k.GetParams(ctx)
+	if name, ok := k.moduleAccountName(addr); ok {
+		receivable := stringSet(params.ReceivableModuleAccounts)
+		if receivable[name] {
+			return types.ErrNotBlocked.Wrapf("module account %s", name)
+		}
+		params.ReceivableModuleAccounts = append(params.ReceivableModuleAccounts, name)
+	} else {
+		if !stringSet(params.BlockedAddresses)[addr.String()] {
+			return types.ErrNotBlocked.Wrap(addr.String())
+		}
+		params.BlockedAddresses = removeString(params.BlockedAddresses, addr.String())
+	}
+
+	k.SetParams(ctx, params)
+	k.EmitBlockEvents(ctx, prev)
+	return nil
+}
+
+// BlockedAddresses returns the addresses in the blocklist under the current params.
+func (k Keeper) BlockedAddresses(ctx sdk.Context) []string {
+	return k.GetParams(ctx).BlockedAddresses
+}
+
+// ModuleAccounts returns the module accounts of the app along with whether they
+// may receive funds under the current params.
+func (k Keeper) ModuleAccounts(ctx sdk.Context) []types.ModuleAccount {
+	receivable := stringSet(k.GetParams(ctx).ReceivableModuleAccounts)
+
+	accounts := make([]types.ModuleAccount, 0, len(k.moduleAccounts))
+	for _, name := range k.moduleAccounts {
+		accounts = append(accounts, types.ModuleAccount{
+			Name:       name,
+			Address:    authtypes.NewModuleAddress(name).String(),
+			Receivable: receivable[name],
+		})
+	}
+
+	return accounts
+}
+
+func (k Keeper) moduleAccountName(addr sdk.AccAddress) (string, bool) {
+	for _, name := range k.moduleAccounts {
+		if authtypes.NewModuleAddress(name).Equals(addr) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func (k Keeper) load() *blockedAddrs {
+	return k.blocked.Load().(*blockedAddrs)
+}
+
+func (k Keeper) blockedAddrs(params types.Params) *blockedAddrs {
+	receivable := stringSet(params.ReceivableModuleAccounts)
+
+	moduleAccounts := make(map[string]bool, len(k.moduleAccounts))
+	for _, name := range k.moduleAccounts {
+		if !receivable[name] {
+			moduleAccounts[authtypes.NewModuleAddress(name).String()] = true
+		}
+	}
+
+	return &blockedAddrs{
+		moduleAccounts: moduleAccounts,
+		addrsSet:       stringSet(params.BlockedAddresses),
+	}
+}
+
+// blockedList returns the module accounts not allowed to receive funds in the
+// order of their names, followed by the addresses in the blocklist.
+func (k Keeper) blockedList(params types.Params) []string {
+	receivable := stringSet(params.ReceivableModuleAccounts)
+
+	addrs := make([]string, 0, len(k.moduleAccounts)+len(params.BlockedAddresses))
+	for _, name := range k.moduleAccounts {
+		if !receivable[name] {
+			addrs = append(addrs, authtypes.NewModuleAddress(name).String())
+		}
+	}
+
+	return append(addrs, params.BlockedAddresses...)
+}
+
+func stringSet(strs []string) map[string]bool {
+	set := make(map[string]bool, len(strs))
+	for _, str := range strs {
+		set[str] = true
+	}
+
+	return set
+}
+
+func removeString(strs []string, str string) []string {
+	res := make([]string, 0, len(strs))
+	for _, s := range strs {
+		if s != str {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,380 @@
+package keeper_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+
+	"github.com/Finschia/finschia-sdk/baseapp"
+	codectypes "github.com/Finschia/finschia-sdk/codec/types"
+	sdk "github.com/Finschia/finschia-sdk/types"
+	sdkerrors "github.com/Finschia/finschia-sdk/types/errors"
+	authtypes "github.com/Finschia/finschia-sdk/x/auth/types"
+	"github.com/Finschia/finschia-sdk/x/authz"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	distrtypes "github.com/Finschia/finschia-sdk/x/distribution/types"
+	"github.com/Finschia/finschia-sdk/x/foundation"
+	govtypes "github.com/Finschia/finschia-sdk/x/gov/types"
+	minttypes "github.com/Finschia/finschia-sdk/x/mint/types"
+	"github.com/Finschia/finschia-sdk/x/params"
+	paramproposal "github.com/Finschia/finschia-sdk/x/params/types/proposal"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+	"github.com/Finschia/finschia-sdk/x/token"
+	tokenkeeper "github.com/Finschia/finschia-sdk/x/token/keeper"
+
+	"github.com/Finschia/finschia/app/helpers"
+	"github.com/Finschia/finschia/x/blocklist"
+	"github.com/Finschia/finschia/x/blocklist/keeper"
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+func TestReceivableModuleAccounts(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	govAddr := authtypes.NewModuleAddress(govtypes.ModuleName)
+	require.True(t, app.BankKeeper.BlockedAddr(govAddr))
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 10))
+
+	// the messages of bank are served by the keeper respecting the blocklist
+	sender := sdk.AccAddress("sender______________")
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, sender, coins))
+	msg := banktypes.NewMsgSend(sender, govAddr, coins)
+	_, err := app.MsgServiceRouter().Handler(msg)(ctx, msg)
+	require.ErrorIs(t, err, sdkerrors.ErrUnauthorized)
+
+	// change the params through a gov proposal
+	handler := params.NewParamChangeProposalHandler(app.ParamsKeeper)
+	proposal := paramproposal.NewParameterChangeProposal("title", "description", []paramproposal.ParamChange{
+		paramproposal.NewParamChange(types.ModuleName, string(types.KeyReceivableModuleAccounts), `["gov"]`),
+	})
+	require.NoError(t, handler(ctx, proposal))
+
+	// not in effect until the blocked addresses are reloaded
+	require.True(t, app.BankKeeper.BlockedAddr(govAddr))
+	app.BlocklistKeeper.Refresh(ctx)
+	require.False(t, app.BankKeeper.BlockedAddr(govAddr))
+	require.True(t, app.BankKeeper.BlockedAddr(authtypes.NewModuleAddress(minttypes.ModuleName)))
+	_, err = app.MsgServiceRouter().Handler(msg)(ctx, msg)
+	require.NoError(t, err)
+
+	for _, acc := range app.BlocklistKeeper.ModuleAccounts(ctx) {
+		require.Equal(t, acc.Name == govtypes.ModuleName, acc.Receivable, acc.Name)
+	}
+	require.Equal(t, []string{govtypes.ModuleName}, app.BlocklistKeeper.ExportGenesis(ctx).Params.ReceivableModuleAccounts)
+}
+
+func TestInvalidParams(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	handler := params.NewParamChangeProposalHandler(app.ParamsKeeper)
+	addr := sdk.AccAddress("addr________________").String()
+	for name, tc := range map[string]struct {
+		key   []byte
+		value string
+	}{
+		"empty name":        {types.KeyReceivableModuleAccounts, `[""]`},
+		"duplicate name":    {types.KeyReceivableModuleAccounts, `["gov","gov"]`},
+		"invalid address":   {types.KeyBlockedAddresses, `["link1invalid"]`},
+		"duplicate address": {types.KeyBlockedAddresses, `["` + addr + `","` + addr + `"]`},
+		"upper case":        {types.KeyBlockedAddresses, `["` + strings.ToUpper(addr) + `"]`},
+	} {
+		proposal := paramproposal.NewParameterChangeProposal("title", "description", []paramproposal.ParamChange{
+			paramproposal.NewParamChange(types.ModuleName, string(tc.key), tc.value),
+		})
+		require.Error(t, handler(ctx, proposal), name)
+	}
+}
+
+func TestBlockedAddresses(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	blocked := sdk.AccAddress("blocked_____________")
+	other := sdk.AccAddress("other_______________")
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 10))
+	for _, addr := range []sdk.AccAddress{blocked, other} {
+		require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+		require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, addr, coins))
+	}
+
+	changeBlockedAddresses := func(value string) sdk.Events {
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+		handler := blocklist.NewParamChangeProposalHandler(params.NewParamChangeProposalHandler(app.ParamsKeeper), app.BlocklistKeeper)
+		proposal := paramproposal.NewParameterChangeProposal("title", "description", []paramproposal.ParamChange{
+			paramproposal.NewParamChange(types.ModuleName, string(types.KeyBlockedAddresses), value),
+		})
+		require.NoError(t, handler(ctx, proposal))
+		events := ctx.EventManager().Events()
+
+		// the events come from the proposal, not from the reload
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+		app.BlocklistKeeper.Refresh(ctx)
+		require.Empty(t, ctx.EventManager().Events())
+
+		return events
+	}
+
+	require.Equal(t, sdk.Events{
+		sdk.NewEvent(types.EventTypeBlockAddress, sdk.NewAttribute(types.AttributeKeyAddress, blocked.String())),
+	}, changeBlockedAddresses(`["`+blocked.String()+`"]`))
+
+	require.True(t, app.BlocklistKeeper.IsBlockedAddress(blocked.String()))
+	require.True(t, app.BlocklistKeeper.IsBlockedAddress(strings.ToUpper(blocked.String())))
+	require.True(t, app.BankKeeper.BlockedAddr(blocked))
+	require.ErrorIs(t, app.BankKeeper.SendCoins(ctx, blocked, other, coins), types.ErrBlockedAddress)
+	require.ErrorIs(t, app.BankKeeper.SendCoins(ctx, other, blocked, coins), types.ErrBlockedAddress)
+	require.ErrorIs(t, app.BankKeeper.SendCoinsFromAccountToModule(ctx, blocked, minttypes.ModuleName, coins), types.ErrBlockedAddress)
+	// nor are the payouts of the modules
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.ErrorIs(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, blocked, coins), types.ErrBlockedAddress)
+	require.Equal(t, []string{blocked.String()}, app.BlocklistKeeper.ExportGenesis(ctx).Params.BlockedAddresses)
+
+	require.Equal(t, sdk.Events{
+		sdk.NewEvent(types.EventTypeUnblockAddress, sdk.NewAttribute(types.AttributeKeyAddress, blocked.String())),
+	}, changeBlockedAddresses(`[]`))
+	require.NoError(t, app.BankKeeper.SendCoins(ctx, blocked, other, coins))
+}
+
+func TestMsgServer(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	authority := foundation.DefaultAuthority().String()
+	addr := sdk.AccAddress("addr________________")
+	govAddr := authtypes.NewModuleAddress(govtypes.ModuleName)
+
+	var events sdk.Events
+	execute := func(msg sdk.Msg) error {
+		res, err := app.MsgServiceRouter().Handler(msg)(ctx, msg)
+		if err != nil {
+			return err
+		}
+		events = res.GetEvents()
+		return nil
+	}
+
+	// only the authority executes the msgs
+	require.ErrorIs(t, execute(&types.MsgBlockAddress{Authority: addr.String(), Address: addr.String()}), sdkerrors.ErrUnauthorized)
+	require.ErrorIs(t, execute(&types.MsgUnblockAddress{Authority: addr.String(), Address: govAddr.String()}), sdkerrors.ErrUnauthorized)
+
+	// the address is stored in the canonical form
+	require.NoError(t, execute(&types.MsgBlockAddress{Authority: authority, Address: strings.ToUpper(addr.String())}))
+	require.Equal(t, sdk.Events{
+		sdk.NewEvent(types.EventTypeBlockAddress, sdk.NewAttribute(types.AttributeKeyAddress, addr.String())),
+	}, events)
+	require.ErrorIs(t, execute(&types.MsgBlockAddress{Authority: authority, Address: addr.String()}), types.ErrAlreadyBlocked)
+	require.Equal(t, []string{addr.String()}, app.BlocklistKeeper.BlockedAddresses(ctx))
+
+	// a module account toggles whether it may receive funds
+	require.ErrorIs(t, execute(&types.MsgBlockAddress{Authority: authority, Address: govAddr.String()}), types.ErrAlreadyBlocked)
+	require.NoError(t, execute(&types.MsgUnblockAddress{Authority: authority, Address: govAddr.String()}))
+	require.Equal(t, sdk.Events{
+		sdk.NewEvent(types.EventTypeUnblockAddress, sdk.NewAttribute(types.AttributeKeyAddress, govAddr.String())),
+	}, events)
+	require.Equal(t, []string{govtypes.ModuleName}, app.BlocklistKeeper.GetParams(ctx).ReceivableModuleAccounts)
+
+	// not in effect until the blocked addresses are reloaded
+	require.False(t, app.BlocklistKeeper.IsBlockedAddress(addr.String()))
+	require.True(t, app.BankKeeper.BlockedAddr(govAddr))
+	app.BlocklistKeeper.Refresh(ctx)
+	require.True(t, app.BlocklistKeeper.IsBlockedAddress(addr.String()))
+	require.False(t, app.BankKeeper.BlockedAddr(govAddr))
+
+	require.NoError(t, execute(&types.MsgUnblockAddress{Authority: authority, Address: addr.String()}))
+	require.ErrorIs(t, execute(&types.MsgUnblockAddress{Authority: authority, Address: addr.String()}), types.ErrNotBlocked)
+	require.NoError(t, execute(&types.MsgBlockAddress{Authority: authority, Address: govAddr.String()}))
+	require.ErrorIs(t, execute(&types.MsgUnblockAddress{Authority: authority, Address: addr.String()}), types.ErrNotBlocked)
+	require.Empty(t, app.BlocklistKeeper.GetParams(ctx).ReceivableModuleAccounts)
+	require.Empty(t, app.BlocklistKeeper.GetParams(ctx).BlockedAddresses)
+}
+
+func TestQueryServer(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	blocked := sdk.AccAddress("blocked_____________")
+	app.BlocklistKeeper.SetParams(ctx, types.Params{
+		ReceivableModuleAccounts: []string{govtypes.ModuleName},
+		BlockedAddresses:         []string{blocked.String()},
+	})
+
+	queryHelper := baseapp.NewQueryServerTestHelper(ctx, app.InterfaceRegistry())
+	types.RegisterQueryServer(queryHelper, keeper.NewQueryServer(app.BlocklistKeeper))
+	queryClient := types.NewQueryClient(queryHelper)
+
+	params, err := queryClient.Params(ctx.Context(), &types.QueryParamsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, app.BlocklistKeeper.GetParams(ctx), params.Params)
+
+	accounts, err := queryClient.ModuleAccounts(ctx.Context(), &types.QueryModuleAccountsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, app.BlocklistKeeper.ModuleAccounts(ctx), accounts.ModuleAccounts)
+
+	addrs, err := queryClient.BlockedAddresses(ctx.Context(), &types.QueryBlockedAddressesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{blocked.String()}, addrs.Addresses)
+
+	for addr, expected := range map[string]types.QueryBlockedAddressResponse{
+		blocked.String():                  {Address: blocked.String(), Blocked: true},
+		strings.ToUpper(blocked.String()): {Address: blocked.String(), Blocked: true},
+		sdk.AccAddress("other_______________").String(): {
+			Address: sdk.AccAddress("other_______________").String(),
+		},
+	} {
+		res, err := queryClient.BlockedAddress(ctx.Context(), &types.QueryBlockedAddressRequest{Address: addr})
+		require.NoError(t, err, addr)
+		require.Equal(t, expected, *res, addr)
+	}
+
+	_, err = queryClient.BlockedAddress(ctx.Context(), &types.QueryBlockedAddressRequest{Address: "link1invalid"})
+	require.Error(t, err)
+}
+
+func TestRemoveValidatorWithBlockedWithdrawAddress(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	valAddr := sdk.ValAddress("validator___________")
+
+	withdrawAddr := sdk.AccAddress("withdraw____________")
+	require.NoError(t, app.DistrKeeper.SetWithdrawAddr(ctx, sdk.AccAddress(valAddr), withdrawAddr))
+	app.BlocklistKeeper.SetParams(ctx, types.Params{BlockedAddresses: []string{withdrawAddr.String()}})
+	app.BlocklistKeeper.Refresh(ctx)
+	require.True(t, app.BlocklistKeeper.IsBlockedAddress(withdrawAddr.String()))
+
+	// the commission of the validator, held by distribution
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 10))
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, distrtypes.ModuleName, coins))
+	commission := sdk.NewDecCoinsFromCoins(coins...)
+	app.DistrKeeper.SetValidatorAccumulatedCommission(ctx, valAddr, distrtypes.ValidatorAccumulatedCommission{Commission: commission})
+	app.DistrKeeper.SetValidatorOutstandingRewards(ctx, valAddr, distrtypes.ValidatorOutstandingRewards{Rewards: commission})
+
+	// distribution force-withdraws the commission, which must not halt the
+	// chain, and goes to the community pool instead
+	pool := app.DistrKeeper.GetFeePoolCommunityCoins(ctx)
+	require.NotPanics(t, func() {
+		app.StakingKeeper.AfterValidatorRemoved(ctx, sdk.ConsAddress(valAddr), valAddr)
+	})
+	require.True(t, app.BankKeeper.GetAllBalances(ctx, withdrawAddr).IsZero())
+	require.Equal(t, pool.Add(commission...), app.DistrKeeper.GetFeePoolCommunityCoins(ctx))
+}
+
+func TestModulePayoutsAndDelegations(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 10))
+	blocked := sdk.AccAddress("blocked_____________")
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins.Add(coins...)))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, blocked, coins))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, stakingtypes.NotBondedPoolName, coins))
+	app.BlocklistKeeper.SetParams(ctx, types.Params{BlockedAddresses: []string{blocked.String()}})
+	app.BlocklistKeeper.Refresh(ctx)
+
+	// the modules pay neither the blocked addresses nor the module accounts
+	// not allowed to receive funds
+	err := app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, blocked, coins)
+	require.ErrorIs(t, err, types.ErrBlockedAddress)
+	err = app.BankKeeper.SendCoinsFromModuleToAccount(ctx, stakingtypes.NotBondedPoolName, authtypes.NewModuleAddress(govtypes.ModuleName), coins)
+	require.ErrorIs(t, err, sdkerrors.ErrUnauthorized)
+
+	// nor do the blocked addresses delegate or undelegate
+	err = app.BankKeeper.DelegateCoinsFromAccountToModule(ctx, blocked, stakingtypes.BondedPoolName, coins)
+	require.ErrorIs(t, err, types.ErrBlockedAddress)
+	err = app.BankKeeper.UndelegateCoinsFromModuleToAccount(ctx, stakingtypes.NotBondedPoolName, blocked, coins)
+	require.ErrorIs(t, err, types.ErrBlockedAddress)
+
+	require.Equal(t, coins, app.BankKeeper.GetAllBalances(ctx, blocked))
+	require.Equal(t, coins, app.BankKeeper.GetAllBalances(ctx, authtypes.NewModuleAddress(stakingtypes.NotBondedPoolName)))
+}
+
+func TestRefundDepositsToBlockedAddress(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 10))
+	depositor := sdk.AccAddress("depositor___________")
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, govtypes.ModuleName, coins))
+	app.GovKeeper.SetDeposit(ctx, govtypes.NewDeposit(1, depositor, coins))
+	app.BlocklistKeeper.SetParams(ctx, types.Params{BlockedAddresses: []string{depositor.String()}})
+	app.BlocklistKeeper.Refresh(ctx)
+
+	// gov refunds the deposits at the end of a block, which must not halt the
+	// chain, and the refund goes to the community pool instead
+	pool := app.DistrKeeper.GetFeePoolCommunityCoins(ctx)
+	require.NotPanics(t, func() {
+		app.GovKeeper.RefundDeposits(ctx, 1)
+	})
+	require.True(t, app.BankKeeper.GetAllBalances(ctx, depositor).IsZero())
+	require.True(t, app.BankKeeper.GetAllBalances(ctx, authtypes.NewModuleAddress(govtypes.ModuleName)).IsZero())
+	require.Equal(t, pool.Add(sdk.NewDecCoinsFromCoins(coins...)...), app.DistrKeeper.GetFeePoolCommunityCoins(ctx))
+	_, found := app.GovKeeper.GetDeposit(ctx, 1, depositor)
+	require.False(t, found)
+}
+
+func TestTokenAndCollection(t *testing.T) {
+	app := helpers.Setup(t, false, 1)
+	ctx := app.BaseApp.NewContext(false, tmproto.Header{})
+
+	blocked := sdk.AccAddress("blocked_____________")
+	other := sdk.AccAddress("other_______________")
+	app.BlocklistKeeper.SetParams(ctx, types.Params{BlockedAddresses: []string{blocked.String()}})
+	app.BlocklistKeeper.Refresh(ctx)
+
+	issued, err := tokenkeeper.NewMsgServer(app.TokenKeeper).Issue(sdk.WrapSDKContext(ctx), &token.MsgIssue{
+		Name:     "test",
+		Symbol:   "TT",
+		Mintable: true,
+		Owner:    other.String(),
+		To:       other.String(),
+		Amount:   sdk.NewInt(10),
+	})
+	require.NoError(t, err)
+	contractID := issued.ContractId
+
+	// the messages routed by wasm, foundation, authz or interchain accounts
+	// reach the msg servers without passing the ante handler
+	execute := func(msg sdk.Msg) error {
+		_, err := app.MsgServiceRouter().Handler(msg)(ctx, msg)
+		return err
+	}
+	for name, msg := range map[string]sdk.Msg{
+		"token send":          &token.MsgSend{ContractId: contractID, From: other.String(), To: blocked.String(), Amount: sdk.OneInt()},
+		"token operator send": &token.MsgOperatorSend{ContractId: contractID, Operator: blocked.String(), From: other.String(), To: other.String(), Amount: sdk.OneInt()},
+		"token mint":          &token.MsgMint{ContractId: contractID, From: other.String(), To: blocked.String(), Amount: sdk.OneInt()},
+		"collection send ft":  &collection.MsgSendFT{ContractId: contractID, From: other.String(), To: blocked.String()},
+		"collection send nft": &collection.MsgSendNFT{ContractId: contractID, From: blocked.String(), To: other.String()},
+		"collection mint nft": &collection.MsgMintNFT{ContractId: contractID, From: other.String(), To: blocked.String()},
+		"collection burn ft":  &collection.MsgBurnFT{ContractId: contractID, From: blocked.String()},
+		"token send through authz": &authz.MsgExec{
+			Grantee: other.String(),
+			Msgs:    mustPackMsgs(t, &token.MsgSend{ContractId: contractID, From: other.String(), To: blocked.String(), Amount: sdk.OneInt()}),
+		},
+	} {
+		require.ErrorIs(t, execute(msg), types.ErrBlockedAddress, name)
+	}
+
+	require.NoError(t, execute(&token.MsgSend{ContractId: contractID, From: other.String(), To: other.String(), Amount: sdk.OneInt()}))
+}
+
+func mustPackMsgs(t *testing.T, msgs ...sdk.Msg) []*codectypes.Any {
+	t.Helper()
+
+	anys := make([]*codectypes.Any, len(msgs))
+	for i, msg := range msgs {
+		any, err := codectypes.NewAnyWithValue(msg)
+		require.NoError(t, err)
+		anys[i] = any
+	}
+
+	return anys
+}
@@ -0,0 +1,68 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	sdkerrors "github.com/Finschia/finschia-sdk/types/errors"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+type msgServer struct {
+	keeper Keeper
+}
+
+// NewMsgServerImpl returns an implementation of the blocklist MsgServer interface
+// for the provided Keeper.
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &msgServer{
+		keeper: keeper,
+	}
+}
+
+var _ types.MsgServer = msgServer{}
+
+func (s msgServer) BlockAddress(c context.Context, req *types.MsgBlockAddress) (*types.MsgBlockAddressResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	if err := s.validateAuthority(req.Authority); err != nil {
+		return nil, err
+	}
+
+	addr, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keeper.BlockAddress(ctx, addr); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgBlockAddressResponse{}, nil
+}
+
+func (s msgServer) UnblockAddress(c context.Context, req *types.MsgUnblockAddress) (*types.MsgUnblockAddressResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	if err := s.validateAuthority(req.Authority); err != nil {
+		return nil, err
+	}
+
+	addr, err := sdk.AccAddressFromBech32(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keeper.UnblockAddress(ctx, addr); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgUnblockAddressResponse{}, nil
+}
+
+func (s msgServer) validateAuthority(authority string) error {
+	if authority != s.keeper.GetAuthority() {
+		return sdkerrors.ErrUnauthorized.Wrapf("invalid authority; expected %s, got %s", s.keeper.GetAuthority(), authority)
+	}
+
+	return nil
+}
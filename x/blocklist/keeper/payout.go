@@ -0,0 +1,88 @@
+package keeper
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+	distrkeeper "github.com/Finschia/finschia-sdk/x/distribution/keeper"
+	distrtypes "github.com/Finschia/finschia-sdk/x/distribution/types"
+	govtypes "github.com/Finschia/finschia-sdk/x/gov/types"
+	stakingtypes "github.com/Finschia/finschia-sdk/x/staking/types"
+)
+
+var _ stakingtypes.StakingHooks = DistrHooks{}
+
+// DistrHooks wraps the staking hooks of distribution, so that the commission
+// of a removed validator whose withdraw address is not allowed to receive
+// funds goes to the community pool, since distribution panics on a failure to
+// pay it at the end of a block.
+type DistrHooks struct {
+	distrkeeper.Hooks
+
+	distr     distrkeeper.Keeper
+	blocklist Keeper
+}
+
+// NewDistrHooks returns the staking hooks of distribution respecting the
+// blocklist.
+func NewDistrHooks(distr distrkeeper.Keeper, blocklist Keeper) DistrHooks {
+	return DistrHooks{
+		Hooks:     distr.Hooks(),
+		distr:     distr,
+		blocklist: blocklist,
+	}
+}
+
+// AfterValidatorRemoved performs clean up after a validator is removed. The
+// commission not to be paid is dropped, which leaves it in the outstanding
+// rewards moved to the community pool.
+func (h DistrHooks) AfterValidatorRemoved(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress) {
+	withdrawAddr := h.distr.GetDelegatorWithdrawAddr(ctx, sdk.AccAddress(valAddr))
+	if h.blocklist.BlockedAddr(withdrawAddr) {
+		commission := h.distr.GetValidatorAccumulatedCommission(ctx, valAddr).Commission
+		if !commission.IsZero() {
+			h.blocklist.Logger(ctx).Info("commission of removed validator sent to community pool", "validator", valAddr.String(), "withdraw_address", withdrawAddr.String(), "commission", commission.String())
+			h.distr.SetValidatorAccumulatedCommission(ctx, valAddr, distrtypes.InitialValidatorAccumulatedCommission())
+		}
+	}
+
+	h.Hooks.AfterValidatorRemoved(ctx, consAddr, valAddr)
+}
+
+var _ govtypes.BankKeeper = GovBankKeeper{}
+
+// GovBankKeeper is the bank keeper of gov, refunding the deposits of the
+// depositors not allowed to receive funds to the community pool, since gov
+// panics on a failure to refund them at the end of a block.
+type GovBankKeeper struct {
+	BankKeeper
+
+	distr distrkeeper.Keeper
+}
+
+// NewGovBankKeeper returns the bank keeper of gov respecting the blocklist.
+func NewGovBankKeeper(bk BankKeeper, distr distrkeeper.Keeper) GovBankKeeper {
+	return GovBankKeeper{
+		BankKeeper: bk,
+		distr:      distr,
+	}
+}
+
+// SendCoinsFromModuleToAccount transfers coins from a ModuleAccount to an
+// AccAddress, or to the community pool if the recipient is not allowed to
+// receive funds.
+func (k GovBankKeeper) SendCoinsFromModuleToAccount(
+	ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins,
+) error {
+	if !k.blocklist.BlockedAddr(recipientAddr) {
+		return k.BankKeeper.SendCoinsFromModuleToAccount(ctx, senderModule, recipientAddr, amt)
+	}
+
+	if err := k.Keeper.SendCoinsFromModuleToModule(ctx, senderModule, distrtypes.ModuleName, amt); err != nil {
+		return err
+	}
+	feePool := k.distr.GetFeePool(ctx)
+	feePool.CommunityPool = feePool.CommunityPool.Add(sdk.NewDecCoinsFromCoins(amt...)...)
+	k.distr.SetFeePool(ctx, feePool)
+	k.blocklist.Logger(ctx).Info("refund sent to community pool", "module", senderModule, "recipient", recipientAddr.String(), "amount", amt.String())
+
+	return nil
+}
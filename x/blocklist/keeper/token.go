@@ -0,0 +1,72 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/Finschia/finschia-sdk/x/token"
+)
+
+// tokenMsgServer serves the messages of token, rejecting the transfers from
+// and to the addresses in the blocklist.
+type tokenMsgServer struct {
+	token.MsgServer
+
+	blocklist Keeper
+}
+
+// NewTokenMsgServer returns a token MsgServer respecting the blocklist over
+// the given one.
+func NewTokenMsgServer(server token.MsgServer, blocklist Keeper) token.MsgServer {
+	return tokenMsgServer{
+		MsgServer: server,
+		blocklist: blocklist,
+	}
+}
+
+func (s tokenMsgServer) Send(c context.Context, req *token.MsgSend) (*token.MsgSendResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.Send(c, req)
+}
+
+func (s tokenMsgServer) OperatorSend(c context.Context, req *token.MsgOperatorSend) (*token.MsgOperatorSendResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.OperatorSend(c, req)
+}
+
+func (s tokenMsgServer) Issue(c context.Context, req *token.MsgIssue) (*token.MsgIssueResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.Issue(c, req)
+}
+
+func (s tokenMsgServer) Mint(c context.Context, req *token.MsgMint) (*token.MsgMintResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.Mint(c, req)
+}
+
+func (s tokenMsgServer) Burn(c context.Context, req *token.MsgBurn) (*token.MsgBurnResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.Burn(c, req)
+}
+
+func (s tokenMsgServer) OperatorBurn(c context.Context, req *token.MsgOperatorBurn) (*token.MsgOperatorBurnResponse, error) {
+	if err := s.blocklist.CheckMsg(req); err != nil {
+		return nil, err
+	}
+
+	return s.MsgServer.OperatorBurn(c, req)
+}
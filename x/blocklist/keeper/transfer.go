@@ -0,0 +1,86 @@
+package keeper
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+	banktypes "github.com/Finschia/finschia-sdk/x/bank/types"
+	"github.com/Finschia/finschia-sdk/x/collection"
+	"github.com/Finschia/finschia-sdk/x/token"
+	ibctransfertypes "github.com/Finschia/ibc-go/v3/modules/apps/transfer/types"
+
+	"github.com/Finschia/finschia/x/blocklist/types"
+)
+
+// CheckMsg returns an error if the message transfers the funds from or to an
+// address in the blocklist, as of the last Refresh.
+func (k Keeper) CheckMsg(msg sdk.Msg) error {
+	for _, addr := range transferAddrs(msg) {
+		if k.IsBlockedAddress(addr) {
+			return types.ErrBlockedAddress.Wrapf("%s is not allowed to send nor receive funds", addr)
+		}
+	}
+
+	return nil
+}
+
+// transferAddrs returns the addresses whose funds the message transfers,
+// including the operators transferring them.
+func transferAddrs(msg sdk.Msg) []string {
+	switch msg := msg.(type) {
+	// bank
+	case *banktypes.MsgSend:
+		return []string{msg.FromAddress, msg.ToAddress}
+	case *banktypes.MsgMultiSend:
+		addrs := make([]string, 0, len(msg.Inputs)+len(msg.Outputs))
+		for _, in := range msg.Inputs {
+			addrs = append(addrs, in.Address)
+		}
+		for _, out := range msg.Outputs {
+			addrs = append(addrs, out.Address)
+		}
+		return addrs
+
+	// token
+	case *token.MsgSend:
+		return []string{msg.From, msg.To}
+	case *token.MsgOperatorSend:
+		return []string{msg.Operator, msg.From, msg.To}
+	case *token.MsgIssue:
+		return []string{msg.To}
+	case *token.MsgMint:
+		return []string{msg.To}
+	case *token.MsgBurn:
+		return []string{msg.From}
+	case *token.MsgOperatorBurn:
+		return []string{msg.Operator, msg.From}
+
+	// collection
+	case *collection.MsgSendFT:
+		return []string{msg.From, msg.To}
+	case *collection.MsgOperatorSendFT:
+		return []string{msg.Operator, msg.From, msg.To}
+	case *collection.MsgSendNFT:
+		return []string{msg.From, msg.To}
+	case *collection.MsgOperatorSendNFT:
+		return []string{msg.Operator, msg.From, msg.To}
+	case *collection.MsgIssueFT:
+		return []string{msg.To}
+	case *collection.MsgMintFT:
+		return []string{msg.To}
+	case *collection.MsgMintNFT:
+		return []string{msg.To}
+	case *collection.MsgBurnFT:
+		return []string{msg.From}
+	case *collection.MsgOperatorBurnFT:
+		return []string{msg.Operator, msg.From}
+	case *collection.MsgBurnNFT:
+		return []string{msg.From}
+	case *collection.MsgOperatorBurnNFT:
+		return []string{msg.Operator, msg.From}
+
+	// ibc transfer, whose receiver is an address of the counterparty chain
+	case *ibctransfertypes.MsgTransfer:
+		return []string{msg.Sender}
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package blocklist
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+	govtypes "github.com/Finschia/finschia-sdk/x/gov/types"
+
+	"github.com/Finschia/finschia/x/blocklist/keeper"
+)
+
+// NewParamChangeProposalHandler wraps the handler of the parameter change
+// proposals to emit the events of the addresses blocked or unblocked by the
+// proposals changing the params of blocklist.
+func NewParamChangeProposalHandler(handler govtypes.Handler, k keeper.Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) error {
+		prev := k.GetParams(ctx)
+		if err := handler(ctx, content); err != nil {
+			return err
+		}
+
+		k.EmitBlockEvents(ctx, prev)
+		return nil
+	}
+}
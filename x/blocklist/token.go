@@ -0,0 +1,36 @@
+package blocklist
+
+import (
+	"github.com/Finschia/finschia-sdk/codec"
+	"github.com/Finschia/finschia-sdk/types/module"
+	"github.com/Finschia/finschia-sdk/x/token"
+	tokenkeeper "github.com/Finschia/finschia-sdk/x/token/keeper"
+	tokenmodule "github.com/Finschia/finschia-sdk/x/token/module"
+
+	"github.com/Finschia/finschia/x/blocklist/keeper"
+)
+
+// TokenAppModule is the token module serving its messages by the msg server
+// respecting the blocklist.
+type TokenAppModule struct {
+	tokenmodule.AppModule
+
+	keeper    tokenkeeper.Keeper
+	blocklist keeper.Keeper
+}
+
+// NewTokenAppModule creates a new token AppModule respecting the blocklist.
+func NewTokenAppModule(cdc codec.Codec, keeper tokenkeeper.Keeper, blocklist keeper.Keeper) TokenAppModule {
+	return TokenAppModule{
+		AppModule: tokenmodule.NewAppModule(cdc, keeper),
+		keeper:    keeper,
+		blocklist: blocklist,
+	}
+}
+
+// RegisterServices registers the token services as the token module does, except
+// that the messages are served by the msg server respecting the blocklist.
+func (am TokenAppModule) RegisterServices(cfg module.Configurator) {
+	token.RegisterMsgServer(cfg.MsgServer(), keeper.NewTokenMsgServer(tokenkeeper.NewMsgServer(am.keeper), am.blocklist))
+	token.RegisterQueryServer(cfg.QueryServer(), tokenkeeper.NewQueryServer(am.keeper))
+}
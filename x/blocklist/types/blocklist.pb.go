@@ -0,0 +1,687 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: finschia/blocklist/v1/blocklist.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// Params defines the parameters of the blocklist module.
+type Params struct {
+	// receivable_module_accounts are the names of the module accounts allowed
+	// to receive funds. Any other module account of the app is blocked.
+	ReceivableModuleAccounts []string `protobuf:"bytes,1,rep,name=receivable_module_accounts,json=receivableModuleAccounts,proto3" json:"receivable_module_accounts,omitempty" yaml:"receivable_module_accounts"`
+	// blocked_addresses are the addresses not allowed to send nor receive the
+	// coins of bank, the tokens of token and collection, and the coins over IBC
+	// transfer, in the canonical form of bech32.
+	BlockedAddresses []string `protobuf:"bytes,2,rep,name=blocked_addresses,json=blockedAddresses,proto3" json:"blocked_addresses,omitempty" yaml:"blocked_addresses"`
+}
+
+func (m *Params) Reset()      { *m = Params{} }
+func (*Params) ProtoMessage() {}
+func (*Params) Descriptor() ([]byte, []int) {
+	return fileDescriptor_336e1ee9dbb68dac, []int{0}
+}
+func (m *Params) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Params) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Params.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Params) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Params.Merge(m, src)
+}
+func (m *Params) XXX_Size() int {
+	return m.Size()
+}
+func (m *Params) XXX_DiscardUnknown() {
+	xxx_messageInfo_Params.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Params proto.InternalMessageInfo
+
+// ModuleAccount describes whether a module account of the app may receive
+// funds.
+type ModuleAccount struct {
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address    string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Receivable bool   `protobuf:"varint,3,opt,name=receivable,proto3" json:"receivable,omitempty"`
+}
+
+func (m *ModuleAccount) Reset()         { *m = ModuleAccount{} }
+func (m *ModuleAccount) String() string { return proto.CompactTextString(m) }
+func (*ModuleAccount) ProtoMessage()    {}
+func (*ModuleAccount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_336e1ee9dbb68dac, []int{1}
+}
+func (m *ModuleAccount) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ModuleAccount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ModuleAccount.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ModuleAccount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ModuleAccount.Merge(m, src)
+}
+func (m *ModuleAccount) XXX_Size() int {
+	return m.Size()
+}
+func (m *ModuleAccount) XXX_DiscardUnknown() {
+	xxx_messageInfo_ModuleAccount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ModuleAccount proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*Params)(nil), "finschia.blocklist.v1.Params")
+	proto.RegisterType((*ModuleAccount)(nil), "finschia.blocklist.v1.ModuleAccount")
+}
+
+func init() {
+	proto.RegisterFile("finschia/blocklist/v1/blocklist.proto", fileDescriptor_336e1ee9dbb68dac)
+}
+
+var fileDescriptor_336e1ee9dbb68dac = []byte{
+	// 314 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xb1, 0x4a, 0xc3, 0x40,
+	0x1c, 0xc6, 0x73, 0x6d, 0xa9, 0xf6, 0x40, 0xd0, 0x43, 0xe1, 0x28, 0x72, 0xad, 0x81, 0x42, 0xa7,
+	0x84, 0xe2, 0xd6, 0xad, 0x1d, 0x04, 0x07, 0xa1, 0x64, 0x14, 0x24, 0x5c, 0x2e, 0x67, 0x1a, 0x4c,
+	0x72, 0x25, 0x97, 0x04, 0xfb, 0x16, 0x8e, 0x8e, 0x8e, 0x3e, 0x85, 0x73, 0xc7, 0x8e, 0x4e, 0x45,
+	0x93, 0x37, 0xe8, 0x13, 0x88, 0xd7, 0xc4, 0x54, 0xc4, 0xed, 0xff, 0xff, 0xbe, 0x1f, 0xdf, 0xfd,
+	0xb9, 0x0f, 0x0e, 0xee, 0xfd, 0x48, 0xb2, 0xb9, 0x4f, 0x4d, 0x27, 0x10, 0xec, 0x21, 0xf0, 0x65,
+	0x62, 0x66, 0xa3, 0x7a, 0x31, 0x16, 0xb1, 0x48, 0x04, 0x3a, 0xab, 0x30, 0xa3, 0x76, 0xb2, 0x51,
+	0xf7, 0xd4, 0x13, 0x9e, 0x50, 0x84, 0xf9, 0x3d, 0xed, 0x60, 0xfd, 0x0d, 0xc0, 0xf6, 0x8c, 0xc6,
+	0x34, 0x94, 0x88, 0xc1, 0x6e, 0xcc, 0x19, 0xf7, 0x33, 0xea, 0x04, 0xdc, 0x0e, 0x85, 0x9b, 0x06,
+	0xdc, 0xa6, 0x8c, 0x89, 0x34, 0x4a, 0x24, 0x06, 0xfd, 0xe6, 0xb0, 0x33, 0x1d, 0x6c, 0x37, 0xbd,
+	0x8b, 0x25, 0x0d, 0x83, 0xb1, 0xfe, 0x3f, 0xab, 0x5b, 0xb8, 0x36, 0x6f, 0x94, 0x37, 0x29, 0x2d,
+	0x74, 0x0d, 0x4f, 0xd4, 0x55, 0xdc, 0xb5, 0xa9, 0xeb, 0xc6, 0x5c, 0x4a, 0x2e, 0x71, 0x43, 0x65,
+	0x9f, 0x6f, 0x37, 0x3d, 0xbc, 0xcb, 0xfe, 0x83, 0xe8, 0xd6, 0x71, 0xa9, 0x4d, 0x2a, 0x69, 0xdc,
+	0x7a, 0x7e, 0xe9, 0x69, 0xfa, 0x1d, 0x3c, 0xfa, 0xf5, 0x04, 0x42, 0xb0, 0x15, 0xd1, 0x90, 0x63,
+	0xd0, 0x07, 0xc3, 0x8e, 0xa5, 0x66, 0x84, 0xe1, 0x41, 0x19, 0x85, 0x1b, 0x4a, 0xae, 0x56, 0x44,
+	0x20, 0xac, 0x6f, 0xc5, 0xcd, 0x3e, 0x18, 0x1e, 0x5a, 0x7b, 0xca, 0x74, 0xb6, 0xfa, 0x24, 0xda,
+	0x6b, 0x4e, 0xc0, 0x2a, 0x27, 0x60, 0x9d, 0x13, 0xf0, 0x91, 0x13, 0xf0, 0x54, 0x10, 0x6d, 0x5d,
+	0x10, 0xed, 0xbd, 0x20, 0xda, 0xad, 0xe1, 0xf9, 0xc9, 0x3c, 0x75, 0x0c, 0x26, 0x42, 0xf3, 0xaa,
+	0x2a, 0xe8, 0xa7, 0xa9, 0xc7, 0xbd, 0xae, 0x92, 0xe5, 0x82, 0x4b, 0xa7, 0xad, 0x3e, 0xfe, 0xf2,
+	0x6b, 0x00, 0xce, 0xb4, 0x81, 0x5b, 0xce, 0x01, 0x00, 0x00,
+}
+
+func (this *Params) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Params)
+	if !ok {
+		that2, ok := that.(Params)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.ReceivableModuleAccounts) != len(that1.ReceivableModuleAccounts) {
+		return false
+	}
+	for i := range this.ReceivableModuleAccounts {
+		if this.ReceivableModuleAccounts[i] != that1.ReceivableModuleAccounts[i] {
+			return false
+		}
+	}
+	if len(this.BlockedAddresses) != len(that1.BlockedAddresses) {
+		return false
+	}
+	for i := range this.BlockedAddresses {
+		if this.BlockedAddresses[i] != that1.BlockedAddresses[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *ModuleAccount) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ModuleAccount)
+	if !ok {
+		that2, ok := that.(ModuleAccount)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Address != that1.Address {
+		return false
+	}
+	if this.Receivable != that1.Receivable {
+		return false
+	}
+	return true
+}
+func (m *Params) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Params) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.BlockedAddresses) > 0 {
+		for iNdEx := len(m.BlockedAddresses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.BlockedAddresses[iNdEx])
+			copy(dAtA[i:], m.BlockedAddresses[iNdEx])
+			i = encodeVarintBlocklist(dAtA, i, uint64(len(m.BlockedAddresses[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.ReceivableModuleAccounts) > 0 {
+		for iNdEx := len(m.ReceivableModuleAccounts) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ReceivableModuleAccounts[iNdEx])
+			copy(dAtA[i:], m.ReceivableModuleAccounts[iNdEx])
+			i = encodeVarintBlocklist(dAtA, i, uint64(len(m.ReceivableModuleAccounts[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ModuleAccount) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ModuleAccount) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ModuleAccount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Receivable {
+		i--
+		if m.Receivable {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintBlocklist(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintBlocklist(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintBlocklist(dAtA []byte, offset int, v uint64) int {
+	offset -= sovBlocklist(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Params) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.ReceivableModuleAccounts) > 0 {
+		for _, s := range m.ReceivableModuleAccounts {
+			l = len(s)
+			n += 1 + l + sovBlocklist(uint64(l))
+		}
+	}
+	if len(m.BlockedAddresses) > 0 {
+		for _, s := range m.BlockedAddresses {
+			l = len(s)
+			n += 1 + l + sovBlocklist(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ModuleAccount) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovBlocklist(uint64(l))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovBlocklist(uint64(l))
+	}
+	if m.Receivable {
+		n += 2
+	}
+	return n
+}
+
+func sovBlocklist(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozBlocklist(x uint64) (n int) {
+	return sovBlocklist(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Params) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBlocklist
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Params: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Params: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReceivableModuleAccounts", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReceivableModuleAccounts = append(m.ReceivableModuleAccounts, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockedAddresses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockedAddresses = append(m.BlockedAddresses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBlocklist(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ModuleAccount) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBlocklist
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ModuleAccount: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ModuleAccount: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Receivable", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Receivable = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBlocklist(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthBlocklist
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipBlocklist(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowBlocklist
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowBlocklist
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthBlocklist
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupBlocklist
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthBlocklist
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthBlocklist        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowBlocklist          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupBlocklist = fmt.Errorf("proto: unexpected end of group")
+)
@@ -0,0 +1,12 @@
+package types
+
+import (
+	sdkerrors "github.com/Finschia/finschia-sdk/types/errors"
+)
+
+// x/blocklist module sentinel errors
+var (
+	ErrBlockedAddress = sdkerrors.Register(ModuleName, 2, "blocked address")
+	ErrAlreadyBlocked = sdkerrors.Register(ModuleName, 3, "address already blocked")
+	ErrNotBlocked     = sdkerrors.Register(ModuleName, 4, "address not blocked")
+)
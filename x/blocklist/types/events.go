@@ -0,0 +1,9 @@
+package types
+
+// blocklist module event types
+const (
+	EventTypeBlockAddress   = "block_address"
+	EventTypeUnblockAddress = "unblock_address"
+
+	AttributeKeyAddress = "address"
+)
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"fmt"
+)
+
+// DefaultGenesisState returns the default genesis state of the blocklist module.
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params: DefaultParams(),
+	}
+}
+
+// ValidateGenesis validates the genesis state of the blocklist module.
+func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return fmt.Errorf("invalid %s params: %w", ModuleName, err)
+	}
+
+	return nil
+}
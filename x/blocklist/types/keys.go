@@ -0,0 +1,6 @@
+package types
+
+const (
+	// ModuleName is the name of the blocklist module
+	ModuleName = "blocklist"
+)
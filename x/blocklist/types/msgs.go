@@ -0,0 +1,74 @@
+package types
+
+import (
+	sdk "github.com/Finschia/finschia-sdk/types"
+	sdkerrors "github.com/Finschia/finschia-sdk/types/errors"
+)
+
+var _ sdk.Msg = (*MsgBlockAddress)(nil)
+
+// ValidateBasic implements Msg.
+func (m MsgBlockAddress) ValidateBasic() error {
+	return validateAuthorityAndAddress(m.Authority, m.Address)
+}
+
+// GetSigners implements Msg.
+func (m MsgBlockAddress) GetSigners() []sdk.AccAddress {
+	signer := sdk.MustAccAddressFromBech32(m.Authority)
+	return []sdk.AccAddress{signer}
+}
+
+// Type implements the LegacyMsg.Type method.
+func (m MsgBlockAddress) Type() string {
+	return sdk.MsgTypeURL(&m)
+}
+
+// Route implements the LegacyMsg.Route method.
+func (m MsgBlockAddress) Route() string {
+	return sdk.MsgTypeURL(&m)
+}
+
+// GetSignBytes implements the LegacyMsg.GetSignBytes method.
+func (m MsgBlockAddress) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&m))
+}
+
+var _ sdk.Msg = (*MsgUnblockAddress)(nil)
+
+// ValidateBasic implements Msg.
+func (m MsgUnblockAddress) ValidateBasic() error {
+	return validateAuthorityAndAddress(m.Authority, m.Address)
+}
+
+// GetSigners implements Msg.
+func (m MsgUnblockAddress) GetSigners() []sdk.AccAddress {
+	signer := sdk.MustAccAddressFromBech32(m.Authority)
+	return []sdk.AccAddress{signer}
+}
+
+// Type implements the LegacyMsg.Type method.
+func (m MsgUnblockAddress) Type() string {
+	return sdk.MsgTypeURL(&m)
+}
+
+// Route implements the LegacyMsg.Route method.
+func (m MsgUnblockAddress) Route() string {
+	return sdk.MsgTypeURL(&m)
+}
+
+// GetSignBytes implements the LegacyMsg.GetSignBytes method.
+func (m MsgUnblockAddress) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&m))
+}
+
+func validateAuthorityAndAddress(authority, address string) error {
+	if _, err := sdk.AccAddressFromBech32(authority); err != nil {
+		return sdkerrors.ErrInvalidAddress.Wrapf("invalid authority address: %s", authority)
+	}
+
+	if _, err := sdk.AccAddressFromBech32(address); err != nil {
+		return sdkerrors.ErrInvalidAddress.Wrapf("invalid address: %s", address)
+	}
+
+	return nil
+}
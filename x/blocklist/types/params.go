@@ -0,0 +1,103 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	sdk "github.com/Finschia/finschia-sdk/types"
+	paramtypes "github.com/Finschia/finschia-sdk/x/params/types"
+)
+
+// parameter store keys
+var (
+	// KeyReceivableModuleAccounts is the store key of the module accounts allowed to receive funds
+	KeyReceivableModuleAccounts = []byte("ReceivableModuleAccounts")
+
+	// KeyBlockedAddresses is the store key of the addresses not allowed to send nor receive funds
+	KeyBlockedAddresses = []byte("BlockedAddresses")
+)
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the key table of the blocklist module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns the default parameters, which block every module account.
+func DefaultParams() Params {
+	return Params{
+		ReceivableModuleAccounts: []string{},
+		BlockedAddresses:         []string{},
+	}
+}
+
+// String implements the Stringer interface.
+func (p Params) String() string {
+	out, _ := yaml.Marshal(p)
+	return string(out)
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyReceivableModuleAccounts, &p.ReceivableModuleAccounts, validateModuleAccountNames),
+		paramtypes.NewParamSetPair(KeyBlockedAddresses, &p.BlockedAddresses, validateAddresses),
+	}
+}
+
+// Validate validates the parameters
+func (p Params) Validate() error {
+	if err := validateModuleAccountNames(p.ReceivableModuleAccounts); err != nil {
+		return err
+	}
+
+	return validateAddresses(p.BlockedAddresses)
+}
+
+func validateModuleAccountNames(i interface{}) error {
+	names, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("empty module account name")
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate module account name: %s", name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+func validateAddresses(i interface{}) error {
+	addrs, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		acc, err := sdk.AccAddressFromBech32(addr)
+		if err != nil {
+			return fmt.Errorf("invalid address %s: %w", addr, err)
+		}
+		// the addresses are matched as they are
+		if acc.String() != addr {
+			return fmt.Errorf("address %s not in the canonical form %s", addr, acc)
+		}
+		if seen[addr] {
+			return fmt.Errorf("duplicate address: %s", addr)
+		}
+		seen[addr] = true
+	}
+
+	return nil
+}